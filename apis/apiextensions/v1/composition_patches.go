@@ -18,7 +18,9 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -51,6 +53,72 @@ const (
 	FromFieldPathPolicyRequired FromFieldPathPolicy = "Required"
 )
 
+// A ToFieldPathPolicy determines how to patch to a field path.
+type ToFieldPathPolicy string
+
+// ToFieldPath patch policies.
+const (
+	ToFieldPathPolicyReplace           ToFieldPathPolicy = "Replace"
+	ToFieldPathPolicyMergeObjects      ToFieldPathPolicy = "MergeObjects"
+	ToFieldPathPolicyAppendArray       ToFieldPathPolicy = "AppendArray"
+	ToFieldPathPolicyAppendUniqueArray ToFieldPathPolicy = "AppendUniqueArray"
+)
+
+// A PatchErrorAction determines what happens when a patch fails.
+type PatchErrorAction string
+
+// Patch error actions.
+const (
+	PatchErrorActionFail    PatchErrorAction = "Fail"
+	PatchErrorActionSkip    PatchErrorAction = "Skip"
+	PatchErrorActionDefault PatchErrorAction = "Default"
+)
+
+// A PatchErrorPolicy determines what happens when a patch fails, for example
+// because one of its transforms returns an error.
+type PatchErrorPolicy struct {
+	// Action to take when the patch fails. The default is 'Fail', which means
+	// a composed resource whose patches fail isn't applied, and that a
+	// composite resource whose patches fail isn't reconciled successfully.
+	// Use 'Skip' to skip the patch, or 'Default' to patch value to the
+	// toFieldPath instead. Skipped and defaulted patches are reported as
+	// Warning events, and in the PatchesApplied condition of the composite
+	// resource.
+	// +kubebuilder:validation:Enum=Fail;Skip;Default
+	// +kubebuilder:default=Fail
+	Action PatchErrorAction `json:"action"`
+
+	// Value to patch to the toFieldPath when action is 'Default'.
+	// +optional
+	Value *extv1.JSON `json:"value,omitempty"`
+}
+
+// GetAction returns the PatchErrorAction of this PatchErrorPolicy, defaulting
+// to PatchErrorActionFail if not specified.
+func (ep *PatchErrorPolicy) GetAction() PatchErrorAction {
+	if ep == nil || ep.Action == "" {
+		return PatchErrorActionFail
+	}
+	return ep.Action
+}
+
+// Validate the PatchErrorPolicy object.
+func (ep *PatchErrorPolicy) Validate() *field.Error {
+	if ep == nil {
+		return nil
+	}
+	switch ep.GetAction() {
+	case PatchErrorActionFail, PatchErrorActionSkip:
+	case PatchErrorActionDefault:
+		if ep.Value == nil {
+			return field.Required(field.NewPath("value"), "value is required when action is Default")
+		}
+	default:
+		return field.Invalid(field.NewPath("action"), ep.Action, "unknown patch error action")
+	}
+	return nil
+}
+
 // A PatchPolicy configures the specifics of patching behaviour.
 type PatchPolicy struct {
 	// FromFieldPath specifies how to patch from a field path. The default is
@@ -60,7 +128,30 @@ type PatchPolicy struct {
 	// +kubebuilder:validation:Enum=Optional;Required
 	// +optional
 	FromFieldPath *FromFieldPathPolicy `json:"fromFieldPath,omitempty"`
-	MergeOptions  *xpv1.MergeOptions   `json:"mergeOptions,omitempty"`
+
+	// ToFieldPath specifies how to patch to a field path. The default is
+	// 'Replace', which means the patch replaces the value of the specified
+	// toFieldPath. Use 'MergeObjects' to merge the fields of an object into
+	// the existing object, overwriting existing fields of the same name. Use
+	// 'AppendArray' to append a value, or the elements of an array, to the
+	// existing array. Use 'AppendUniqueArray' to do the same, but only append
+	// elements the existing array doesn't already contain. When patching to
+	// the composite resource the array is built from scratch each time it's
+	// reconciled, from the values of every composed resource that patches to
+	// it. Can't be used with mergeOptions.
+	// +kubebuilder:validation:Enum=Replace;MergeObjects;AppendArray;AppendUniqueArray
+	// +optional
+	ToFieldPath *ToFieldPathPolicy `json:"toFieldPath,omitempty"`
+
+	// MergeOptions specifies merge options on a field path. Deprecated in
+	// favor of toFieldPath.
+	// +optional
+	MergeOptions *xpv1.MergeOptions `json:"mergeOptions,omitempty"`
+
+	// OnError determines what happens when the patch fails. Overrides the
+	// onPatchError policy of the composed resource template.
+	// +optional
+	OnError *PatchErrorPolicy `json:"onError,omitempty"`
 }
 
 // GetFromFieldPathPolicy returns the FromFieldPathPolicy for this PatchPolicy, defaulting to FromFieldPathPolicyOptional if not specified.
@@ -71,6 +162,36 @@ func (pp *PatchPolicy) GetFromFieldPathPolicy() FromFieldPathPolicy {
 	return *pp.FromFieldPath
 }
 
+// GetToFieldPathPolicy returns the ToFieldPathPolicy for this PatchPolicy, defaulting to ToFieldPathPolicyReplace if not specified.
+func (pp *PatchPolicy) GetToFieldPathPolicy() ToFieldPathPolicy {
+	if pp == nil || pp.ToFieldPath == nil {
+		return ToFieldPathPolicyReplace
+	}
+	return *pp.ToFieldPath
+}
+
+// Validate the PatchPolicy object.
+func (pp *PatchPolicy) Validate() *field.Error {
+	if pp == nil {
+		return nil
+	}
+	if err := pp.OnError.Validate(); err != nil {
+		return verrors.WrapFieldError(err, field.NewPath("onError"))
+	}
+	if pp.ToFieldPath == nil {
+		return nil
+	}
+	switch *pp.ToFieldPath {
+	case ToFieldPathPolicyReplace, ToFieldPathPolicyMergeObjects, ToFieldPathPolicyAppendArray, ToFieldPathPolicyAppendUniqueArray:
+	default:
+		return field.Invalid(field.NewPath("toFieldPath"), *pp.ToFieldPath, "unknown toFieldPath policy")
+	}
+	if pp.MergeOptions != nil {
+		return field.Forbidden(field.NewPath("mergeOptions"), "mergeOptions cannot be used with a toFieldPath policy")
+	}
+	return nil
+}
+
 // Patch objects are applied between composite and composed resources. Their
 // behaviour depends on the Type selected. The default Type,
 // FromCompositeFieldPath, copies a value from the composite resource to
@@ -86,6 +207,8 @@ type Patch struct {
 	// FromFieldPath is the path of the field on the resource whose value is
 	// to be used as input. Required when type is FromCompositeFieldPath,
 	// FromEnvironmentFieldPath, ToCompositeFieldPath, ToEnvironmentFieldPath.
+	// The path may contain [*] wildcards, e.g. spec.subnets[*].id, in which
+	// case each matching field is transformed and patched individually.
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
@@ -96,7 +219,10 @@ type Patch struct {
 
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. If fromFieldPath contains
+	// wildcards, toFieldPath must contain either the same number of wildcards,
+	// which are replaced by the array indices fromFieldPath matched, or none,
+	// in which case the values are patched to toFieldPath as an array.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
@@ -145,6 +271,9 @@ func (p *Patch) Validate() *field.Error {
 		if p.FromFieldPath == nil {
 			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
 		}
+		if from, to := strings.Count(*p.FromFieldPath, "[*]"), strings.Count(p.GetToFieldPath(), "[*]"); from > 0 && to > 0 && from != to {
+			return field.Invalid(field.NewPath("toFieldPath"), p.GetToFieldPath(), "toFieldPath must contain either no wildcards or as many wildcards as fromFieldPath")
+		}
 	case PatchTypePatchSet:
 		if p.PatchSetName == nil {
 			return field.Required(field.NewPath("patchSetName"), fmt.Sprintf("patchSetName must be set for patch type %s", p.Type))
@@ -165,6 +294,9 @@ func (p *Patch) Validate() *field.Error {
 			return verrors.WrapFieldError(err, field.NewPath("transforms").Index(i))
 		}
 	}
+	if err := p.Policy.Validate(); err != nil {
+		return verrors.WrapFieldError(err, field.NewPath("policy"))
+	}
 
 	return nil
 }
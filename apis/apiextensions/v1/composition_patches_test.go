@@ -23,6 +23,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
 func TestPatchValidate(t *testing.T) {
@@ -48,6 +50,83 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidFromCompositeFieldPathWithWildcards": {
+			reason: "FromCompositeFieldPath patch with as many wildcards in ToFieldPath as in FromFieldPath should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.subnets[*].id"),
+					ToFieldPath:   ptr.To("spec.forProvider.subnetIds[*]"),
+				},
+			},
+		},
+		"InvalidFromCompositeFieldPathWildcardCountMismatch": {
+			reason: "FromCompositeFieldPath patch with a different number of wildcards in ToFieldPath than in FromFieldPath should be invalid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.subnets[*].id"),
+					ToFieldPath:   ptr.To("spec.forProvider.subnets[*].ids[*]"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "toFieldPath",
+				},
+			},
+		},
+		"ValidToCompositeFieldPathWithToFieldPathPolicy": {
+			reason: "ToCompositeFieldPath patch with a known toFieldPath policy should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("status.atProvider.endpoints"),
+					ToFieldPath:   ptr.To("status.endpoints"),
+					Policy: &PatchPolicy{
+						ToFieldPath: ptr.To(ToFieldPathPolicyAppendUniqueArray),
+					},
+				},
+			},
+		},
+		"InvalidToFieldPathPolicyWithMergeOptions": {
+			reason: "A patch with both a toFieldPath policy and merge options should be invalid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("status.atProvider.endpoints"),
+					ToFieldPath:   ptr.To("status.endpoints"),
+					Policy: &PatchPolicy{
+						ToFieldPath:  ptr.To(ToFieldPathPolicyMergeObjects),
+						MergeOptions: &xpv1.MergeOptions{KeepMapValues: ptr.To(true)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "policy.mergeOptions",
+				},
+			},
+		},
+		"InvalidDefaultPatchErrorPolicyWithoutValue": {
+			reason: "A patch whose error policy defaults a value but doesn't specify one should be invalid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.forProvider.foo"),
+					Policy: &PatchPolicy{
+						OnError: &PatchErrorPolicy{Action: PatchErrorActionDefault},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "policy.onError.value",
+				},
+			},
+		},
 		"FromCompositeFieldPathWithInvalidTransforms": {
 			reason: "FromCompositeFieldPath with invalid transforms should return error",
 			args: args{
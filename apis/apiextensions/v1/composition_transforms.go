@@ -18,14 +18,20 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"time"
 
+	"github.com/google/cel-go/cel"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	verrors "github.com/crossplane/crossplane/internal/validation/errors"
+	"github.com/crossplane/crossplane/internal/xcel"
+	"github.com/crossplane/crossplane/internal/xtemplate"
+	"github.com/crossplane/crossplane/internal/xtime"
 )
 
 // TransformType is type of the transform function to be chosen.
@@ -35,11 +41,14 @@ type TransformType string
 const (
 	ErrFmtConvertFormatPairNotSupported = "conversion from %s to %s is not supported with format %s"
 
-	TransformTypeMap     TransformType = "map"
-	TransformTypeMatch   TransformType = "match"
-	TransformTypeMath    TransformType = "math"
-	TransformTypeString  TransformType = "string"
-	TransformTypeConvert TransformType = "convert"
+	TransformTypeMap        TransformType = "map"
+	TransformTypeMatch      TransformType = "match"
+	TransformTypeMath       TransformType = "math"
+	TransformTypeString     TransformType = "string"
+	TransformTypeConvert    TransformType = "convert"
+	TransformTypeCEL        TransformType = "cel"
+	TransformTypeTime       TransformType = "time"
+	TransformTypeCollection TransformType = "collection"
 )
 
 // Transform is a unit of process whose input is transformed into an output with
@@ -47,7 +56,7 @@ const (
 type Transform struct {
 
 	// Type of the transform to be run.
-	// +kubebuilder:validation:Enum=map;match;math;string;convert
+	// +kubebuilder:validation:Enum=map;match;math;string;convert;cel;time;collection
 	Type TransformType `json:"type"`
 
 	// Math is used to transform the input via mathematical operations such as
@@ -71,6 +80,21 @@ type Transform struct {
 	// Convert is used to cast the input into the given output type.
 	// +optional
 	Convert *ConvertTransform `json:"convert,omitempty"`
+
+	// CEL is used to transform the input using a Common Expression Language
+	// (CEL) expression.
+	// +optional
+	CEL *CELTransform `json:"cel,omitempty"`
+
+	// Time is used to parse, format and offset times, and to convert
+	// durations.
+	// +optional
+	Time *TimeTransform `json:"time,omitempty"`
+
+	// Collection is used to manipulate arrays and objects, for example to
+	// join an array into a string or to get the keys of an object.
+	// +optional
+	Collection *CollectionTransform `json:"collection,omitempty"`
 }
 
 // Validate this Transform is valid.
@@ -105,6 +129,21 @@ func (t *Transform) Validate() *field.Error {
 		if err := t.Convert.Validate(); err != nil {
 			return verrors.WrapFieldError(err, field.NewPath("convert"))
 		}
+	case TransformTypeCEL:
+		if t.CEL == nil {
+			return field.Required(field.NewPath("cel"), "given transform type cel requires configuration")
+		}
+		return verrors.WrapFieldError(t.CEL.Validate(), field.NewPath("cel"))
+	case TransformTypeTime:
+		if t.Time == nil {
+			return field.Required(field.NewPath("time"), "given transform type time requires configuration")
+		}
+		return verrors.WrapFieldError(t.Time.Validate(), field.NewPath("time"))
+	case TransformTypeCollection:
+		if t.Collection == nil {
+			return field.Required(field.NewPath("collection"), "given transform type collection requires configuration")
+		}
+		return verrors.WrapFieldError(t.Collection.Validate(), field.NewPath("collection"))
 	default:
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), t.Type, "unknown transform type")
@@ -131,10 +170,28 @@ func (t *Transform) GetOutputType() (*TransformIOType, error) {
 		return nil, nil
 	case TransformTypeMath:
 		out = TransformIOTypeFloat64
+		if t.Math != nil {
+			out = t.Math.GetOutputType("")
+		}
 	case TransformTypeString:
 		out = TransformIOTypeString
 	case TransformTypeConvert:
 		out = t.Convert.ToType
+	case TransformTypeCEL:
+		if t.CEL == nil {
+			return nil, nil
+		}
+		return t.CEL.GetOutputType()
+	case TransformTypeTime:
+		if t.Time == nil {
+			return nil, nil
+		}
+		out = t.Time.GetOutputType()
+	case TransformTypeCollection:
+		if t.Collection == nil {
+			return nil, nil
+		}
+		out = t.Collection.GetOutputType()
 	default:
 		return nil, errors.Errorf("unable to get output type, unknown transform type: %s", t.Type)
 	}
@@ -149,14 +206,23 @@ const (
 	MathTransformTypeMultiply MathTransformType = "Multiply" // Default
 	MathTransformTypeClampMin MathTransformType = "ClampMin"
 	MathTransformTypeClampMax MathTransformType = "ClampMax"
+	MathTransformTypeAdd      MathTransformType = "Add"
+	MathTransformTypeSubtract MathTransformType = "Subtract"
+	MathTransformTypeDivide   MathTransformType = "Divide"
+	MathTransformTypeModulo   MathTransformType = "Modulo"
+	MathTransformTypeRound    MathTransformType = "Round"
+	MathTransformTypeCeil     MathTransformType = "Ceil"
+	MathTransformTypeFloor    MathTransformType = "Floor"
 )
 
 // MathTransform conducts mathematical operations on the input with the given
-// configuration in its properties.
+// configuration in its properties. Multiply, Add, Subtract and Modulo return
+// an integer given an integer input, and a float given a float input. Divide
+// always returns a float. Round, Ceil and Floor always return an integer.
 type MathTransform struct {
 	// Type of the math transform to be run.
 	// +optional
-	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax
+	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax;Add;Subtract;Divide;Modulo;Round;Ceil;Floor
 	// +kubebuilder:default=Multiply
 	Type MathTransformType `json:"type,omitempty"`
 
@@ -169,6 +235,24 @@ type MathTransform struct {
 	// ClampMax makes sure that the value is not bigger than the given value.
 	// +optional
 	ClampMax *int64 `json:"clampMax,omitempty"`
+	// Add the given value to the value.
+	// +optional
+	Add *int64 `json:"add,omitempty"`
+	// Subtract the given value from the value.
+	// +optional
+	Subtract *int64 `json:"subtract,omitempty"`
+	// Divide the value by the given value. Must not be zero.
+	// +optional
+	Divide *int64 `json:"divide,omitempty"`
+	// Modulo returns the remainder of dividing the value by the given value.
+	// Must not be zero.
+	// +optional
+	Modulo *int64 `json:"modulo,omitempty"`
+	// Multiple the value is rounded to by the Round, Ceil and Floor types,
+	// e.g. 10 to round to the nearest, next or previous multiple of 10.
+	// Defaults to 1. Must be positive.
+	// +optional
+	Multiple *int64 `json:"multiple,omitempty"`
 }
 
 // GetType returns the type of the math transform, returning the default if not specified.
@@ -179,7 +263,33 @@ func (m *MathTransform) GetType() MathTransformType {
 	return m.Type
 }
 
+// GetMultiple returns the multiple the Round, Ceil and Floor types round to,
+// returning the default if not specified.
+func (m *MathTransform) GetMultiple() int64 {
+	if m.Multiple == nil {
+		return 1
+	}
+	return *m.Multiple
+}
+
+// GetOutputType returns the output type of the math transform given the
+// supplied input type, which may be empty if it is not known.
+func (m *MathTransform) GetOutputType(input TransformIOType) TransformIOType {
+	switch m.GetType() {
+	case MathTransformTypeRound, MathTransformTypeCeil, MathTransformTypeFloor:
+		return TransformIOTypeInt64
+	case MathTransformTypeMultiply, MathTransformTypeAdd, MathTransformTypeSubtract, MathTransformTypeModulo, MathTransformTypeClampMin, MathTransformTypeClampMax:
+		if input == TransformIOTypeInt || input == TransformIOTypeInt64 {
+			return TransformIOTypeInt64
+		}
+	case MathTransformTypeDivide:
+	}
+	return TransformIOTypeFloat64
+}
+
 // Validate checks this MathTransform is valid.
+//
+//nolint:gocyclo // This is a long but simple/same-y switch.
 func (m *MathTransform) Validate() *field.Error {
 	switch m.GetType() {
 	case MathTransformTypeMultiply:
@@ -194,6 +304,32 @@ func (m *MathTransform) Validate() *field.Error {
 		if m.ClampMax == nil {
 			return field.Required(field.NewPath("clampMax"), "must specify a value if a clamp max math transform is specified")
 		}
+	case MathTransformTypeAdd:
+		if m.Add == nil {
+			return field.Required(field.NewPath("add"), "must specify a value if an add math transform is specified")
+		}
+	case MathTransformTypeSubtract:
+		if m.Subtract == nil {
+			return field.Required(field.NewPath("subtract"), "must specify a value if a subtract math transform is specified")
+		}
+	case MathTransformTypeDivide:
+		if m.Divide == nil {
+			return field.Required(field.NewPath("divide"), "must specify a value if a divide math transform is specified")
+		}
+		if *m.Divide == 0 {
+			return field.Invalid(field.NewPath("divide"), *m.Divide, "cannot divide by zero")
+		}
+	case MathTransformTypeModulo:
+		if m.Modulo == nil {
+			return field.Required(field.NewPath("modulo"), "must specify a value if a modulo math transform is specified")
+		}
+		if *m.Modulo == 0 {
+			return field.Invalid(field.NewPath("modulo"), *m.Modulo, "cannot divide by zero")
+		}
+	case MathTransformTypeRound, MathTransformTypeCeil, MathTransformTypeFloor:
+		if m.GetMultiple() <= 0 {
+			return field.Invalid(field.NewPath("multiple"), m.GetMultiple(), "multiple must be positive")
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), m.Type, "unknown math transform type")
 	}
@@ -339,6 +475,18 @@ const (
 	StringTransformTypeTrimPrefix StringTransformType = "TrimPrefix"
 	StringTransformTypeTrimSuffix StringTransformType = "TrimSuffix"
 	StringTransformTypeRegexp     StringTransformType = "Regexp"
+	StringTransformTypeTemplate   StringTransformType = "Template"
+)
+
+// Variables available to the template of a Template string transform.
+const (
+	// StringTransformTemplateVariableInput is the input value of the
+	// transform.
+	StringTransformTemplateVariableInput = "input"
+
+	// StringTransformTemplateVariableSource is the object the patch reads
+	// from, e.g. the composite resource or the environment.
+	StringTransformTemplateVariableSource = "source"
 )
 
 // StringConversionType converts a string.
@@ -362,7 +510,7 @@ type StringTransform struct {
 
 	// Type of the string transform to be run.
 	// +optional
-	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp
+	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Template
 	// +kubebuilder:default=Format
 	Type StringTransformType `json:"type,omitempty"`
 
@@ -388,6 +536,19 @@ type StringTransform struct {
 	// Extract a match from the input using a regular expression.
 	// +optional
 	Regexp *StringTransformRegexp `json:"regexp,omitempty"`
+
+	// Template renders a Go text/template. The template may reference the
+	// input of the transform as {{ .input }}, and the object the patch reads
+	// from - e.g. the composite resource or the environment - as
+	// {{ .source }}, e.g. "{{ .source.spec.user }}@{{ .input }}". Referencing
+	// a missing field is an error; use index and default to handle optional
+	// fields. The helpers lower, upper, trim, trimPrefix, trimSuffix,
+	// replace, contains, hasPrefix, hasSuffix, quote, split, join, hasKey,
+	// default, empty, coalesce, required, toJson, b64enc, b64dec, and
+	// sha256sum are available. See https://pkg.go.dev/text/template for
+	// details.
+	// +optional
+	Template *string `json:"template,omitempty"`
 }
 
 // Validate checks this StringTransform is valid.
@@ -417,6 +578,13 @@ func (s *StringTransform) Validate() *field.Error {
 		if _, err := regexp.Compile(s.Regexp.Match); err != nil {
 			return field.Invalid(field.NewPath("regexp", "match"), s.Regexp.Match, "invalid regexp")
 		}
+	case StringTransformTypeTemplate:
+		if s.Template == nil {
+			return field.Required(field.NewPath("template"), "template transform requires a template")
+		}
+		if _, err := xtemplate.Parse(*s.Template); err != nil {
+			return field.Invalid(field.NewPath("template"), *s.Template, err.Error())
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), s.Type, "unknown string transform type")
 	}
@@ -436,6 +604,196 @@ type StringTransformRegexp struct {
 	Group *int `json:"group,omitempty"`
 }
 
+// TimeTransformType is the type of a TimeTransform.
+type TimeTransformType string
+
+// Accepted TimeTransformTypes.
+const (
+	TimeTransformTypeFormat          TimeTransformType = "Format"
+	TimeTransformTypeAddDuration     TimeTransformType = "AddDuration"
+	TimeTransformTypeConvertDuration TimeTransformType = "ConvertDuration"
+)
+
+// A TimeTransform parses, formats and offsets times, and converts durations.
+type TimeTransform struct {
+	// Type of the time transform to be run. Format parses the input time
+	// using inputLayout and formats it using outputLayout. AddDuration does
+	// the same, but adds duration to the time before formatting it.
+	// ConvertDuration parses the input duration, e.g. "30d" or "1h30m", and
+	// returns it as an integer number of unit, truncated toward zero.
+	// +kubebuilder:validation:Enum=Format;AddDuration;ConvertDuration
+	Type TimeTransformType `json:"type"`
+
+	// InputLayout is the layout used to parse the input time. Either the name
+	// of a layout of the Go time package, e.g. RFC3339 or DateOnly, or a Go
+	// time layout, e.g. 2006-01-02. See https://pkg.go.dev/time#pkg-constants
+	// for details. Defaults to RFC3339.
+	// +optional
+	InputLayout *string `json:"inputLayout,omitempty"`
+
+	// OutputLayout is the layout used to format the output time. Accepts the
+	// same values as inputLayout. Defaults to RFC3339.
+	// +optional
+	OutputLayout *string `json:"outputLayout,omitempty"`
+
+	// Duration to add to the input time when type is AddDuration, e.g. "720h"
+	// or "30d". May be negative. Supports the units ns, us, ms, s, m, h, d
+	// (24h), and w (7d).
+	// +optional
+	Duration *string `json:"duration,omitempty"`
+
+	// Unit the input duration is converted to when type is ConvertDuration.
+	// +optional
+	// +kubebuilder:validation:Enum=ns;us;ms;s;m;h;d;w
+	Unit *string `json:"unit,omitempty"`
+}
+
+// GetInputLayout returns the InputLayout of this TimeTransform, defaulting to
+// RFC3339.
+func (t *TimeTransform) GetInputLayout() string {
+	if t.InputLayout == nil {
+		return time.RFC3339
+	}
+	return xtime.Layout(*t.InputLayout)
+}
+
+// GetOutputLayout returns the OutputLayout of this TimeTransform, defaulting
+// to RFC3339.
+func (t *TimeTransform) GetOutputLayout() string {
+	if t.OutputLayout == nil {
+		return time.RFC3339
+	}
+	return xtime.Layout(*t.OutputLayout)
+}
+
+// GetOutputType returns the output type of this TimeTransform.
+func (t *TimeTransform) GetOutputType() TransformIOType {
+	if t.Type == TimeTransformTypeConvertDuration {
+		return TransformIOTypeInt64
+	}
+	return TransformIOTypeString
+}
+
+// Validate checks this TimeTransform is valid.
+func (t *TimeTransform) Validate() *field.Error {
+	switch t.Type {
+	case TimeTransformTypeFormat:
+	case TimeTransformTypeAddDuration:
+		if t.Duration == nil {
+			return field.Required(field.NewPath("duration"), "time transform type AddDuration requires a duration")
+		}
+		if _, err := xtime.ParseDuration(*t.Duration); err != nil {
+			return field.Invalid(field.NewPath("duration"), *t.Duration, err.Error())
+		}
+	case TimeTransformTypeConvertDuration:
+		if t.Unit == nil {
+			return field.Required(field.NewPath("unit"), "time transform type ConvertDuration requires a unit")
+		}
+		if _, err := xtime.Unit(*t.Unit); err != nil {
+			return field.Invalid(field.NewPath("unit"), *t.Unit, err.Error())
+		}
+	default:
+		return field.Invalid(field.NewPath("type"), t.Type, "unknown time transform type")
+	}
+	if t.InputLayout != nil && *t.InputLayout == "" {
+		return field.Invalid(field.NewPath("inputLayout"), *t.InputLayout, "inputLayout must not be empty if specified")
+	}
+	if t.OutputLayout != nil && *t.OutputLayout == "" {
+		return field.Invalid(field.NewPath("outputLayout"), *t.OutputLayout, "outputLayout must not be empty if specified")
+	}
+	return nil
+}
+
+// CollectionTransformType is the type of a CollectionTransform.
+type CollectionTransformType string
+
+// Accepted CollectionTransformTypes.
+const (
+	CollectionTransformTypeJoin    CollectionTransformType = "Join"
+	CollectionTransformTypeSplit   CollectionTransformType = "Split"
+	CollectionTransformTypeFlatten CollectionTransformType = "Flatten"
+	CollectionTransformTypeSort    CollectionTransformType = "Sort"
+	CollectionTransformTypeUnique  CollectionTransformType = "Unique"
+	CollectionTransformTypeKeys    CollectionTransformType = "Keys"
+	CollectionTransformTypeValues  CollectionTransformType = "Values"
+	CollectionTransformTypePick    CollectionTransformType = "Pick"
+	CollectionTransformTypeOmit    CollectionTransformType = "Omit"
+)
+
+// A CollectionTransform manipulates arrays and objects.
+type CollectionTransform struct {
+	// Type of the collection transform to be run. Join joins an array of
+	// strings, numbers or booleans into a string separated by separator.
+	// Split splits a string into an array of strings at each separator.
+	// Flatten flattens an array of arrays by one level. Sort sorts an array
+	// of strings or numbers in ascending order. Unique removes duplicate
+	// elements from an array, keeping the first of each. Keys returns the
+	// keys of an object and Values its values, both ordered by key. Pick
+	// returns an object with only the supplied fields, and Omit one without
+	// them.
+	// +kubebuilder:validation:Enum=Join;Split;Flatten;Sort;Unique;Keys;Values;Pick;Omit
+	Type CollectionTransformType `json:"type"`
+
+	// Separator used by the Join and Split types. Defaults to ",".
+	// +optional
+	Separator *string `json:"separator,omitempty"`
+
+	// Fields picked or omitted by the Pick and Omit types.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// GetSeparator returns the Separator of this CollectionTransform, defaulting
+// to ",".
+func (t *CollectionTransform) GetSeparator() string {
+	if t.Separator == nil {
+		return ","
+	}
+	return *t.Separator
+}
+
+// GetInputType returns the input type required by this CollectionTransform.
+func (t *CollectionTransform) GetInputType() TransformIOType {
+	switch t.Type {
+	case CollectionTransformTypeSplit:
+		return TransformIOTypeString
+	case CollectionTransformTypeKeys, CollectionTransformTypeValues, CollectionTransformTypePick, CollectionTransformTypeOmit:
+		return TransformIOTypeObject
+	case CollectionTransformTypeJoin, CollectionTransformTypeFlatten, CollectionTransformTypeSort, CollectionTransformTypeUnique:
+	}
+	return TransformIOTypeArray
+}
+
+// GetOutputType returns the output type of this CollectionTransform.
+func (t *CollectionTransform) GetOutputType() TransformIOType {
+	switch t.Type {
+	case CollectionTransformTypeJoin:
+		return TransformIOTypeString
+	case CollectionTransformTypePick, CollectionTransformTypeOmit:
+		return TransformIOTypeObject
+	case CollectionTransformTypeSplit, CollectionTransformTypeFlatten, CollectionTransformTypeSort, CollectionTransformTypeUnique, CollectionTransformTypeKeys, CollectionTransformTypeValues:
+	}
+	return TransformIOTypeArray
+}
+
+// Validate checks this CollectionTransform is valid.
+func (t *CollectionTransform) Validate() *field.Error {
+	switch t.Type {
+	case CollectionTransformTypeSplit:
+		if t.Separator != nil && *t.Separator == "" {
+			return field.Invalid(field.NewPath("separator"), *t.Separator, "collection transform type Split requires a non-empty separator")
+		}
+	case CollectionTransformTypeJoin, CollectionTransformTypeFlatten, CollectionTransformTypeSort, CollectionTransformTypeUnique, CollectionTransformTypeKeys, CollectionTransformTypeValues:
+	case CollectionTransformTypePick, CollectionTransformTypeOmit:
+		if len(t.Fields) == 0 {
+			return field.Required(field.NewPath("fields"), fmt.Sprintf("collection transform type %s requires at least one field", t.Type))
+		}
+	default:
+		return field.Invalid(field.NewPath("type"), t.Type, "unknown collection transform type")
+	}
+	return nil
+}
+
 // TransformIOType defines the type of a ConvertTransform.
 type TransformIOType string
 
@@ -510,3 +868,118 @@ func (t ConvertTransform) Validate() *field.Error {
 	}
 	return nil
 }
+
+// CELTransformVariableInput is the name of the variable the input of a
+// CELTransform is bound to.
+const CELTransformVariableInput = "input"
+
+// A CELTransform evaluates a Common Expression Language (CEL) expression
+// against the input and returns its result.
+type CELTransform struct {
+	// Expression is the CEL expression to evaluate. The input is bound to the
+	// `input` variable, e.g. `input.size() > 0 ? input[0] : "none"`. See
+	// https://github.com/google/cel-spec for details.
+	Expression string `json:"expression"`
+
+	// OutputType is the type the expression is expected to return. It is
+	// inferred from the expression if omitted, where possible. An error is
+	// returned if the expression returns a value of a different type.
+	// +optional
+	// +kubebuilder:validation:Enum=string;int;int64;bool;float64;object;array
+	OutputType *TransformIOType `json:"outputType,omitempty"`
+}
+
+// Validate checks this CELTransform is valid.
+func (t *CELTransform) Validate() *field.Error {
+	if t.Expression == "" {
+		return field.Required(field.NewPath("expression"), "cel transform requires an expression")
+	}
+	if t.OutputType != nil && !t.OutputType.IsValid() {
+		return field.Invalid(field.NewPath("outputType"), t.OutputType, "invalid type")
+	}
+	if _, err := t.TypeCheck(""); err != nil {
+		return field.Invalid(field.NewPath("expression"), t.Expression, err.Error())
+	}
+	return nil
+}
+
+// GetOutputType returns the output type of the transform. It returns nil if
+// the output type is not known.
+func (t *CELTransform) GetOutputType() (*TransformIOType, error) {
+	if t.OutputType != nil {
+		return t.OutputType, nil
+	}
+	return t.TypeCheck("")
+}
+
+// TypeCheck compiles the expression with the input bound to a variable of the
+// supplied type, or of a dynamic type if the supplied type is empty. It
+// returns the type of the expression, or nil if the type is not known.
+func (t *CELTransform) TypeCheck(input TransformIOType) (*TransformIOType, error) {
+	ast, err := xcel.Compile(t.Expression, xcel.Variables{CELTransformVariableInput: input.celType()})
+	if err != nil {
+		return nil, err
+	}
+	out := transformIOTypeFromCEL(ast.OutputType())
+	if out == nil || t.OutputType == nil {
+		return out, nil
+	}
+	if !out.IsEquivalent(*t.OutputType) {
+		return nil, errors.Errorf("expression returns %s, not %s", *out, *t.OutputType)
+	}
+	return t.OutputType, nil
+}
+
+// celType returns the CEL type corresponding to this TransformIOType.
+func (c TransformIOType) celType() *cel.Type {
+	switch c {
+	case TransformIOTypeString:
+		return cel.StringType
+	case TransformIOTypeBool:
+		return cel.BoolType
+	case TransformIOTypeInt, TransformIOTypeInt64:
+		return cel.IntType
+	case TransformIOTypeFloat64:
+		return cel.DoubleType
+	case TransformIOTypeObject:
+		return cel.MapType(cel.StringType, cel.DynType)
+	case TransformIOTypeArray:
+		return cel.ListType(cel.DynType)
+	}
+	return cel.DynType
+}
+
+// transformIOTypeFromCEL returns the TransformIOType corresponding to the
+// supplied CEL type, or nil if there is none.
+func transformIOTypeFromCEL(t *cel.Type) *TransformIOType {
+	var out TransformIOType
+	switch t.Kind() { //nolint:exhaustive // Other kinds have no corresponding TransformIOType.
+	case cel.StringKind:
+		out = TransformIOTypeString
+	case cel.BoolKind:
+		out = TransformIOTypeBool
+	case cel.IntKind:
+		out = TransformIOTypeInt64
+	case cel.DoubleKind:
+		out = TransformIOTypeFloat64
+	case cel.MapKind:
+		out = TransformIOTypeObject
+	case cel.ListKind:
+		out = TransformIOTypeArray
+	default:
+		return nil
+	}
+	return &out
+}
+
+// IsEquivalent returns true if the supplied TransformIOType represents the
+// same type of value as this one, i.e. int and int64 are equivalent.
+func (c TransformIOType) IsEquivalent(o TransformIOType) bool {
+	if c == TransformIOTypeInt {
+		c = TransformIOTypeInt64
+	}
+	if o == TransformIOTypeInt {
+		o = TransformIOTypeInt64
+	}
+	return c == o
+}
@@ -95,6 +95,53 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidMathCeilToMultiple": {
+			reason: "Math transform rounding up to a positive multiple should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Type:     MathTransformTypeCeil,
+						Multiple: ptr.To[int64](10),
+					},
+				},
+			},
+		},
+		"InvalidMathDivideByZero": {
+			reason: "Math transform dividing by zero should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Type:   MathTransformTypeDivide,
+						Divide: ptr.To[int64](0),
+					},
+				},
+			},
+			want: want{
+				&field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "math.divide",
+				},
+			},
+		},
+		"InvalidMathMissingAdd": {
+			reason: "Math transform of type Add without add set should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{
+						Type: MathTransformTypeAdd,
+					},
+				},
+			},
+			want: want{
+				&field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "math.add",
+				},
+			},
+		},
 		"InvalidMathNotDefinedAtAll": {
 			reason: "Math transform with no MathTransform set should be invalid",
 			args: args{
@@ -327,6 +374,182 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidCEL": {
+			reason: "CEL transform with a valid expression should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL: &CELTransform{
+						Expression: `input.size() > 0 ? input[0] : "none"`,
+					},
+				},
+			},
+		},
+		"InvalidCELMissingConfig": {
+			reason: "CEL transform without configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "cel",
+				},
+			},
+		},
+		"InvalidCELSyntax": {
+			reason: "CEL transform with an expression that does not parse should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL: &CELTransform{
+						Expression: `input +`,
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "cel.expression",
+				},
+			},
+		},
+		"InvalidCELOutputTypeMismatch": {
+			reason: "CEL transform whose expression does not return the declared output type should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL: &CELTransform{
+						Expression: `"foo"`,
+						OutputType: &[]TransformIOType{TransformIOTypeBool}[0],
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "cel.expression",
+				},
+			},
+		},
+		"ValidTimeAddDuration": {
+			reason: "Time transform adding a duration in days should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTime,
+					Time: &TimeTransform{
+						Type:     TimeTransformTypeAddDuration,
+						Duration: ptr.To("30d"),
+					},
+				},
+			},
+		},
+		"InvalidTimeDuration": {
+			reason: "Time transform adding an invalid duration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTime,
+					Time: &TimeTransform{
+						Type:     TimeTransformTypeAddDuration,
+						Duration: ptr.To("a month"),
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "time.duration",
+				},
+			},
+		},
+		"ValidStringTemplate": {
+			reason: "String transform with a valid template should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:     StringTransformTypeTemplate,
+						Template: ptr.To(`{{ .source.spec.user }}@{{ .input | lower }}`),
+					},
+				},
+			},
+		},
+		"InvalidStringTemplate": {
+			reason: "String transform with a template that can't be parsed should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+					String: &StringTransform{
+						Type:     StringTransformTypeTemplate,
+						Template: ptr.To(`{{ .input | nope }}`),
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "string.template",
+				},
+			},
+		},
+		"ValidCollectionJoin": {
+			reason: "Collection transform joining an array should be valid",
+			args: args{
+				transform: &Transform{
+					Type:       TransformTypeCollection,
+					Collection: &CollectionTransform{Type: CollectionTransformTypeJoin},
+				},
+			},
+		},
+		"InvalidCollectionPickNoFields": {
+			reason: "Collection transform picking no fields should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:       TransformTypeCollection,
+					Collection: &CollectionTransform{Type: CollectionTransformTypePick},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "collection.fields",
+				},
+			},
+		},
+		"InvalidCollectionSplitEmptySeparator": {
+			reason: "Collection transform splitting at an empty separator should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:       TransformTypeCollection,
+					Collection: &CollectionTransform{Type: CollectionTransformTypeSplit, Separator: ptr.To("")},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "collection.separator",
+				},
+			},
+		},
+		"InvalidTimeConvertDurationMissingUnit": {
+			reason: "Time transform converting a duration without a unit should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTime,
+					Time: &TimeTransform{
+						Type: TimeTransformTypeConvertDuration,
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "time.unit",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -362,6 +585,30 @@ func TestTransformGetOutputType(t *testing.T) {
 				output: &[]TransformIOType{TransformIOTypeFloat64}[0],
 			},
 		},
+		"MathTransformRound": {
+			reason: "Output of a Math transform that rounds should be int64",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{Type: MathTransformTypeRound},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeInt64}[0],
+			},
+		},
+		"CollectionTransformKeys": {
+			reason: "Output of a Collection transform that gets the keys of an object should be array",
+			args: args{
+				transform: &Transform{
+					Type:       TransformTypeCollection,
+					Collection: &CollectionTransform{Type: CollectionTransformTypeKeys},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeArray}[0],
+			},
+		},
 		"ConvertTransform": {
 			reason: "Output of Convert transform, no validation, should be the type specified",
 			args: args{
@@ -401,6 +648,66 @@ func TestTransformGetOutputType(t *testing.T) {
 				},
 			},
 		},
+		"CELTransformInferred": {
+			reason: "Output of CEL transform should be inferred from its expression",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL:  &CELTransform{Expression: `input.size() > 3`},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeBool}[0],
+			},
+		},
+		"CELTransformDeclared": {
+			reason: "Output of CEL transform should be the declared output type",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL: &CELTransform{
+						Expression: `input`,
+						OutputType: &[]TransformIOType{TransformIOTypeString}[0],
+					},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeString}[0],
+			},
+		},
+		"TimeTransformFormat": {
+			reason: "Output of a time Format transform should be string",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTime,
+					Time: &TimeTransform{Type: TimeTransformTypeFormat},
+				},
+			},
+			want: want{
+				output: ptr.To(TransformIOTypeString),
+			},
+		},
+		"TimeTransformConvertDuration": {
+			reason: "Output of a time ConvertDuration transform should be int64",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTime,
+					Time: &TimeTransform{Type: TimeTransformTypeConvertDuration, Unit: ptr.To("s")},
+				},
+			},
+			want: want{
+				output: ptr.To(TransformIOTypeInt64),
+			},
+		},
+		"CELTransformDynamicNil": {
+			reason: "Output of CEL transform is nil if it cannot be inferred",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL:  &CELTransform{Expression: `input`},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
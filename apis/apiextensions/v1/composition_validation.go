@@ -69,6 +69,9 @@ func (c *Composition) validatePipeline() (errs field.ErrorList) {
 			errs = append(errs, field.Duplicate(field.NewPath("spec", "pipeline").Index(i).Child("step"), f.Step))
 		}
 		seen[f.Step] = true
+		if err := f.Validate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "pipeline").Index(i)))
+		}
 	}
 	return errs
 }
@@ -124,6 +127,25 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("readinessChecks").Index(j)))
 			}
 		}
+		if res.ForEach != nil {
+			if res.GetName() == "" {
+				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("name"), "resources that use forEach must have a name"))
+			}
+			if err := res.ForEach.Validate(); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("forEach")))
+			}
+		}
+		if res.Condition != nil {
+			if res.GetName() == "" {
+				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("name"), "resources that use condition must have a name"))
+			}
+			if err := res.Condition.Validate(); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("condition")))
+			}
+		}
+		if err := res.OnPatchError.Validate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("onPatchError")))
+		}
 		// TODO(phisco): we should validate also ConnectionDetails, but would need a major refactoring
 	}
 	return errs
@@ -510,6 +510,95 @@ func TestCompositionValidatePipeline(t *testing.T) {
 				},
 			},
 		},
+		"ValidStepCondition": {
+			reason: "A step with a condition that returns a bool should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step:      "foo",
+								Condition: ptr.To("has(observed.composite.resource.spec.enabled) && !(\"cool\" in desired.resources)"),
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidDuplicateCredentialNames": {
+			reason: "A step with duplicate credential names should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step: "foo",
+								Credentials: []FunctionCredentials{
+									{Name: "cool", Source: FunctionCredentialsSourceNone},
+									{Name: "cool", Source: FunctionCredentialsSourceNone},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeDuplicate,
+						Field: "spec.pipeline[0].credentials[1].name",
+					},
+				},
+			},
+		},
+		"InvalidSecretCredentialsWithoutSecretRef": {
+			reason: "A step with Secret credentials but no secretRef should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step: "foo",
+								Credentials: []FunctionCredentials{
+									{Name: "cool", Source: FunctionCredentialsSourceSecret},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.pipeline[0].credentials[0].secretRef",
+					},
+				},
+			},
+		},
+		"InvalidStepCondition": {
+			reason: "A step with a condition that doesn't compile should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step:      "foo",
+								Condition: ptr.To("observed.composite.resource.spec.enabled =="),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.pipeline[0].condition",
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -638,6 +727,101 @@ func TestCompositionValidateResources(t *testing.T) {
 				},
 			},
 		},
+		"ValidForEachResource": {
+			reason: "a named resource using forEach should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: ptr.To("subnet"),
+								ForEach: &ForEach{
+									FromFieldPath: "spec.subnets",
+									KeyFieldPath:  ptr.To("name"),
+								},
+								Patches: []Patch{
+									{
+										Type:          PatchTypeFromCompositeFieldPath,
+										FromFieldPath: ptr.To("forEach.element.cidr"),
+										ToFieldPath:   ptr.To("spec.forProvider.cidrBlock"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidForEachResource": {
+			reason: "an anonymous resource using forEach without a fromFieldPath should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								ForEach: &ForEach{},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].name",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].forEach.fromFieldPath",
+					},
+				},
+			},
+		},
+		"ValidConditionalResource": {
+			reason: "a named resource using a condition should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: ptr.To("backup"),
+								Condition: &ResourceCondition{
+									Type:       ResourceConditionTypeExpression,
+									Expression: ptr.To("xr.spec.parameters.enableBackups == true"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidConditionalResource": {
+			reason: "a resource using a condition with an expression that doesn't return a bool should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: ptr.To("backup"),
+								Condition: &ResourceCondition{
+									Type:       ResourceConditionTypeExpression,
+									Expression: ptr.To("'backups'"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].condition.expression",
+					},
+				},
+			},
+		},
 		"InvalidComplexResource": {
 			reason: "complex resource with invalid patches and readiness checks should be invalid",
 			args: args{
@@ -32,6 +32,11 @@ const (
 	// A TypeOffered XRD has created the CRD for its composite resource claim
 	// and started a controller to reconcile instances of said claim.
 	TypeOffered xpv1.ConditionType = "Offered"
+
+	// A TypePatchesApplied composite resource has applied all of the patches
+	// of its Composition, rather than skipping or defaulting some of them per
+	// their error policy.
+	TypePatchesApplied xpv1.ConditionType = "PatchesApplied"
 )
 
 // Reasons a resource is or is not established or offered.
@@ -43,6 +48,12 @@ const (
 	ReasonTerminatingClaim     xpv1.ConditionReason = "TerminatingCompositeResourceClaim"
 )
 
+// Reasons a composite resource has or has not applied all of its patches.
+const (
+	ReasonAllPatchesApplied xpv1.ConditionReason = "AllPatchesApplied"
+	ReasonPatchesSkipped    xpv1.ConditionReason = "PatchesSkipped"
+)
+
 // WatchingComposite indicates that Crossplane has defined and is watching for a
 // new kind of composite resource.
 func WatchingComposite() xpv1.Condition {
@@ -86,3 +97,26 @@ func TerminatingClaim() xpv1.Condition {
 		Reason:             ReasonTerminatingClaim,
 	}
 }
+
+// AllPatchesApplied indicates that a composite resource has applied all of the
+// patches of its Composition.
+func AllPatchesApplied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePatchesApplied,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAllPatchesApplied,
+	}
+}
+
+// PatchesSkipped indicates that a composite resource skipped or defaulted some
+// of the patches of its Composition because they failed.
+func PatchesSkipped(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePatchesApplied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPatchesSkipped,
+		Message:            msg,
+	}
+}
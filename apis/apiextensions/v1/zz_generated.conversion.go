@@ -105,6 +105,44 @@ func (c *GeneratedRevisionSpecConverter) pRuntimeRawExtensionToPRuntimeRawExtens
 	}
 	return pRuntimeRawExtension
 }
+func (c *GeneratedRevisionSpecConverter) pV1CELTransformToPV1CELTransform(source *CELTransform) *CELTransform {
+	var pV1CELTransform *CELTransform
+	if source != nil {
+		var v1CELTransform CELTransform
+		v1CELTransform.Expression = (*source).Expression
+		var pV1TransformIOType *TransformIOType
+		if (*source).OutputType != nil {
+			v1TransformIOType := TransformIOType(*(*source).OutputType)
+			pV1TransformIOType = &v1TransformIOType
+		}
+		v1CELTransform.OutputType = pV1TransformIOType
+		pV1CELTransform = &v1CELTransform
+	}
+	return pV1CELTransform
+}
+func (c *GeneratedRevisionSpecConverter) pV1CollectionTransformToPV1CollectionTransform(source *CollectionTransform) *CollectionTransform {
+	var pV1CollectionTransform *CollectionTransform
+	if source != nil {
+		var v1CollectionTransform CollectionTransform
+		v1CollectionTransform.Type = CollectionTransformType((*source).Type)
+		var pString *string
+		if (*source).Separator != nil {
+			xstring := *(*source).Separator
+			pString = &xstring
+		}
+		v1CollectionTransform.Separator = pString
+		var stringList []string
+		if (*source).Fields != nil {
+			stringList = make([]string, len((*source).Fields))
+			for i := 0; i < len((*source).Fields); i++ {
+				stringList[i] = (*source).Fields[i]
+			}
+		}
+		v1CollectionTransform.Fields = stringList
+		pV1CollectionTransform = &v1CollectionTransform
+	}
+	return pV1CollectionTransform
+}
 func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine) *Combine {
 	var pV1Combine *Combine
 	if source != nil {
@@ -201,6 +239,29 @@ func (c *GeneratedRevisionSpecConverter) pV1EnvironmentSourceSelectorToPV1Enviro
 	}
 	return pV1EnvironmentSourceSelector
 }
+func (c *GeneratedRevisionSpecConverter) pV1ForEachToPV1ForEach(source *ForEach) *ForEach {
+	var pV1ForEach *ForEach
+	if source != nil {
+		var v1ForEach ForEach
+		v1ForEach.FromFieldPath = (*source).FromFieldPath
+		var pString *string
+		if (*source).KeyFieldPath != nil {
+			xstring := *(*source).KeyFieldPath
+			pString = &xstring
+		}
+		v1ForEach.KeyFieldPath = pString
+		pV1ForEach = &v1ForEach
+	}
+	return pV1ForEach
+}
+func (c *GeneratedRevisionSpecConverter) pV1JSONToPV1JSON(source *v1.JSON) *v1.JSON {
+	var pV1JSON *v1.JSON
+	if source != nil {
+		v1JSON := c.v1JSONToV1JSON((*source))
+		pV1JSON = &v1JSON
+	}
+	return pV1JSON
+}
 func (c *GeneratedRevisionSpecConverter) pV1MapTransformToPV1MapTransform(source *MapTransform) *MapTransform {
 	var pV1MapTransform *MapTransform
 	if source != nil {
@@ -265,6 +326,36 @@ func (c *GeneratedRevisionSpecConverter) pV1MathTransformToPV1MathTransform(sour
 			pInt643 = &xint643
 		}
 		v1MathTransform.ClampMax = pInt643
+		var pInt644 *int64
+		if (*source).Add != nil {
+			xint644 := *(*source).Add
+			pInt644 = &xint644
+		}
+		v1MathTransform.Add = pInt644
+		var pInt645 *int64
+		if (*source).Subtract != nil {
+			xint645 := *(*source).Subtract
+			pInt645 = &xint645
+		}
+		v1MathTransform.Subtract = pInt645
+		var pInt646 *int64
+		if (*source).Divide != nil {
+			xint646 := *(*source).Divide
+			pInt646 = &xint646
+		}
+		v1MathTransform.Divide = pInt646
+		var pInt647 *int64
+		if (*source).Modulo != nil {
+			xint647 := *(*source).Modulo
+			pInt647 = &xint647
+		}
+		v1MathTransform.Modulo = pInt647
+		var pInt648 *int64
+		if (*source).Multiple != nil {
+			xint648 := *(*source).Multiple
+			pInt648 = &xint648
+		}
+		v1MathTransform.Multiple = pInt648
 		pV1MathTransform = &v1MathTransform
 	}
 	return pV1MathTransform
@@ -289,6 +380,16 @@ func (c *GeneratedRevisionSpecConverter) pV1MergeOptionsToPV1MergeOptions(source
 	}
 	return pV1MergeOptions
 }
+func (c *GeneratedRevisionSpecConverter) pV1PatchErrorPolicyToPV1PatchErrorPolicy(source *PatchErrorPolicy) *PatchErrorPolicy {
+	var pV1PatchErrorPolicy *PatchErrorPolicy
+	if source != nil {
+		var v1PatchErrorPolicy PatchErrorPolicy
+		v1PatchErrorPolicy.Action = PatchErrorAction((*source).Action)
+		v1PatchErrorPolicy.Value = c.pV1JSONToPV1JSON((*source).Value)
+		pV1PatchErrorPolicy = &v1PatchErrorPolicy
+	}
+	return pV1PatchErrorPolicy
+}
 func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *PatchPolicy) *PatchPolicy {
 	var pV1PatchPolicy *PatchPolicy
 	if source != nil {
@@ -299,7 +400,14 @@ func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *
 			pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
 		}
 		v1PatchPolicy.FromFieldPath = pV1FromFieldPathPolicy
+		var pV1ToFieldPathPolicy *ToFieldPathPolicy
+		if (*source).ToFieldPath != nil {
+			v1ToFieldPathPolicy := ToFieldPathPolicy(*(*source).ToFieldPath)
+			pV1ToFieldPathPolicy = &v1ToFieldPathPolicy
+		}
+		v1PatchPolicy.ToFieldPath = pV1ToFieldPathPolicy
 		v1PatchPolicy.MergeOptions = c.pV1MergeOptionsToPV1MergeOptions((*source).MergeOptions)
+		v1PatchPolicy.OnError = c.pV1PatchErrorPolicyToPV1PatchErrorPolicy((*source).OnError)
 		pV1PatchPolicy = &v1PatchPolicy
 	}
 	return pV1PatchPolicy
@@ -324,6 +432,38 @@ func (c *GeneratedRevisionSpecConverter) pV1PolicyToPV1Policy(source *v11.Policy
 	}
 	return pV1Policy
 }
+func (c *GeneratedRevisionSpecConverter) pV1ResourceConditionToPV1ResourceCondition(source *ResourceCondition) *ResourceCondition {
+	var pV1ResourceCondition *ResourceCondition
+	if source != nil {
+		var v1ResourceCondition ResourceCondition
+		v1ResourceCondition.Type = ResourceConditionType((*source).Type)
+		var pString *string
+		if (*source).FieldPath != nil {
+			xstring := *(*source).FieldPath
+			pString = &xstring
+		}
+		v1ResourceCondition.FieldPath = pString
+		v1ResourceCondition.Value = c.pV1JSONToPV1JSON((*source).Value)
+		var pString2 *string
+		if (*source).Expression != nil {
+			xstring2 := *(*source).Expression
+			pString2 = &xstring2
+		}
+		v1ResourceCondition.Expression = pString2
+		pV1ResourceCondition = &v1ResourceCondition
+	}
+	return pV1ResourceCondition
+}
+func (c *GeneratedRevisionSpecConverter) pV1SecretReferenceToPV1SecretReference(source *v11.SecretReference) *v11.SecretReference {
+	var pV1SecretReference *v11.SecretReference
+	if source != nil {
+		var v1SecretReference v11.SecretReference
+		v1SecretReference.Name = (*source).Name
+		v1SecretReference.Namespace = (*source).Namespace
+		pV1SecretReference = &v1SecretReference
+	}
+	return pV1SecretReference
+}
 func (c *GeneratedRevisionSpecConverter) pV1StoreConfigReferenceToPV1StoreConfigReference(source *StoreConfigReference) *StoreConfigReference {
 	var pV1StoreConfigReference *StoreConfigReference
 	if source != nil {
@@ -381,10 +521,49 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 		}
 		v1StringTransform.Trim = pString2
 		v1StringTransform.Regexp = c.pV1StringTransformRegexpToPV1StringTransformRegexp((*source).Regexp)
+		var pString3 *string
+		if (*source).Template != nil {
+			xstring3 := *(*source).Template
+			pString3 = &xstring3
+		}
+		v1StringTransform.Template = pString3
 		pV1StringTransform = &v1StringTransform
 	}
 	return pV1StringTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1TimeTransformToPV1TimeTransform(source *TimeTransform) *TimeTransform {
+	var pV1TimeTransform *TimeTransform
+	if source != nil {
+		var v1TimeTransform TimeTransform
+		v1TimeTransform.Type = TimeTransformType((*source).Type)
+		var pString *string
+		if (*source).InputLayout != nil {
+			xstring := *(*source).InputLayout
+			pString = &xstring
+		}
+		v1TimeTransform.InputLayout = pString
+		var pString2 *string
+		if (*source).OutputLayout != nil {
+			xstring2 := *(*source).OutputLayout
+			pString2 = &xstring2
+		}
+		v1TimeTransform.OutputLayout = pString2
+		var pString3 *string
+		if (*source).Duration != nil {
+			xstring3 := *(*source).Duration
+			pString3 = &xstring3
+		}
+		v1TimeTransform.Duration = pString3
+		var pString4 *string
+		if (*source).Unit != nil {
+			xstring4 := *(*source).Unit
+			pString4 = &xstring4
+		}
+		v1TimeTransform.Unit = pString4
+		pV1TimeTransform = &v1TimeTransform
+	}
+	return pV1TimeTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1CombineVariableToV1CombineVariable(source CombineVariable) CombineVariable {
 	var v1CombineVariable CombineVariable
 	v1CombineVariable.FromFieldPath = source.FromFieldPath
@@ -423,6 +602,9 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	v1ComposedTemplate.ForEach = c.pV1ForEachToPV1ForEach(source.ForEach)
+	v1ComposedTemplate.Condition = c.pV1ResourceConditionToPV1ResourceCondition(source.Condition)
+	v1ComposedTemplate.OnPatchError = c.pV1PatchErrorPolicyToPV1PatchErrorPolicy(source.OnPatchError)
 	return v1ComposedTemplate
 }
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
@@ -517,6 +699,13 @@ func (c *GeneratedRevisionSpecConverter) v1EnvironmentSourceToV1EnvironmentSourc
 	v1EnvironmentSource.Selector = c.pV1EnvironmentSourceSelectorToPV1EnvironmentSourceSelector(source.Selector)
 	return v1EnvironmentSource
 }
+func (c *GeneratedRevisionSpecConverter) v1FunctionCredentialsToV1FunctionCredentials(source FunctionCredentials) FunctionCredentials {
+	var v1FunctionCredentials FunctionCredentials
+	v1FunctionCredentials.Name = source.Name
+	v1FunctionCredentials.Source = FunctionCredentialsSource(source.Source)
+	v1FunctionCredentials.SecretRef = c.pV1SecretReferenceToPV1SecretReference(source.SecretRef)
+	return v1FunctionCredentials
+}
 func (c *GeneratedRevisionSpecConverter) v1FunctionReferenceToV1FunctionReference(source FunctionReference) FunctionReference {
 	var v1FunctionReference FunctionReference
 	v1FunctionReference.Name = source.Name
@@ -603,6 +792,26 @@ func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source P
 	v1PipelineStep.Step = source.Step
 	v1PipelineStep.FunctionRef = c.v1FunctionReferenceToV1FunctionReference(source.FunctionRef)
 	v1PipelineStep.Input = c.pRuntimeRawExtensionToPRuntimeRawExtension(source.Input)
+	var pString *string
+	if source.Condition != nil {
+		xstring := *source.Condition
+		pString = &xstring
+	}
+	v1PipelineStep.Condition = pString
+	var v1FunctionCredentialsList []FunctionCredentials
+	if source.Credentials != nil {
+		v1FunctionCredentialsList = make([]FunctionCredentials, len(source.Credentials))
+		for i := 0; i < len(source.Credentials); i++ {
+			v1FunctionCredentialsList[i] = c.v1FunctionCredentialsToV1FunctionCredentials(source.Credentials[i])
+		}
+	}
+	v1PipelineStep.Credentials = v1FunctionCredentialsList
+	var pV1ObservedResourcesPolicy *ObservedResourcesPolicy
+	if source.ObservedResources != nil {
+		v1ObservedResourcesPolicy := ObservedResourcesPolicy(*source.ObservedResources)
+		pV1ObservedResourcesPolicy = &v1ObservedResourcesPolicy
+	}
+	v1PipelineStep.ObservedResources = pV1ObservedResourcesPolicy
 	return v1PipelineStep
 }
 func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(source ReadinessCheck) ReadinessCheck {
@@ -622,6 +831,9 @@ func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transfo
 	v1Transform.Match = c.pV1MatchTransformToPV1MatchTransform(source.Match)
 	v1Transform.String = c.pV1StringTransformToPV1StringTransform(source.String)
 	v1Transform.Convert = c.pV1ConvertTransformToPV1ConvertTransform(source.Convert)
+	v1Transform.CEL = c.pV1CELTransformToPV1CELTransform(source.CEL)
+	v1Transform.Time = c.pV1TimeTransformToPV1TimeTransform(source.Time)
+	v1Transform.Collection = c.pV1CollectionTransformToPV1CollectionTransform(source.Collection)
 	return v1Transform
 }
 func (c *GeneratedRevisionSpecConverter) v1TypeReferenceToV1TypeReference(source TypeReference) TypeReference {
@@ -19,7 +19,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/google/cel-go/cel"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -27,6 +29,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 
 	"github.com/crossplane/crossplane/internal/validation/errors"
+	"github.com/crossplane/crossplane/internal/xcel"
 )
 
 /*
@@ -114,6 +117,135 @@ type ComposedTemplate struct {
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// ForEach renders one composed resource from this template per element of
+	// an array field of the composite resource. Templates that use ForEach must
+	// be named.
+	// +optional
+	ForEach *ForEach `json:"forEach,omitempty"`
+
+	// Condition determines whether a composed resource is rendered from this
+	// template. A composed resource that was rendered from this template is
+	// deleted when its condition no longer holds. Templates that use Condition
+	// must be named.
+	// +optional
+	Condition *ResourceCondition `json:"condition,omitempty"`
+
+	// OnPatchError determines what happens when one of this template's
+	// patches fails, unless the patch specifies its own onError policy.
+	// +optional
+	OnPatchError *PatchErrorPolicy `json:"onPatchError,omitempty"`
+}
+
+// ResourceConditionType is the type of a ResourceCondition.
+type ResourceConditionType string
+
+// ResourceCondition types.
+const (
+	ResourceConditionTypeFieldPathExists ResourceConditionType = "FieldPathExists"
+	ResourceConditionTypeFieldPathEquals ResourceConditionType = "FieldPathEquals"
+	ResourceConditionTypeExpression      ResourceConditionType = "Expression"
+)
+
+// ResourceConditionVariableXR is the name of the variable the composite
+// resource is bound to in the expression of an Expression ResourceCondition.
+const ResourceConditionVariableXR = "xr"
+
+// A ResourceCondition determines whether a composed resource is rendered from
+// a template.
+type ResourceCondition struct {
+	// Type of the condition. FieldPathExists holds if the fieldPath of the
+	// composite resource exists. FieldPathEquals holds if the fieldPath of the
+	// composite resource exists and is equal to value. Expression holds if
+	// expression returns true.
+	// +kubebuilder:validation:Enum=FieldPathExists;FieldPathEquals;Expression
+	Type ResourceConditionType `json:"type"`
+
+	// FieldPath of the composite resource the condition checks. Required when
+	// type is FieldPathExists or FieldPathEquals.
+	// +optional
+	FieldPath *string `json:"fieldPath,omitempty"`
+
+	// Value the field must equal. Required when type is FieldPathEquals.
+	// +optional
+	Value *extv1.JSON `json:"value,omitempty"`
+
+	// Expression is a CEL expression that must return a boolean. The
+	// composite resource is bound to the xr variable, e.g.
+	// `has(xr.spec.parameters.enableBackups) && xr.spec.parameters.enableBackups`.
+	// Required when type is Expression. See https://github.com/google/cel-spec
+	// for details.
+	// +optional
+	Expression *string `json:"expression,omitempty"`
+}
+
+// Validate checks this ResourceCondition is valid.
+func (c *ResourceCondition) Validate() *field.Error {
+	switch c.Type {
+	case ResourceConditionTypeFieldPathExists:
+		if c.FieldPath == nil || *c.FieldPath == "" {
+			return field.Required(field.NewPath("fieldPath"), "fieldPath must be set for condition type FieldPathExists")
+		}
+	case ResourceConditionTypeFieldPathEquals:
+		if c.FieldPath == nil || *c.FieldPath == "" {
+			return field.Required(field.NewPath("fieldPath"), "fieldPath must be set for condition type FieldPathEquals")
+		}
+		if c.Value == nil {
+			return field.Required(field.NewPath("value"), "value must be set for condition type FieldPathEquals")
+		}
+	case ResourceConditionTypeExpression:
+		if c.Expression == nil || *c.Expression == "" {
+			return field.Required(field.NewPath("expression"), "expression must be set for condition type Expression")
+		}
+		ast, err := xcel.Compile(*c.Expression, xcel.Variables{ResourceConditionVariableXR: cel.DynType})
+		if err != nil {
+			return field.Invalid(field.NewPath("expression"), *c.Expression, err.Error())
+		}
+		if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+			return field.Invalid(field.NewPath("expression"), *c.Expression, "expression must return a bool")
+		}
+	default:
+		return field.Invalid(field.NewPath("type"), c.Type, "unknown condition type")
+	}
+	return nil
+}
+
+// ForEachFieldPath is the field path from which the patches of a ForEach
+// composed resource template may read the array element the composed resource
+// is rendered for.
+const ForEachFieldPath = "forEach"
+
+// ForEach renders one composed resource per element of an array field of the
+// composite resource. Each composed resource is named after its template and
+// the key of its element, i.e. <template name>-<key>. Composed resources are
+// deleted when their element is removed from the array.
+//
+// The FromCompositeFieldPath and CombineFromComposite patches of the template
+// may read the element from the forEach.element field path, its key from the
+// forEach.key field path, and its index from the forEach.index field path.
+type ForEach struct {
+	// FromFieldPath is the path of an array field of the composite resource.
+	// One composed resource is rendered per element of the array.
+	FromFieldPath string `json:"fromFieldPath"`
+
+	// KeyFieldPath is the path of a string or integer field of each element
+	// that uniquely identifies the element within the array, e.g. name. Keys
+	// keep the names of composed resources stable when elements are added to
+	// or removed from the middle of the array. The index of each element is
+	// used as its key if omitted.
+	// +optional
+	KeyFieldPath *string `json:"keyFieldPath,omitempty"`
+}
+
+// Validate checks this ForEach is valid.
+func (f *ForEach) Validate() *field.Error {
+	if f.FromFieldPath == "" {
+		return field.Required(field.NewPath("fromFieldPath"), "forEach requires a fromFieldPath")
+	}
+	if f.KeyFieldPath != nil && *f.KeyFieldPath == "" {
+		return field.Invalid(field.NewPath("keyFieldPath"), f.KeyFieldPath, "keyFieldPath must not be empty if specified")
+	}
+	return nil
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -300,6 +432,126 @@ type PipelineStep struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:EmbeddedResource
 	Input *runtime.RawExtension `json:"input,omitempty"`
+
+	// Condition is a CEL expression that must return a boolean. The step only
+	// runs if it returns true, otherwise it is skipped and the desired state
+	// and context are passed unchanged to the next step. The observed state,
+	// the desired state returned by the previous steps, and the pipeline
+	// context are bound to the observed, desired and context variables, using
+	// the JSON representation of a RunFunctionRequest, e.g.
+	// `observed.composite.resource.spec.parameters.enableBackups == true`.
+	// See https://github.com/google/cel-spec for details.
+	// +optional
+	Condition *string `json:"condition,omitempty"`
+
+	// Credentials are optional credentials that the Composition Function
+	// needs. They're passed to the Function as the credentials of its
+	// RunFunctionRequest, keyed by name.
+	// +optional
+	Credentials []FunctionCredentials `json:"credentials,omitempty"`
+
+	// ObservedResources configures which observed composed resources are
+	// sent to the Composition Function. All sends every observed composed
+	// resource. Required sends only the observed composed resources the
+	// Function requires, by name. A Function declares which observed
+	// composed resources it requires using the observed_resources
+	// requirement of its RunFunctionResponse, and is run again with them.
+	// Use Required to run Functions that only need a few of the composed
+	// resources of an XR that composes many. Defaults to All.
+	// +optional
+	// +kubebuilder:validation:Enum=All;Required
+	ObservedResources *ObservedResourcesPolicy `json:"observedResources,omitempty"`
+}
+
+// An ObservedResourcesPolicy configures which observed composed resources are
+// sent to a Composition Function.
+type ObservedResourcesPolicy string
+
+const (
+	// ObservedResourcesAll sends every observed composed resource to the
+	// Function.
+	ObservedResourcesAll ObservedResourcesPolicy = "All"
+
+	// ObservedResourcesRequired sends only the observed composed resources the
+	// Function requires to the Function.
+	ObservedResourcesRequired ObservedResourcesPolicy = "Required"
+)
+
+// SendsRequiredObservedResources returns true if this PipelineStep only sends
+// the observed composed resources its Function requires.
+func (s *PipelineStep) SendsRequiredObservedResources() bool {
+	return s.ObservedResources != nil && *s.ObservedResources == ObservedResourcesRequired
+}
+
+// A FunctionCredentialsSource is a source from which Composition Function
+// credentials may be acquired.
+type FunctionCredentialsSource string
+
+const (
+	// FunctionCredentialsSourceNone indicates that a Function does not need
+	// credentials.
+	FunctionCredentialsSourceNone FunctionCredentialsSource = "None"
+
+	// FunctionCredentialsSourceSecret indicates that a Function should acquire
+	// credentials from a secret.
+	FunctionCredentialsSourceSecret FunctionCredentialsSource = "Secret"
+)
+
+// FunctionCredentials are optional credentials that a Composition Function
+// needs to run.
+type FunctionCredentials struct {
+	// Name of this set of credentials. Must be unique within the step.
+	Name string `json:"name"`
+
+	// Source of the Function credentials.
+	// +kubebuilder:validation:Enum=None;Secret
+	Source FunctionCredentialsSource `json:"source"`
+
+	// A SecretRef is a reference to a secret containing credentials that should
+	// be supplied to the function. Required when source is Secret.
+	// +optional
+	SecretRef *xpv1.SecretReference `json:"secretRef,omitempty"`
+}
+
+// The names of the variables the state of a Composition pipeline is bound to
+// in the condition of a PipelineStep.
+const (
+	PipelineStepConditionVariableObserved = "observed"
+	PipelineStepConditionVariableDesired  = "desired"
+	PipelineStepConditionVariableContext  = "context"
+)
+
+// Validate checks this PipelineStep is valid.
+func (s *PipelineStep) Validate() *field.Error {
+	seen := make(map[string]bool, len(s.Credentials))
+	for i, c := range s.Credentials {
+		if seen[c.Name] {
+			return field.Duplicate(field.NewPath("credentials").Index(i).Child("name"), c.Name)
+		}
+		seen[c.Name] = true
+		if c.Source == FunctionCredentialsSourceSecret && c.SecretRef == nil {
+			return field.Required(field.NewPath("credentials").Index(i).Child("secretRef"), "secretRef must be set when source is Secret")
+		}
+	}
+
+	if s.Condition == nil {
+		return nil
+	}
+	if *s.Condition == "" {
+		return field.Required(field.NewPath("condition"), "condition must not be empty")
+	}
+	ast, err := xcel.Compile(*s.Condition, xcel.Variables{
+		PipelineStepConditionVariableObserved: cel.DynType,
+		PipelineStepConditionVariableDesired:  cel.DynType,
+		PipelineStepConditionVariableContext:  cel.DynType,
+	})
+	if err != nil {
+		return field.Invalid(field.NewPath("condition"), *s.Condition, err.Error())
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return field.Invalid(field.NewPath("condition"), *s.Condition, "condition must return a bool")
+	}
+	return nil
 }
 
 // A FunctionReference references a Composition Function that may be used in a
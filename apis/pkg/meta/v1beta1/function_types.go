@@ -26,6 +26,36 @@ type FunctionSpec struct {
 
 	// Image is the packaged Function image.
 	Image *string `json:"image,omitempty"`
+
+	// Runtime configures how Crossplane runs the Function. Functions are run
+	// in a container by default.
+	Runtime *FunctionRuntime `json:"runtime,omitempty"`
+}
+
+// A FunctionRuntimeType is a type of Function runtime.
+type FunctionRuntimeType string
+
+// Function runtime types.
+const (
+	// FunctionRuntimeTypeContainer Functions are run as a Deployment, and
+	// called via gRPC.
+	FunctionRuntimeTypeContainer FunctionRuntimeType = "Container"
+
+	// FunctionRuntimeTypeWebAssembly Functions are WebAssembly modules that
+	// are run inside the Crossplane pod.
+	FunctionRuntimeTypeWebAssembly FunctionRuntimeType = "WebAssembly"
+)
+
+// FunctionRuntime configures how Crossplane runs a Function.
+type FunctionRuntime struct {
+	// Type of runtime. Defaults to Container.
+	// +kubebuilder:validation:Enum=Container;WebAssembly
+	Type FunctionRuntimeType `json:"type,omitempty"`
+
+	// Module is the path of the WebAssembly module within the Function
+	// package image. Only used by the WebAssembly runtime. Defaults to
+	// function.wasm.
+	Module *string `json:"module,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,6 +55,31 @@ type FunctionSpec struct {
 	v1.PackageSpec `json:",inline"`
 
 	v1.PackageRuntimeSpec `json:",inline"`
+
+	// RunPolicy configures how Crossplane calls the Function.
+	// +optional
+	RunPolicy *FunctionRunPolicy `json:"runPolicy,omitempty"`
+}
+
+// FunctionRunPolicy configures how Crossplane calls a Function.
+type FunctionRunPolicy struct {
+	// Timeout of each attempt to run the Function. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// MaxRetries is the maximum number of times Crossplane retries a call to
+	// the Function that fails with a transient error, for example because the
+	// Function's pod is restarting. Defaults to 3. Set to 0 to disable
+	// retries.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// Backoff is how long Crossplane waits before it first retries a call to
+	// the Function. It doubles after each retry, up to a maximum of 10s.
+	// Defaults to 250ms.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
 }
 
 // FunctionStatus represents the observed state of a Function.
@@ -108,8 +133,34 @@ type FunctionRevisionStatus struct {
 	// Endpoint is the gRPC endpoint where Crossplane will send
 	// RunFunctionRequests.
 	Endpoint string `json:"endpoint,omitempty"`
+
+	// Runtime is the runtime Crossplane uses to run the Function, as
+	// specified by the Function package's metadata. Functions with no runtime
+	// are run in a container.
+	// +kubebuilder:validation:Enum=Container;WebAssembly
+	// +optional
+	Runtime FunctionRuntimeType `json:"runtime,omitempty"`
+
+	// Module is the path of the WebAssembly module within the Function
+	// package image. Only set when the runtime is WebAssembly.
+	// +optional
+	Module string `json:"module,omitempty"`
 }
 
+// A FunctionRuntimeType is a type of Function runtime.
+type FunctionRuntimeType string
+
+// Function runtime types.
+const (
+	// FunctionRuntimeTypeContainer Functions are run as a Deployment, and
+	// called via gRPC at their endpoint.
+	FunctionRuntimeTypeContainer FunctionRuntimeType = "Container"
+
+	// FunctionRuntimeTypeWebAssembly Functions are WebAssembly modules that
+	// are run inside the Crossplane pod.
+	FunctionRuntimeTypeWebAssembly FunctionRuntimeType = "WebAssembly"
+)
+
 // +kubebuilder:object:root=true
 
 // FunctionRevisionList contains a list of FunctionRevision.
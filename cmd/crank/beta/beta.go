@@ -21,7 +21,9 @@ package beta
 
 import (
 	"github.com/crossplane/crossplane/cmd/crank/beta/render"
+	"github.com/crossplane/crossplane/cmd/crank/beta/test"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace"
+	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
 	"github.com/crossplane/crossplane/cmd/crank/beta/xpkg"
 )
 
@@ -29,9 +31,11 @@ import (
 type Cmd struct {
 	// Subcommands and flags will appear in the CLI help output in the same
 	// order they're specified here. Keep them in alphabetical order.
-	Render render.Cmd `cmd:"" help:"Render a composite resource (XR)."`
-	Trace  trace.Cmd  `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
-	XPKG   xpkg.Cmd   `cmd:"" help:"Manage Crossplane packages."`
+	Render   render.Cmd   `cmd:"" help:"Render a composite resource (XR)."`
+	Test     test.Cmd     `cmd:"" help:"Run golden file tests for Compositions."`
+	Trace    trace.Cmd    `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
+	Validate validate.Cmd `cmd:"" help:"Validate resources against the schemas of their CustomResourceDefinitions."`
+	XPKG     xpkg.Cmd     `cmd:"" help:"Manage Crossplane packages."`
 }
 
 // Help output for crossplane beta.
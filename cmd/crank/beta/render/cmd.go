@@ -14,39 +14,51 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package render implements composition rendering using composition functions.
+// Package render implements composition rendering using composition functions or
+// patch and transform resource templates.
 package render
 
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
 
 // Cmd arguments and flags for render subcommand.
 type Cmd struct {
 	// Arguments.
-	CompositeResource string `arg:"" type:"existingfile" help:"A YAML file specifying the composite resource (XR) to render."`
-	Composition       string `arg:"" type:"existingfile" help:"A YAML file specifying the Composition to use to render the XR. Must be mode: Pipeline."`
-	Functions         string `arg:"" type:"path" help:"A YAML file or directory of YAML files specifying the Composition Functions to use to render the XR."`
+	CompositeResource string `arg:"" type:"existingfile" help:"A YAML file specifying the composite resource (XR) to render, or a claim if --xrd is specified."`
+	Composition       string `arg:"" type:"path" help:"A YAML file or directory of YAML files specifying the Composition to use to render the XR. May specify several Compositions to select from if --xrd is specified."`
+	Functions         string `arg:"" optional:"" type:"path" help:"A YAML file or directory of YAML files specifying the Composition Functions to use to render the XR. Required if the Composition uses mode: Pipeline."`
 
 	// Flags. Keep them in alphabetical order.
 	ContextFiles           map[string]string `mapsep:"," help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be files containing JSON."`
 	ContextValues          map[string]string `mapsep:"," help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be JSON. Keys take precedence over --context-files."`
+	EnvironmentConfigs     string            `placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying EnvironmentConfigs that Compositions using mode: Resources may select."`
+	ExtraResources         string            `short:"e" placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying extra resources to pass to Functions that require them."`
+	FunctionCredentials    string            `placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying Secrets to use as credentials for Functions that require them."`
 	IncludeFunctionResults bool              `short:"r" help:"Include informational and warning messages from Functions in the rendered output as resources of kind: Result."`
+	Iterations             int               `default:"1" help:"Simulate up to this many reconciles, feeding each reconcile's desired resources back as observed resources, until the output stabilizes. Prints each reconcile's changes to stderr."`
 	ObservedResources      string            `short:"o" placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying the observed state of composed resources."`
+	ObservedStatus         string            `placeholder:"PATH" type:"path" help:"A YAML file or directory of YAML files specifying partial composed resources to merge into the observed state of composed resources when simulating reconciles, e.g. their status. Matched by composition resource name annotation."`
 	Timeout                time.Duration     `help:"How long to run before timing out." default:"1m"`
+	XRD                    string            `name:"xrd" placeholder:"PATH" type:"existingfile" help:"A YAML file specifying the CompositeResourceDefinition (XRD) of the XR. Used to default the XR, to render a claim, and to select a Composition."`
 
 	fs afero.Fs
 }
@@ -58,7 +70,12 @@ This command shows you what composed resources Crossplane would create by
 printing them to stdout. It also prints any changes that would be made to the
 status of the XR. It doesn't talk to Crossplane. Instead it runs the Composition
 Function pipeline specified by the Composition locally, and uses that to render
-the XR. It only supports Compositions in Pipeline mode.
+the XR.
+
+Compositions in Resources mode are rendered using their patch and transform
+resource templates, the same way Crossplane renders them. They don't need any
+Functions. Use --environment-configs to supply any EnvironmentConfigs they
+select.
 
 Composition Functions are pulled and run using Docker by default. You can add
 the following annotations to each Function to change how they're run:
@@ -83,6 +100,24 @@ the following annotations to each Function to change how they're run:
     Always pull the Function's package, even if it already exists locally.
 	Other supported values are Never, or IfNotPresent. 
 
+Crossplane usually takes several reconciles to converge, as composed resources
+become ready and their status is patched or observed back into the XR. Use
+--iterations to simulate this. Each reconcile's desired XR and composed
+resources are merged into the observed state of the next, and any partial
+resources supplied by --observed-status are merged into observed composed
+resources with the same crossplane.io/composition-resource-name annotation.
+Simulation stops early once the observed state stops changing. The output of
+the final reconcile is printed to stdout, and the changes made by each
+reconcile are printed to stderr.
+
+Use --xrd to supply the XR's CompositeResourceDefinition. The XR is defaulted
+using the XRD's schema, like the API server would default it. The resource to
+render may then also be a claim. The claim is defaulted, then used to configure
+the XR Crossplane would create for it. The XRD is also used to select which of
+several Compositions to use, by the XR's compositionRef or compositionSelector,
+or the XRD's enforced or default Composition. If several match, the first by
+name is selected. Crossplane would select one of them at random.
+
 Use the standard DOCKER_HOST, DOCKER_API_VERSION, DOCKER_CERT_PATH, and
 DOCKER_TLS_VERIFY environment variables to configure how this command connects
 to the Docker daemon.
@@ -96,9 +131,30 @@ Examples:
   crossplane beta render xr.yaml composition.yaml functions.yaml \
     --observed-resources=existing-observed-resources.yaml
 
+  # Pass extra resources Functions in the pipeline may require.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    --extra-resources=extra-resources.yaml
+
+  # Pass credentials to Functions in the pipeline that need them.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    --function-credentials=credentials.yaml
+
   # Pass context values to the Function pipeline.
   crossplane beta render xr.yaml composition.yaml functions.yaml \
     --context-values=apiextensions.crossplane.io/environment='{"key": "value"}'
+
+  # Simulate several reconciles, as composed resources become ready.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    --iterations=5 --observed-status=ready-status.yaml
+
+  # Render a claim, selecting one of a directory of Compositions.
+  crossplane beta render claim.yaml compositions/ functions.yaml \
+    --xrd=xrd.yaml
+
+  # Simulate updating an XR using a patch and transform Composition.
+  crossplane beta render xr.yaml composition.yaml \
+    --observed-resources=existing-observed-resources.yaml \
+    --environment-configs=environment-configs.yaml
 `
 }
 
@@ -109,17 +165,52 @@ func (c *Cmd) AfterApply() error {
 }
 
 // Run render.
-func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo // Only a touch over.
+func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo // Mostly loading optional inputs.
+	if c.Iterations < 1 {
+		return errors.New("--iterations must be at least 1")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
 	xr, err := LoadCompositeResource(c.fs, c.CompositeResource)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load composite resource from %q", c.CompositeResource)
 	}
 
+	comps, err := LoadCompositions(c.fs, c.Composition)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load Compositions from %q", c.Composition)
+	}
+
 	// TODO(negz): Should we do some simple validations, e.g. that the
 	// Composition's compositeTypeRef matches the XR's type?
-	comp, err := LoadComposition(c.fs, c.Composition)
-	if err != nil {
-		return errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
+	var comp *v1.Composition
+	switch {
+	case c.XRD != "":
+		xrd, err := LoadCompositeResourceDefinition(c.fs, c.XRD)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load composite resource definition from %q", c.XRD)
+		}
+		if IsClaim(&xr.Unstructured, xrd) {
+			cm := &claim.Unstructured{Unstructured: xr.Unstructured}
+			if err := DefaultClaim(cm, xrd); err != nil {
+				return errors.Wrapf(err, "cannot default claim %q", cm.GetName())
+			}
+			if xr, err = CompositeFromClaim(ctx, cm, xrd); err != nil {
+				return errors.Wrapf(err, "cannot derive composite resource from claim %q", cm.GetName())
+			}
+		}
+		if err := DefaultCompositeResource(xr, xrd); err != nil {
+			return errors.Wrapf(err, "cannot default composite resource %q", xr.GetName())
+		}
+		if comp, err = SelectComposition(ctx, xr, xrd, comps); err != nil {
+			return errors.Wrapf(err, "cannot select Composition for composite resource %q", xr.GetName())
+		}
+	case len(comps) == 1:
+		comp = &comps[0]
+	default:
+		return errors.Errorf("found %d Compositions in %q - you must specify --xrd to select one of several Compositions", len(comps), c.Composition)
 	}
 
 	warns, errs := comp.Validate()
@@ -130,13 +221,15 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 		return errors.Wrapf(errs.ToAggregate(), "invalid Composition %q", comp.GetName())
 	}
 
-	if m := comp.Spec.Mode; m == nil || *m != v1.CompositionModePipeline {
-		return errors.Errorf("render only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline", comp.GetName())
-	}
-
-	fns, err := LoadFunctions(c.fs, c.Functions)
-	if err != nil {
-		return errors.Wrapf(err, "cannot load functions from %q", c.Functions)
+	fns := []pkgv1beta1.Function{}
+	if m := comp.Spec.Mode; m != nil && *m == v1.CompositionModePipeline {
+		if c.Functions == "" {
+			return errors.Errorf("Composition %q uses spec.mode: Pipeline - you must specify the Functions to use to render the XR", comp.GetName())
+		}
+		fns, err = LoadFunctions(c.fs, c.Functions)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load functions from %q", c.Functions)
+		}
 	}
 
 	ors := []composed.Unstructured{}
@@ -147,6 +240,38 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 		}
 	}
 
+	ers := []unstructured.Unstructured{}
+	if c.ExtraResources != "" {
+		ers, err = LoadExtraResources(c.fs, c.ExtraResources)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load extra resources from %q", c.ExtraResources)
+		}
+	}
+
+	ecs := []v1alpha1.EnvironmentConfig{}
+	if c.EnvironmentConfigs != "" {
+		ecs, err = LoadEnvironmentConfigs(c.fs, c.EnvironmentConfigs)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load EnvironmentConfigs from %q", c.EnvironmentConfigs)
+		}
+	}
+
+	overlays := []composed.Unstructured{}
+	if c.ObservedStatus != "" {
+		overlays, err = LoadObservedResources(c.fs, c.ObservedStatus)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load observed status from %q", c.ObservedStatus)
+		}
+	}
+
+	creds := []corev1.Secret{}
+	if c.FunctionCredentials != "" {
+		creds, err = LoadCredentials(c.fs, c.FunctionCredentials)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load Function credentials from %q", c.FunctionCredentials)
+		}
+	}
+
 	fctx := map[string][]byte{}
 	for k, filename := range c.ContextFiles {
 		v, err := afero.ReadFile(c.fs, filename)
@@ -159,19 +284,35 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 		fctx[k] = []byte(v)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
-	defer cancel()
-
-	out, err := Render(ctx, Inputs{
+	in := Inputs{
 		CompositeResource: xr,
 		Composition:       comp,
 		Functions:         fns,
 		ObservedResources: ors,
+		ExtraResources:    ers,
+		Credentials:       creds,
 		Context:           fctx,
-	})
+
+		EnvironmentConfigs: ecs,
+	}
+
+	steps, err := Simulate(ctx, in, c.Iterations, overlays)
 	if err != nil {
 		return errors.Wrap(err, "cannot render composite resource")
 	}
+	if c.Iterations > 1 {
+		for i, s := range steps {
+			switch {
+			case i == 0:
+				fmt.Fprintf(k.Stderr, "Reconcile 1 rendered %d composed resources\n", len(s.Outputs.ComposedResources))
+			case s.Diff == "":
+				fmt.Fprintf(k.Stderr, "Reconcile %d made no changes\n", i+1)
+			default:
+				fmt.Fprintf(k.Stderr, "Reconcile %d made changes (-before +after):\n%s\n", i+1, s.Diff)
+			}
+		}
+	}
+	out := steps[len(steps)-1].Outputs
 
 	// TODO(negz): Right now we're just emitting the desired state, which is an
 	// overlay on the observed state. Would it be more useful to apply the
@@ -180,24 +321,31 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error { //nolint:gocyclo //
 	// server-side apply would do (e.g. merging vs atomically replacing arrays)
 	// and we don't have enough context (i.e. OpenAPI schemas) to do that.
 
+	return errors.Wrap(WriteYAMLStream(k.Stdout, out, c.IncludeFunctionResults), "cannot write rendered output")
+}
+
+// WriteYAMLStream writes the supplied outputs to the supplied writer as a
+// stream of YAML manifests; the XR, then its composed resources, then
+// optionally any results.
+func WriteYAMLStream(w io.Writer, out Outputs, includeResults bool) error {
 	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
 
-	fmt.Fprintln(k.Stdout, "---")
-	if err := s.Encode(out.CompositeResource, os.Stdout); err != nil {
-		return errors.Wrapf(err, "cannot marshal composite resource %q to YAML", xr.GetName())
+	fmt.Fprintln(w, "---")
+	if err := s.Encode(out.CompositeResource, w); err != nil {
+		return errors.Wrapf(err, "cannot marshal composite resource %q to YAML", out.CompositeResource.GetName())
 	}
 
 	for i := range out.ComposedResources {
-		fmt.Fprintln(k.Stdout, "---")
-		if err := s.Encode(&out.ComposedResources[i], os.Stdout); err != nil {
+		fmt.Fprintln(w, "---")
+		if err := s.Encode(&out.ComposedResources[i], w); err != nil {
 			return errors.Wrapf(err, "cannot marshal composed resource %q to YAML", out.ComposedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName])
 		}
 	}
 
-	if c.IncludeFunctionResults {
+	if includeResults {
 		for i := range out.Results {
-			fmt.Fprintln(k.Stdout, "---")
-			if err := s.Encode(&out.Results[i], os.Stdout); err != nil {
+			fmt.Fprintln(w, "---")
+			if err := s.Encode(&out.Results[i], w); err != nil {
 				return errors.Wrap(err, "cannot marshal result to YAML")
 			}
 		}
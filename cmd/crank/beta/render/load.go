@@ -22,6 +22,8 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -29,6 +31,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
 
@@ -42,9 +45,9 @@ func LoadCompositeResource(fs afero.Fs, file string) (*composite.Unstructured, e
 	return xr, errors.Wrap(yaml.Unmarshal(y, xr), "cannot unmarshal composite resource YAML")
 }
 
-// TODO(negz): What if we load a YAML stream of Compositions? We could then
-// render out nested XRs too. What would that look like in our output? How would
-// we match XRs to Compositions (e.g. selectors, refs etc)
+// TODO(negz): We can load a YAML stream of Compositions to select from. Could we
+// use them to render out nested XRs too? What would that look like in our
+// output?
 
 // LoadComposition form a YAML manifest.
 func LoadComposition(fs afero.Fs, file string) (*apiextensionsv1.Composition, error) {
@@ -58,12 +61,80 @@ func LoadComposition(fs afero.Fs, file string) (*apiextensionsv1.Composition, er
 	}
 	switch gvk := comp.GroupVersionKind(); gvk {
 	case apiextensionsv1.CompositionGroupVersionKind:
+		DefaultEnvironment(comp.Spec.Environment)
 		return comp, nil
 	default:
 		return nil, errors.Errorf("not a composition: %s/%s", gvk.Kind, comp.GetName())
 	}
 }
 
+// LoadCompositions from the supplied YAML file or directory.
+func LoadCompositions(fs afero.Fs, fileOrDir string) ([]apiextensionsv1.Composition, error) {
+	stream, err := LoadYAMLStream(fs, fileOrDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream from file")
+	}
+
+	comps := make([]apiextensionsv1.Composition, 0, len(stream))
+	for _, y := range stream {
+		comp := &apiextensionsv1.Composition{}
+		if err := yaml.Unmarshal(y, comp); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML Composition manifest")
+		}
+		switch gvk := comp.GroupVersionKind(); gvk {
+		case apiextensionsv1.CompositionGroupVersionKind:
+			DefaultEnvironment(comp.Spec.Environment)
+			comps = append(comps, *comp)
+		default:
+			return nil, errors.Errorf("not a composition: %s/%s", gvk.Kind, comp.GetName())
+		}
+	}
+
+	return comps, nil
+}
+
+// LoadCompositeResourceDefinition from a YAML manifest.
+func LoadCompositeResourceDefinition(fs afero.Fs, file string) (*apiextensionsv1.CompositeResourceDefinition, error) {
+	y, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read composite resource definition file")
+	}
+	xrd := &apiextensionsv1.CompositeResourceDefinition{}
+	if err := yaml.Unmarshal(y, xrd); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal composite resource definition YAML")
+	}
+	switch gvk := xrd.GroupVersionKind(); gvk {
+	case apiextensionsv1.CompositeResourceDefinitionGroupVersionKind:
+		return xrd, nil
+	default:
+		return nil, errors.Errorf("not a composite resource definition: %s/%s", gvk.Kind, xrd.GetName())
+	}
+}
+
+// DefaultEnvironment sets the defaults the API server would set on the
+// supplied Composition environment. Other fields that the API server defaults
+// are handled by getters, e.g. Patch's GetType.
+func DefaultEnvironment(env *apiextensionsv1.EnvironmentConfiguration) {
+	if env == nil {
+		return
+	}
+	for i := range env.EnvironmentConfigs {
+		src := &env.EnvironmentConfigs[i]
+		if src.Type == "" {
+			src.Type = apiextensionsv1.EnvironmentSourceTypeReference
+		}
+		if src.Selector == nil {
+			continue
+		}
+		if src.Selector.Mode == "" {
+			src.Selector.Mode = apiextensionsv1.EnvironmentSourceSelectorSingleMode
+		}
+		if src.Selector.SortByFieldPath == "" {
+			src.Selector.SortByFieldPath = "metadata.name"
+		}
+	}
+}
+
 // TODO(negz): Support optionally loading functions and observed resources from
 // a directory of manifests instead of a single stream.
 
@@ -110,6 +181,63 @@ func LoadObservedResources(fs afero.Fs, file string) ([]composed.Unstructured, e
 	return observed, nil
 }
 
+// LoadExtraResources from the supplied YAML file or directory.
+func LoadExtraResources(fs afero.Fs, file string) ([]unstructured.Unstructured, error) {
+	stream, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream from file")
+	}
+
+	extra := make([]unstructured.Unstructured, 0, len(stream))
+	for _, y := range stream {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(y, u); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML extra resource manifest")
+		}
+		extra = append(extra, *u)
+	}
+
+	return extra, nil
+}
+
+// LoadEnvironmentConfigs from the supplied YAML file or directory.
+func LoadEnvironmentConfigs(fs afero.Fs, file string) ([]v1alpha1.EnvironmentConfig, error) {
+	stream, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream from file")
+	}
+
+	configs := make([]v1alpha1.EnvironmentConfig, 0, len(stream))
+	for _, y := range stream {
+		ec := &v1alpha1.EnvironmentConfig{}
+		if err := yaml.Unmarshal(y, ec); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML EnvironmentConfig manifest")
+		}
+		configs = append(configs, *ec)
+	}
+
+	return configs, nil
+}
+
+// LoadCredentials from the supplied YAML file or directory of Secrets.
+func LoadCredentials(fs afero.Fs, file string) ([]corev1.Secret, error) {
+	stream, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream from file")
+	}
+
+	secrets := make([]corev1.Secret, 0, len(stream))
+	for _, y := range stream {
+		s := &corev1.Secret{}
+		if err := yaml.Unmarshal(y, s); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML Secret manifest")
+		}
+		secrets = append(secrets, *s)
+	}
+
+	return secrets, nil
+}
+
 // LoadYAMLStream from the supplied file or directory. Returns an array of byte
 // arrays, where each byte array is expected to be a YAML manifest.
 func LoadYAMLStream(filesys afero.Fs, fileOrDir string) ([][]byte, error) {
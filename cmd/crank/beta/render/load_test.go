@@ -25,6 +25,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 )
@@ -158,6 +161,72 @@ func TestLoadComposition(t *testing.T) {
 	}
 }
 
+func TestLoadCompositions(t *testing.T) {
+	fs := afero.FromIOFS{FS: testdatafs}
+	pipeline := apiextensionsv1.CompositionModePipeline
+
+	type want struct {
+		comps []apiextensionsv1.Composition
+		err   error
+	}
+	cases := map[string]struct {
+		file string
+		want want
+	}{
+		"Success": {
+			file: "testdata/composition.yaml",
+			want: want{
+				comps: []apiextensionsv1.Composition{
+					{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       apiextensionsv1.CompositionKind,
+							APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+						},
+						ObjectMeta: metav1.ObjectMeta{Name: "xnopresources.nop.example.org"},
+						Spec: apiextensionsv1.CompositionSpec{
+							CompositeTypeRef: apiextensionsv1.TypeReference{
+								APIVersion: "nop.example.org/v1alpha1",
+								Kind:       "XNopResource",
+							},
+							Mode: &pipeline,
+							Pipeline: []apiextensionsv1.PipelineStep{{
+								Step:        "be-a-dummy",
+								FunctionRef: apiextensionsv1.FunctionReference{Name: "function-dummy"},
+							}},
+						},
+					},
+				},
+			},
+		},
+		"NoSuchFile": {
+			file: "testdata/nonexist.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"NotAComposition": {
+			file: "testdata/xr.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comps, err := LoadCompositions(fs, tc.file)
+
+			if diff := cmp.Diff(tc.want.comps, comps, test.EquateConditions()); diff != "" {
+				t.Errorf("LoadCompositions(..), -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("LoadCompositions(..), -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLoadFunctions(t *testing.T) {
 	fs := afero.FromIOFS{FS: testdatafs}
 
@@ -312,6 +381,184 @@ func TestLoadObservedResources(t *testing.T) {
 	}
 }
 
+func TestLoadExtraResources(t *testing.T) {
+	fs := afero.FromIOFS{FS: testdatafs}
+
+	type want struct {
+		ers []unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		file string
+		want want
+	}{
+		"Success": {
+			file: "testdata/extra.yaml",
+			want: want{
+				ers: []unstructured.Unstructured{
+					{Object: MustLoadJSON(`{
+						"apiVersion": "example.org/v1alpha1",
+						"kind": "VPC",
+						"metadata": {
+							"name": "cool-vpc",
+							"labels": {
+								"cool": "true"
+							}
+						},
+						"spec": {
+							"cidrBlock": "10.0.0.0/16"
+						}
+					}`)},
+					{Object: MustLoadJSON(`{
+						"apiVersion": "v1",
+						"kind": "ConfigMap",
+						"metadata": {
+							"name": "cool-config",
+							"namespace": "default"
+						},
+						"data": {
+							"region": "us-east-2"
+						}
+					}`)},
+				},
+			},
+		},
+		"NoSuchFile": {
+			file: "testdata/nonexist.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ers, err := LoadExtraResources(fs, tc.file)
+
+			if diff := cmp.Diff(tc.want.ers, ers); diff != "" {
+				t.Errorf("LoadExtraResources(..), -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("LoadExtraResources(..), -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadEnvironmentConfigs(t *testing.T) {
+	fs := afero.FromIOFS{FS: testdatafs}
+
+	type want struct {
+		ecs []v1alpha1.EnvironmentConfig
+		err error
+	}
+	cases := map[string]struct {
+		file string
+		want want
+	}{
+		"Success": {
+			file: "testdata/environmentconfigs.yaml",
+			want: want{
+				ecs: []v1alpha1.EnvironmentConfig{
+					{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: "apiextensions.crossplane.io/v1alpha1",
+							Kind:       "EnvironmentConfig",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cool-environment",
+							Labels: map[string]string{
+								"cool": "true",
+							},
+						},
+						Data: map[string]extv1.JSON{
+							"region": {Raw: []byte(`"us-east-2"`)},
+						},
+					},
+				},
+			},
+		},
+		"NoSuchFile": {
+			file: "testdata/nonexist.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ecs, err := LoadEnvironmentConfigs(fs, tc.file)
+
+			if diff := cmp.Diff(tc.want.ecs, ecs); diff != "" {
+				t.Errorf("LoadEnvironmentConfigs(..), -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("LoadEnvironmentConfigs(..), -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	fs := afero.FromIOFS{FS: testdatafs}
+
+	type want struct {
+		creds []corev1.Secret
+		err   error
+	}
+	cases := map[string]struct {
+		file string
+		want want
+	}{
+		"Success": {
+			file: "testdata/credentials.yaml",
+			want: want{
+				creds: []corev1.Secret{
+					{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: "v1",
+							Kind:       "Secret",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cool-secret",
+							Namespace: "crossplane-system",
+						},
+						Data: map[string][]byte{
+							"username": []byte("cool"),
+						},
+						StringData: map[string]string{
+							"password": "hunter2",
+						},
+					},
+				},
+			},
+		},
+		"NoSuchFile": {
+			file: "testdata/nonexist.yaml",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			creds, err := LoadCredentials(fs, tc.file)
+
+			if diff := cmp.Diff(tc.want.creds, creds); diff != "" {
+				t.Errorf("LoadCredentials(..), -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("LoadCredentials(..), -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLoadYAMLStream(t *testing.T) {
 	type args struct {
 		file string
@@ -23,9 +23,13 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -34,6 +38,7 @@ import (
 
 	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
@@ -62,8 +67,14 @@ type Inputs struct {
 	Composition       *apiextensionsv1.Composition
 	Functions         []pkgv1beta1.Function
 	ObservedResources []composed.Unstructured
+	ExtraResources    []unstructured.Unstructured
+	Credentials       []corev1.Secret
 	Context           map[string][]byte
 
+	// EnvironmentConfigs may be selected by Compositions that use patch and
+	// transform (P&T) resource templates.
+	EnvironmentConfigs []v1alpha1.EnvironmentConfig
+
 	// TODO(negz): Allow supplying observed XR and composed resource connection
 	// details. Maybe as Secrets? What if secret stores are in use?
 }
@@ -74,6 +85,13 @@ type Outputs struct {
 	ComposedResources []composed.Unstructured
 	Results           []unstructured.Unstructured
 
+	// PersistedCompositeResource is the XR as Crossplane would persist it
+	// after rendering a patch and transform (P&T) Composition, including any
+	// metadata and spec the Composition patched. It's used to simulate later
+	// reconciles, and isn't part of the rendered output. It's nil when
+	// rendering a Function pipeline.
+	PersistedCompositeResource *ucomposite.Unstructured
+
 	// TODO(negz): Allow returning desired XR connection details. Maybe as a
 	// Secret? Should we honor writeConnectionSecretToRef? What if secret stores
 	// are in use?
@@ -84,7 +102,13 @@ type Outputs struct {
 }
 
 // Render the desired XR and composed resources, sorted by resource name, given the supplied inputs.
+// Compositions that don't use mode: Pipeline are rendered using patch and
+// transform (P&T) resource templates.
 func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo // TODO(negz): Should we refactor to break this up a bit?
+	if in.Composition != nil && (in.Composition.Spec.Mode == nil || *in.Composition.Spec.Mode == apiextensionsv1.CompositionModeResources) {
+		return RenderPatchAndTransform(ctx, in)
+	}
+
 	// Run our Functions.
 	conns := map[string]*grpc.ClientConn{}
 	for _, fn := range in.Functions {
@@ -146,10 +170,31 @@ func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo
 		fctx.Fields[k] = v
 	}
 
+	// Status conditions returned by the Function pipeline, by type.
+	conditions := map[xpv1.ConditionType]*fnv1beta1.Condition{}
+
 	// Run any Composition Functions in the pipeline. Each Function may mutate
 	// the desired state returned by the last, and each Function may produce
 	// results.
 	for _, fn := range in.Composition.Spec.Pipeline {
+		// Like Crossplane, skip steps whose condition is false.
+		if fn.Condition != nil {
+			run, err := composite.EvaluatePipelineStepCondition(*fn.Condition, o, d, fctx)
+			if err != nil {
+				return Outputs{}, errors.Wrapf(err, "cannot evaluate condition of pipeline step %q", fn.Step)
+			}
+			if !run {
+				results = append(results, unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "render.crossplane.io/v1beta1",
+					"kind":       "Result",
+					"step":       fn.Step,
+					"severity":   fnv1beta1.Severity_SEVERITY_NORMAL.String(),
+					"message":    "skipped because its condition is false",
+				}})
+				continue
+			}
+		}
+
 		req := &fnv1beta1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
 
 		if fn.Input != nil {
@@ -160,12 +205,37 @@ func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo
 			req.Input = in
 		}
 
+		for _, cs := range fn.Credentials {
+			// For now we only support loading credentials from secrets.
+			if cs.Source != apiextensionsv1.FunctionCredentialsSourceSecret || cs.SecretRef == nil {
+				continue
+			}
+			data, ok := SecretData(in.Credentials, cs.SecretRef.Namespace, cs.SecretRef.Name)
+			if !ok {
+				return Outputs{}, errors.Errorf("cannot find Secret %q in namespace %q for credential %q of pipeline step %q - does it exist in your credentials file?", cs.SecretRef.Name, cs.SecretRef.Namespace, cs.Name, fn.Step)
+			}
+			if req.Credentials == nil {
+				req.Credentials = make(map[string]*fnv1beta1.Credentials)
+			}
+			req.Credentials[cs.Name] = &fnv1beta1.Credentials{
+				Source: &fnv1beta1.Credentials_CredentialData{
+					CredentialData: &fnv1beta1.CredentialData{Data: data},
+				},
+			}
+		}
+
 		conn, ok := conns[fn.FunctionRef.Name]
 		if !ok {
 			return Outputs{}, errors.Errorf("unknown Function %q, referenced by pipeline step %q - does it exist in your Functions file?", fn.FunctionRef.Name, fn.Step)
 		}
 
-		rsp, err := fnv1beta1.NewFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
+		var all *fnv1beta1.State
+		if fn.SendsRequiredObservedResources() {
+			all = o
+			req.Observed = composite.RequiredObservedState(o, nil)
+		}
+
+		rsp, err := RunFunction(ctx, fnv1beta1.NewFunctionRunnerServiceClient(conn), &FilteringFetcher{extra: in.ExtraResources}, req, all)
 		if err != nil {
 			return Outputs{}, errors.Wrapf(err, "cannot run pipeline step %q", fn.Step)
 		}
@@ -192,6 +262,17 @@ func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo
 				}})
 			}
 		}
+
+		// Conditions returned by later pipeline steps override those returned
+		// by earlier steps. Like Crossplane, we ignore any conditions of the
+		// types Crossplane owns.
+		for _, c := range rsp.GetConditions() {
+			t := xpv1.ConditionType(c.GetType())
+			if t == "" || t == xpv1.TypeReady || t == xpv1.TypeSynced {
+				continue
+			}
+			conditions[t] = c
+		}
 	}
 
 	desired := make([]composed.Unstructured, 0, len(d.GetResources()))
@@ -233,9 +314,111 @@ func Render(ctx context.Context, in Inputs) (Outputs, error) { //nolint:gocyclo
 	xr.SetKind(in.CompositeResource.GetKind())
 	xr.SetName(in.CompositeResource.GetName())
 
+	if err := composite.SetFunctionConditions(xr, conditions); err != nil {
+		return Outputs{}, errors.Wrap(err, "cannot set desired composite resource conditions")
+	}
+
 	return Outputs{CompositeResource: xr, ComposedResources: desired, Results: results}, nil
 }
 
+// SecretData returns the data of the named Secret. Like the API server it
+// merges any stringData into the Secret's data, so that Secrets may be written
+// by hand.
+func SecretData(secrets []corev1.Secret, namespace, name string) (map[string][]byte, bool) {
+	for _, s := range secrets {
+		if s.GetNamespace() != namespace || s.GetName() != name {
+			continue
+		}
+		data := make(map[string][]byte, len(s.Data)+len(s.StringData))
+		for k, v := range s.Data {
+			data[k] = v
+		}
+		for k, v := range s.StringData {
+			data[k] = []byte(v)
+		}
+		return data, true
+	}
+	return nil, false
+}
+
+// RunFunction runs a Function. If the Function requires extra resources it
+// fetches them and runs the Function again, until the Function returns the
+// same requirements twice in a row. If the supplied observed state isn't nil
+// the Function is also run again with the observed composed resources it
+// requires. It's a simplified version of the same logic used by Crossplane.
+func RunFunction(ctx context.Context, c fnv1beta1.FunctionRunnerServiceClient, f composite.ExtraResourcesFetcher, req *fnv1beta1.RunFunctionRequest, observed *fnv1beta1.State) (*fnv1beta1.RunFunctionResponse, error) {
+	var requirements *fnv1beta1.Requirements
+	for i := 0; i < composite.MaxRequirementsIterations; i++ {
+		rsp, err := c.RunFunction(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		newRequirements := rsp.GetRequirements()
+		n := len(newRequirements.GetExtraResources())
+		if observed != nil {
+			n += len(newRequirements.GetObservedResources())
+		}
+		if n == 0 || proto.Equal(newRequirements, requirements) {
+			return rsp, nil
+		}
+		requirements = newRequirements
+
+		extra := make(map[string]*fnv1beta1.Resources, len(requirements.GetExtraResources()))
+		for k, rs := range requirements.GetExtraResources() {
+			resources, err := f.FetchExtraResources(ctx, rs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot fetch extra resources %q", k)
+			}
+			extra[k] = resources
+		}
+		req.ExtraResources = extra
+
+		if observed != nil {
+			req.Observed = composite.RequiredObservedState(observed, requirements.GetObservedResources())
+		}
+	}
+
+	return nil, errors.Errorf("requirements didn't stabilize after %d runs", composite.MaxRequirementsIterations)
+}
+
+// A FilteringFetcher fetches extra resources from those supplied to render.
+type FilteringFetcher struct {
+	extra []unstructured.Unstructured
+}
+
+// FetchExtraResources returns the supplied extra resources that match the
+// supplied selector.
+func (f *FilteringFetcher) FetchExtraResources(_ context.Context, rs *fnv1beta1.ResourceSelector) (*fnv1beta1.Resources, error) {
+	out := &fnv1beta1.Resources{}
+	for i := range f.extra {
+		r := &f.extra[i]
+		if r.GetAPIVersion() != rs.GetApiVersion() || r.GetKind() != rs.GetKind() || r.GetNamespace() != rs.GetNamespace() {
+			continue
+		}
+
+		switch match := rs.GetMatch().(type) {
+		case *fnv1beta1.ResourceSelector_MatchName:
+			if r.GetName() != match.MatchName {
+				continue
+			}
+		case *fnv1beta1.ResourceSelector_MatchLabels:
+			if !labels.SelectorFromSet(match.MatchLabels.GetLabels()).Matches(labels.Set(r.GetLabels())) {
+				continue
+			}
+		default:
+			return nil, errors.New("extra resource selector must match either a name or labels")
+		}
+
+		o, err := composite.AsStruct(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode extra resource %q to protocol buffer Struct well-known type", r.GetName())
+		}
+		out.Items = append(out.Items, &fnv1beta1.Resource{Resource: o})
+	}
+	return out, nil
+}
+
 // SetComposedResourceMetadata sets standard, required composed resource
 // metadata. It's a simplified version of the same function used by Crossplane.
 // Notably it doesn't handle 'nested' XRs - it assumes the supplied XR should be
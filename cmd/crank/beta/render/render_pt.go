@@ -0,0 +1,290 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composition"
+)
+
+// RenderPatchAndTransform renders the desired XR and composed resources,
+// sorted by resource name, using the supplied Composition's patch and
+// transform (P&T) resource templates. It uses the same PTComposer Crossplane
+// uses, backed by the supplied observed resources and EnvironmentConfigs
+// rather than an API server.
+func RenderPatchAndTransform(ctx context.Context, in Inputs) (Outputs, error) {
+	xr := &ucomposite.Unstructured{Unstructured: *in.CompositeResource.Unstructured.DeepCopy()}
+
+	rev := composition.NewCompositionRevision(in.Composition, 1)
+	c := NewMemoryClient(xr, in.ObservedResources, in.EnvironmentConfigs)
+
+	// Like Crossplane, derive the names of composed resources from the XR's
+	// name unless it already specifies a prefix (e.g. because it's nested).
+	if err := composite.NewAPINamingConfigurator(c).Configure(ctx, xr, rev); err != nil {
+		return Outputs{}, errors.Wrap(err, "cannot configure composite resource")
+	}
+
+	if err := composite.NewAPIEnvironmentSelector(c).SelectEnvironment(ctx, xr, rev); err != nil {
+		return Outputs{}, errors.Wrap(err, "cannot select EnvironmentConfigs")
+	}
+
+	env, err := composite.NewAPIEnvironmentFetcher(c).Fetch(ctx, composite.EnvironmentFetcherRequest{
+		Composite: xr,
+		Revision:  rev,
+		Required:  rev.Spec.Environment.IsRequired(),
+	})
+	if err != nil {
+		return Outputs{}, errors.Wrap(err, "cannot fetch environment")
+	}
+
+	pt := composite.NewPTComposer(c,
+		composite.WithTemplateAssociator(composite.CompositionTemplateAssociatorFn(AssociateObservedResources(in.ObservedResources))),
+		// Leave the generateName of new composed resources alone, like the
+		// Function pipeline does.
+		composite.WithComposedNameGenerator(composite.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+		// We don't support passing in observed connection details.
+		composite.WithComposedConnectionDetailsFetcher(composite.ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+	)
+
+	res, err := pt.Compose(ctx, xr, composite.CompositionRequest{Revision: rev, Environment: env})
+	if err != nil {
+		return Outputs{}, errors.Wrap(err, "cannot compose resources")
+	}
+
+	desired := c.Applied()
+	sort.Slice(desired, func(i, j int) bool {
+		return desired[i].GetAnnotations()[AnnotationKeyCompositionResourceName] < desired[j].GetAnnotations()[AnnotationKeyCompositionResourceName]
+	})
+
+	xr.SetConditions(res.Conditions...)
+
+	// Results are identified by the composed resource template they pertain
+	// to, like the results of a Function pipeline are identified by step.
+	results := make([]unstructured.Unstructured, 0, len(res.Events))
+	for _, e := range res.Events {
+		severity := fnv1beta1.Severity_SEVERITY_NORMAL
+		if e.Type == event.TypeWarning {
+			severity = fnv1beta1.Severity_SEVERITY_WARNING
+		}
+		results = append(results, unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "render.crossplane.io/v1beta1",
+			"kind":       "Result",
+			"resource":   e.Annotations[AnnotationKeyCompositionResourceName],
+			"severity":   severity.String(),
+			"message":    e.Message,
+		}})
+	}
+
+	return Outputs{CompositeResource: desiredComposite(xr), ComposedResources: desired, Results: results, PersistedCompositeResource: xr}, nil
+}
+
+// desiredComposite returns the desired state of the supplied XR in the same
+// shape a Function pipeline returns it - its status, and enough metadata to
+// identify it.
+func desiredComposite(xr *ucomposite.Unstructured) *ucomposite.Unstructured {
+	out := ucomposite.New()
+	out.SetAPIVersion(xr.GetAPIVersion())
+	out.SetKind(xr.GetKind())
+	out.SetName(xr.GetName())
+	if s, ok := xr.Object["status"]; ok {
+		out.Object["status"] = runtime.DeepCopyJSONValue(s)
+	}
+	return out
+}
+
+// AssociateObservedResources returns a function that associates the supplied
+// Composition's resource templates with the supplied observed resources, using
+// their crossplane.io/composition-resource-name annotation. Anonymous
+// templates are associated with the XR's resource references by order.
+func AssociateObservedResources(observed []composed.Unstructured) composite.CompositionTemplateAssociatorFn {
+	return func(_ context.Context, xr resource.Composite, cts []apiextensionsv1.ComposedTemplate) ([]composite.TemplateAssociation, error) {
+		for _, t := range cts {
+			if t.Name == nil {
+				return composite.AssociateByOrder(cts, xr.GetResourceReferences()), nil
+			}
+		}
+
+		tas := make([]composite.TemplateAssociation, len(cts))
+		for i, t := range cts {
+			tas[i] = composite.TemplateAssociation{Template: t}
+			for j := range observed {
+				cd := &observed[j]
+				if cd.GetAnnotations()[AnnotationKeyCompositionResourceName] != *t.Name {
+					continue
+				}
+				tas[i].Reference = corev1.ObjectReference{
+					APIVersion: cd.GetAPIVersion(),
+					Kind:       cd.GetKind(),
+					Namespace:  cd.GetNamespace(),
+					Name:       cd.GetName(),
+				}
+				break
+			}
+		}
+		return tas, nil
+	}
+}
+
+// A MemoryClient is a Kubernetes client backed by the resources supplied to
+// render. It serves observed composed resources and EnvironmentConfigs, and
+// records the composed resources it's asked to create or patch rather than
+// persisting them. Only the methods the PTComposer, EnvironmentSelector, and
+// EnvironmentFetcher use are implemented.
+type MemoryClient struct {
+	client.Client
+
+	xr       resource.Composite
+	observed []composed.Unstructured
+	configs  []v1alpha1.EnvironmentConfig
+
+	applied []composed.Unstructured
+}
+
+// NewMemoryClient returns a MemoryClient that renders the supplied XR.
+func NewMemoryClient(xr resource.Composite, observed []composed.Unstructured, configs []v1alpha1.EnvironmentConfig) *MemoryClient {
+	return &MemoryClient{xr: xr, observed: observed, configs: configs}
+}
+
+// Applied returns the desired state of the composed resources the client was
+// asked to create or patch.
+func (c *MemoryClient) Applied() []composed.Unstructured {
+	return c.applied
+}
+
+// Get the supplied observed composed resource or EnvironmentConfig.
+func (c *MemoryClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	switch o := obj.(type) {
+	case *v1alpha1.EnvironmentConfig:
+		for i := range c.configs {
+			if c.configs[i].GetName() == key.Name {
+				c.configs[i].DeepCopyInto(o)
+				return nil
+			}
+		}
+		return kerrors.NewNotFound(schema.GroupResource{Group: v1alpha1.Group, Resource: "environmentconfigs"}, key.Name)
+	case *unstructured.Unstructured:
+		gvk := o.GroupVersionKind()
+		for i := range c.observed {
+			cd := &c.observed[i]
+			if cd.GroupVersionKind() == gvk && cd.GetNamespace() == key.Namespace && cd.GetName() == key.Name {
+				o.Object = cd.DeepCopy().UnstructuredContent()
+				return nil
+			}
+		}
+		return kerrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
+	}
+	return errors.Errorf("cannot get unsupported object of type %T", obj)
+}
+
+// List the supplied EnvironmentConfigs.
+func (c *MemoryClient) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	l, ok := list.(*v1alpha1.EnvironmentConfigList)
+	if !ok {
+		return errors.Errorf("cannot list unsupported object of type %T", list)
+	}
+
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+
+	for i := range c.configs {
+		if lo.LabelSelector != nil && !lo.LabelSelector.Matches(labels.Set(c.configs[i].GetLabels())) {
+			continue
+		}
+		l.Items = append(l.Items, *c.configs[i].DeepCopy())
+	}
+	return nil
+}
+
+// Create records the supplied composed resource.
+func (c *MemoryClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.Errorf("cannot create unsupported object of type %T", obj)
+	}
+	if c.isXR(u) {
+		return nil
+	}
+	c.applied = append(c.applied, composed.Unstructured{Unstructured: *u.DeepCopy()})
+	return nil
+}
+
+// Patch records the desired state of the supplied composed resource, then
+// merges it into the observed state like the API server would.
+func (c *MemoryClient) Patch(_ context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return errors.Errorf("cannot patch unsupported object of type %T", obj)
+	}
+	if c.isXR(u) {
+		return nil
+	}
+
+	data, err := patch.Data(obj)
+	if err != nil {
+		return errors.Wrap(err, "cannot get patch data")
+	}
+	cd := composed.New()
+	if err := json.Unmarshal(data, cd); err != nil {
+		return errors.Wrap(err, "cannot unmarshal desired composed resource")
+	}
+	c.applied = append(c.applied, *cd)
+
+	current, err := json.Marshal(u)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal observed composed resource")
+	}
+	merged, err := jsonpatch.MergePatch(current, data)
+	if err != nil {
+		return errors.Wrap(err, "cannot merge desired composed resource into observed composed resource")
+	}
+	u.Object = map[string]any{}
+	return errors.Wrap(json.Unmarshal(merged, u), "cannot unmarshal merged composed resource")
+}
+
+// Update is a no-op. Render doesn't persist the XR.
+func (c *MemoryClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return nil
+}
+
+func (c *MemoryClient) isXR(u *unstructured.Unstructured) bool {
+	return u.GroupVersionKind() == c.xr.GetObjectKind().GroupVersionKind() && u.GetName() == c.xr.GetName()
+}
@@ -0,0 +1,472 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestRenderPatchAndTransform(t *testing.T) {
+	xr := func() *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+			"apiVersion": "nop.example.org/v1alpha1",
+			"kind": "XNopResource",
+			"metadata": {
+				"name": "test-render"
+			},
+			"spec": {
+				"coolField": "I'm cool!"
+			}
+		}`)}}
+	}
+
+	comp := &apiextensionsv1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-render"},
+		Spec: apiextensionsv1.CompositionSpec{
+			Environment: &apiextensionsv1.EnvironmentConfiguration{
+				EnvironmentConfigs: []apiextensionsv1.EnvironmentSource{{
+					Type: apiextensionsv1.EnvironmentSourceTypeReference,
+					Ref:  &apiextensionsv1.EnvironmentSourceReference{Name: "cool-environment"},
+				}},
+			},
+			Resources: []apiextensionsv1.ComposedTemplate{
+				{
+					Name: ptr.To("a-cool-resource"),
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"atest.crossplane.io/v1","kind":"AComposed"}`)},
+					Patches: []apiextensionsv1.Patch{
+						{
+							Type:          apiextensionsv1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: ptr.To("spec.coolField"),
+							ToFieldPath:   ptr.To("spec.coolerField"),
+						},
+						{
+							Type:          apiextensionsv1.PatchTypeFromEnvironmentFieldPath,
+							FromFieldPath: ptr.To("region"),
+							ToFieldPath:   ptr.To("spec.region"),
+						},
+						{
+							Type:          apiextensionsv1.PatchTypeToCompositeFieldPath,
+							FromFieldPath: ptr.To("status.widgets"),
+							ToFieldPath:   ptr.To("status.widgets"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	env := []v1alpha1.EnvironmentConfig{{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-environment"},
+		Data: map[string]extv1.JSON{
+			"region": {Raw: []byte(`"us-east-2"`)},
+		},
+	}}
+
+	type want struct {
+		out Outputs
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     Inputs
+		want   want
+	}{
+		"NewComposedResources": {
+			reason: "We should render new composed resources from the Composition's templates, patching them from the XR and environment.",
+			in: Inputs{
+				CompositeResource:  xr(),
+				Composition:        comp,
+				EnvironmentConfigs: env,
+			},
+			want: want{
+				out: Outputs{
+					CompositeResource: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "nop.example.org/v1alpha1",
+						"kind": "XNopResource",
+						"metadata": {
+							"name": "test-render"
+						},
+						"status": {
+							"conditions": [{
+								"reason": "AllPatchesApplied",
+								"status": "True",
+								"type": "PatchesApplied"
+							}]
+						}
+					}`)}},
+					PersistedCompositeResource: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "nop.example.org/v1alpha1",
+						"kind": "XNopResource",
+						"metadata": {
+							"name": "test-render",
+							"labels": {
+								"crossplane.io/composite": "test-render"
+							}
+						},
+						"spec": {
+							"coolField": "I'm cool!",
+							"environmentConfigRefs": [{
+								"apiVersion": "apiextensions.crossplane.io/v1alpha1",
+								"kind": "EnvironmentConfig",
+								"name": "cool-environment"
+							}],
+							"resourceRefs": [{
+								"apiVersion": "atest.crossplane.io/v1",
+								"kind": "AComposed"
+							}]
+						},
+						"status": {
+							"conditions": [{
+								"reason": "AllPatchesApplied",
+								"status": "True",
+								"type": "PatchesApplied"
+							}]
+						}
+					}`)}},
+					ComposedResources: []composed.Unstructured{{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "atest.crossplane.io/v1",
+						"kind": "AComposed",
+						"metadata": {
+							"generateName": "test-render-",
+							"labels": {
+								"crossplane.io/claim-name": "",
+								"crossplane.io/claim-namespace": "",
+								"crossplane.io/composite": "test-render"
+							},
+							"annotations": {
+								"crossplane.io/composition-resource-name": "a-cool-resource"
+							},
+							"ownerReferences": [{
+								"apiVersion": "nop.example.org/v1alpha1",
+								"kind": "XNopResource",
+								"name": "test-render",
+								"blockOwnerDeletion": true,
+								"controller": true,
+								"uid": ""
+							}]
+						},
+						"spec": {
+							"coolerField": "I'm cool!",
+							"region": "us-east-2"
+						}
+					}`)}}},
+				},
+			},
+		},
+		"ObservedComposedResources": {
+			reason: "We should render existing composed resources, and patch the XR from their observed state.",
+			in: Inputs{
+				CompositeResource: xr(),
+				Composition:       comp,
+				ObservedResources: []composed.Unstructured{{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+					"apiVersion": "atest.crossplane.io/v1",
+					"kind": "AComposed",
+					"metadata": {
+						"name": "test-render-a",
+						"annotations": {
+							"crossplane.io/composition-resource-name": "a-cool-resource"
+						}
+					},
+					"spec": {
+						"coolerField": "I'm cool!"
+					},
+					"status": {
+						"widgets": "9001"
+					}
+				}`)}}},
+				EnvironmentConfigs: env,
+			},
+			want: want{
+				out: Outputs{
+					CompositeResource: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "nop.example.org/v1alpha1",
+						"kind": "XNopResource",
+						"metadata": {
+							"name": "test-render"
+						},
+						"status": {
+							"conditions": [{
+								"reason": "AllPatchesApplied",
+								"status": "True",
+								"type": "PatchesApplied"
+							}],
+							"widgets": "9001"
+						}
+					}`)}},
+					PersistedCompositeResource: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "nop.example.org/v1alpha1",
+						"kind": "XNopResource",
+						"metadata": {
+							"name": "test-render",
+							"labels": {
+								"crossplane.io/composite": "test-render"
+							}
+						},
+						"spec": {
+							"coolField": "I'm cool!",
+							"environmentConfigRefs": [{
+								"apiVersion": "apiextensions.crossplane.io/v1alpha1",
+								"kind": "EnvironmentConfig",
+								"name": "cool-environment"
+							}],
+							"resourceRefs": [{
+								"apiVersion": "atest.crossplane.io/v1",
+								"kind": "AComposed",
+								"name": "test-render-a"
+							}]
+						},
+						"status": {
+							"conditions": [{
+								"reason": "AllPatchesApplied",
+								"status": "True",
+								"type": "PatchesApplied"
+							}],
+							"widgets": "9001"
+						}
+					}`)}},
+					ComposedResources: []composed.Unstructured{{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+						"apiVersion": "atest.crossplane.io/v1",
+						"kind": "AComposed",
+						"metadata": {
+							"name": "test-render-a",
+							"generateName": "test-render-",
+							"labels": {
+								"crossplane.io/claim-name": "",
+								"crossplane.io/claim-namespace": "",
+								"crossplane.io/composite": "test-render"
+							},
+							"annotations": {
+								"crossplane.io/composition-resource-name": "a-cool-resource"
+							},
+							"ownerReferences": [{
+								"apiVersion": "nop.example.org/v1alpha1",
+								"kind": "XNopResource",
+								"name": "test-render",
+								"blockOwnerDeletion": true,
+								"controller": true,
+								"uid": ""
+							}]
+						},
+						"spec": {
+							"coolerField": "I'm cool!",
+							"region": "us-east-2"
+						}
+					}`)}}},
+				},
+			},
+		},
+		"MissingEnvironmentConfig": {
+			reason: "We should return an error if a required EnvironmentConfig wasn't supplied.",
+			in: Inputs{
+				CompositeResource: xr(),
+				Composition:       comp,
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := Render(context.Background(), tc.in)
+
+			// Ignore condition transition times, which are set to now.
+			ignoreTime := cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "lastTransitionTime" })
+			if diff := cmp.Diff(tc.want.out, out, cmpopts.EquateEmpty(), ignoreTime); diff != "" {
+				t.Errorf("%s\nRender(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nRender(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderOutputShape(t *testing.T) {
+	pipeline := apiextensionsv1.CompositionModePipeline
+
+	xr := func() *composite.Unstructured {
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+			"apiVersion": "nop.example.org/v1alpha1",
+			"kind": "XNopResource",
+			"metadata": {
+				"name": "test-render",
+				"labels": {
+					"cool": "very"
+				}
+			},
+			"spec": {
+				"coolField": "I'm cool!"
+			}
+		}`)}}
+	}
+
+	observed := []composed.Unstructured{{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+		"apiVersion": "atest.crossplane.io/v1",
+		"kind": "AComposed",
+		"metadata": {
+			"name": "test-render-a",
+			"annotations": {
+				"crossplane.io/composition-resource-name": "a-cool-resource"
+			}
+		},
+		"status": {
+			"widgets": "9001"
+		}
+	}`)}}}
+
+	lis := NewFunction(t, &fnv1beta1.RunFunctionResponse{
+		Desired: &fnv1beta1.State{
+			Composite: &fnv1beta1.Resource{Resource: MustStructJSON(`{"status":{"widgets":"9001"}}`)},
+			Resources: map[string]*fnv1beta1.Resource{
+				"a-cool-resource": {Resource: MustStructJSON(`{"apiVersion":"atest.crossplane.io/v1","kind":"AComposed"}`)},
+			},
+		},
+		Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_WARNING, Message: "uncool"}},
+	})
+	defer lis.Close()
+
+	pt := Inputs{
+		CompositeResource: xr(),
+		Composition: &apiextensionsv1.Composition{
+			Spec: apiextensionsv1.CompositionSpec{
+				Resources: []apiextensionsv1.ComposedTemplate{{
+					Name: ptr.To("a-cool-resource"),
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"atest.crossplane.io/v1","kind":"AComposed"}`)},
+					Patches: []apiextensionsv1.Patch{
+						{
+							Type:          apiextensionsv1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: ptr.To("spec.missingField"),
+							ToFieldPath:   ptr.To("spec.uncoolField"),
+							Policy: &apiextensionsv1.PatchPolicy{
+								FromFieldPath: ptr.To(apiextensionsv1.FromFieldPathPolicyRequired),
+								OnError:       &apiextensionsv1.PatchErrorPolicy{Action: apiextensionsv1.PatchErrorActionSkip},
+							},
+						},
+						{
+							Type:          apiextensionsv1.PatchTypeToCompositeFieldPath,
+							FromFieldPath: ptr.To("status.widgets"),
+							ToFieldPath:   ptr.To("status.widgets"),
+						},
+					},
+				}},
+			},
+		},
+		ObservedResources: observed,
+	}
+
+	fn := Inputs{
+		CompositeResource: xr(),
+		Composition: &apiextensionsv1.Composition{
+			Spec: apiextensionsv1.CompositionSpec{
+				Mode: &pipeline,
+				Pipeline: []apiextensionsv1.PipelineStep{{
+					Step:        "test",
+					FunctionRef: apiextensionsv1.FunctionReference{Name: "function-test"},
+				}},
+			},
+		},
+		Functions: []pkgv1beta1.Function{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "function-test",
+				Annotations: map[string]string{
+					AnnotationKeyRuntime:                  string(AnnotationValueRuntimeDevelopment),
+					AnnotationKeyRuntimeDevelopmentTarget: lis.Addr().String(),
+				},
+			},
+		}},
+		ObservedResources: observed,
+	}
+
+	// shape returns the top-level and metadata fields of each rendered
+	// object. The content of the XR's status depends on the
+	// Composition, so we don't compare it.
+	shape := func(t *testing.T, in Inputs) [][]string {
+		t.Helper()
+		out, err := Render(context.Background(), in)
+		if err != nil {
+			t.Fatalf("Render(...): %s", err)
+		}
+		objs := []map[string]any{out.CompositeResource.UnstructuredContent()}
+		for i := range out.ComposedResources {
+			objs = append(objs, out.ComposedResources[i].UnstructuredContent())
+		}
+		for i := range out.Results {
+			objs = append(objs, out.Results[i].UnstructuredContent())
+		}
+		s := make([][]string, 0, len(objs))
+		for _, o := range objs {
+			u := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(o)}
+			// Results are identified by the pipeline step that returned
+			// them, or the resource template they pertain to.
+			for _, k := range []string{"step", "resource"} {
+				if id, ok := u.Object[k]; ok && u.GetKind() == "Result" {
+					if id == "" {
+						t.Errorf("Render(...): result has an empty %s", k)
+					}
+					delete(u.Object, k)
+					u.Object["identifier"] = id
+				}
+			}
+			s = append(s, fields(u.Object))
+		}
+		return s
+	}
+
+	got := shape(t, pt)
+	want := shape(t, fn)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nRender(...): P&T and Function pipeline output should have the same shape: -pipeline, +P&T:\n%s", diff)
+	}
+}
+
+// fields returns the sorted top-level and metadata fields of the supplied
+// object.
+func fields(o map[string]any) []string {
+	out := make([]string, 0, len(o))
+	for k := range o {
+		out = append(out, k)
+	}
+	if m, ok := o["metadata"].(map[string]any); ok {
+		for k := range m {
+			out = append(out, "metadata."+k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
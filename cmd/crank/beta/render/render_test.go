@@ -27,11 +27,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
@@ -80,6 +84,7 @@ func TestRender(t *testing.T) {
 					CompositeResource: composite.New(),
 					Composition: &apiextensionsv1.Composition{
 						Spec: apiextensionsv1.CompositionSpec{
+							Mode: &pipeline,
 							Pipeline: []apiextensionsv1.PipelineStep{
 								{
 									// Not valid JSON.
@@ -132,6 +137,35 @@ func TestRender(t *testing.T) {
 				err: cmpopts.AnyError,
 			},
 		},
+		"MissingCredentials": {
+			args: args{
+				ctx: context.Background(),
+				in: Inputs{
+					CompositeResource: composite.New(),
+					Composition: &apiextensionsv1.Composition{
+						Spec: apiextensionsv1.CompositionSpec{
+							Mode: &pipeline,
+							Pipeline: []apiextensionsv1.PipelineStep{
+								{
+									Step:        "test",
+									FunctionRef: apiextensionsv1.FunctionReference{Name: "function-test"},
+									Credentials: []apiextensionsv1.FunctionCredentials{
+										{
+											Name:      "cool-secret",
+											Source:    apiextensionsv1.FunctionCredentialsSourceSecret,
+											SecretRef: &xpv1.SecretReference{Namespace: "default", Name: "cool-secret"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
 		"FatalResult": {
 			args: args{
 				ctx: context.Background(),
@@ -177,6 +211,64 @@ func TestRender(t *testing.T) {
 				err: cmpopts.AnyError,
 			},
 		},
+		"SkipStepWithFalseCondition": {
+			reason: "We should skip a pipeline step whose condition is false, and return a result naming it.",
+			args: args{
+				ctx: context.Background(),
+				in: Inputs{
+					CompositeResource: &composite.Unstructured{
+						Unstructured: unstructured.Unstructured{
+							Object: MustLoadJSON(`{
+								"apiVersion": "nop.example.org/v1alpha1",
+								"kind": "XNopResource",
+								"metadata": {
+									"name": "test-render"
+								},
+								"spec": {
+									"replicas": 1
+								}
+							}`),
+						},
+					},
+					Composition: &apiextensionsv1.Composition{
+						Spec: apiextensionsv1.CompositionSpec{
+							Mode: &pipeline,
+							Pipeline: []apiextensionsv1.PipelineStep{
+								{
+									// The Function isn't supplied, so running
+									// this step would return an error.
+									Step:        "test",
+									FunctionRef: apiextensionsv1.FunctionReference{Name: "function-test"},
+									Condition:   ptr.To("observed.composite.resource.spec.replicas > 1"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				out: Outputs{
+					CompositeResource: &composite.Unstructured{
+						Unstructured: unstructured.Unstructured{
+							Object: MustLoadJSON(`{
+								"apiVersion": "nop.example.org/v1alpha1",
+								"kind": "XNopResource",
+								"metadata": {
+									"name": "test-render"
+								}
+							}`),
+						},
+					},
+					Results: []unstructured.Unstructured{{Object: map[string]any{
+						"apiVersion": "render.crossplane.io/v1beta1",
+						"kind":       "Result",
+						"step":       "test",
+						"severity":   "SEVERITY_NORMAL",
+						"message":    "skipped because its condition is false",
+					}}},
+				},
+			},
+		},
 		"Success": {
 			args: args{
 				ctx: context.Background(),
@@ -353,6 +445,119 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestSecretData(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cool-secret"},
+			Data:       map[string][]byte{"username": []byte("cool"), "password": []byte("nope")},
+			StringData: map[string]string{"password": "hunter2"},
+		},
+	}
+
+	type want struct {
+		data map[string][]byte
+		ok   bool
+	}
+
+	cases := map[string]struct {
+		reason    string
+		namespace string
+		name      string
+		want      want
+	}{
+		"Found": {
+			reason:    "We should merge stringData into the data of the named Secret.",
+			namespace: "default",
+			name:      "cool-secret",
+			want: want{
+				data: map[string][]byte{"username": []byte("cool"), "password": []byte("hunter2")},
+				ok:   true,
+			},
+		},
+		"WrongNamespace": {
+			reason:    "We should not find a Secret in another namespace.",
+			namespace: "other",
+			name:      "cool-secret",
+			want: want{
+				ok: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, ok := SecretData(secrets, tc.namespace, tc.name)
+			if diff := cmp.Diff(tc.want.data, data); diff != "" {
+				t.Errorf("%s\nSecretData(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("%s\nSecretData(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFilteringFetcher(t *testing.T) {
+	extra := []unstructured.Unstructured{
+		{Object: MustLoadJSON(`{"apiVersion":"example.org/v1","kind":"VPC","metadata":{"name":"cool-vpc","labels":{"cool":"true"}}}`)},
+		{Object: MustLoadJSON(`{"apiVersion":"example.org/v1","kind":"VPC","metadata":{"name":"uncool-vpc","labels":{"cool":"false"}}}`)},
+		{Object: MustLoadJSON(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cool-config","namespace":"default"}}`)},
+	}
+
+	type want struct {
+		res *fnv1beta1.Resources
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		rs     *fnv1beta1.ResourceSelector
+		want   want
+	}{
+		"MatchName": {
+			reason: "We should return the extra resource with the selected name.",
+			rs:     &fnv1beta1.ResourceSelector{ApiVersion: "example.org/v1", Kind: "VPC", Match: &fnv1beta1.ResourceSelector_MatchName{MatchName: "uncool-vpc"}},
+			want: want{
+				res: &fnv1beta1.Resources{Items: []*fnv1beta1.Resource{{Resource: MustStructJSON(`{"apiVersion":"example.org/v1","kind":"VPC","metadata":{"name":"uncool-vpc","labels":{"cool":"false"}}}`)}}},
+			},
+		},
+		"MatchLabels": {
+			reason: "We should return the extra resources with the selected labels.",
+			rs:     &fnv1beta1.ResourceSelector{ApiVersion: "example.org/v1", Kind: "VPC", Match: &fnv1beta1.ResourceSelector_MatchLabels{MatchLabels: &fnv1beta1.MatchLabels{Labels: map[string]string{"cool": "true"}}}},
+			want: want{
+				res: &fnv1beta1.Resources{Items: []*fnv1beta1.Resource{{Resource: MustStructJSON(`{"apiVersion":"example.org/v1","kind":"VPC","metadata":{"name":"cool-vpc","labels":{"cool":"true"}}}`)}}},
+			},
+		},
+		"MatchNamespace": {
+			reason: "We should only return extra resources in the selected namespace.",
+			rs:     &fnv1beta1.ResourceSelector{ApiVersion: "v1", Kind: "ConfigMap", Namespace: "other", Match: &fnv1beta1.ResourceSelector_MatchName{MatchName: "cool-config"}},
+			want: want{
+				res: &fnv1beta1.Resources{},
+			},
+		},
+		"NoMatch": {
+			reason: "We should return an error if the selector doesn't match a name or labels.",
+			rs:     &fnv1beta1.ResourceSelector{ApiVersion: "example.org/v1", Kind: "VPC"},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &FilteringFetcher{extra: extra}
+			res, err := f.FetchExtraResources(context.Background(), tc.rs)
+			if diff := cmp.Diff(tc.want.res, res, protocmp.Transform()); diff != "" {
+				t.Errorf("%s\nFetchExtraResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nFetchExtraResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func NewFunction(t *testing.T, rsp *fnv1beta1.RunFunctionResponse) net.Listener {
 	lis, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// A Step is the result of one simulated reconcile.
+type Step struct {
+	// Outputs rendered by this step.
+	Outputs Outputs
+
+	// Diff between the outputs of the previous step and this one. Empty for
+	// the first step.
+	Diff string
+}
+
+// Simulate up to the supplied number of reconciles of the supplied inputs.
+// After each reconcile the desired XR and composed resources are fed back as
+// the observed XR and composed resources of the next reconcile, like Crossplane
+// would observe them after applying them. The supplied status overlays are
+// merged into the observed composed resources with the same
+// crossplane.io/composition-resource-name annotation, to simulate providers
+// writing their status. Simulation stops early once the observed state
+// stabilizes.
+func Simulate(ctx context.Context, in Inputs, iterations int, overlays []composed.Unstructured) ([]Step, error) {
+	steps := make([]Step, 0, iterations)
+	for i := 0; i < iterations; i++ {
+		out, err := Render(ctx, in)
+		if err != nil {
+			return steps, errors.Wrapf(err, "cannot render iteration %d", i+1)
+		}
+
+		s := Step{Outputs: out}
+		if i > 0 {
+			s.Diff = cmp.Diff(Objects(steps[i-1].Outputs), Objects(out))
+		}
+		steps = append(steps, s)
+
+		// There's no next reconcile to observe the result of the last one.
+		if i == iterations-1 {
+			return steps, nil
+		}
+
+		next, err := Observe(in, out, overlays)
+		if err != nil {
+			return steps, errors.Wrapf(err, "cannot observe the result of iteration %d", i+1)
+		}
+		if Stable(in, next) {
+			return steps, nil
+		}
+		in = next
+	}
+	return steps, nil
+}
+
+// Observe returns the inputs of the next simulated reconcile, given the inputs
+// and outputs of the last one. The desired XR and composed resources are merged
+// into the observed XR and composed resources, like the API server would merge
+// them when Crossplane applies them. New composed resources are named.
+func Observe(in Inputs, out Outputs, overlays []composed.Unstructured) (Inputs, error) {
+	next := in
+
+	// A P&T Composition may patch the XR's metadata and spec, which Crossplane
+	// persists along with its status.
+	desired := out.CompositeResource
+	if out.PersistedCompositeResource != nil {
+		desired = out.PersistedCompositeResource
+	}
+
+	xr := &ucomposite.Unstructured{}
+	if err := merge(in.CompositeResource, desired, xr); err != nil {
+		return Inputs{}, errors.Wrap(err, "cannot merge desired composite resource into observed composite resource")
+	}
+	next.CompositeResource = xr
+
+	observed := map[string]*composed.Unstructured{}
+	for i := range in.ObservedResources {
+		observed[in.ObservedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName]] = &in.ObservedResources[i]
+	}
+
+	next.ObservedResources = make([]composed.Unstructured, 0, len(out.ComposedResources))
+	for i := range out.ComposedResources {
+		dr := &out.ComposedResources[i]
+		name := dr.GetAnnotations()[AnnotationKeyCompositionResourceName]
+
+		cd := composed.New()
+		if or, ok := observed[name]; ok {
+			if err := merge(or, dr, cd); err != nil {
+				return Inputs{}, errors.Wrapf(err, "cannot merge desired composed resource %q into observed composed resource", name)
+			}
+		} else {
+			cd.Object = dr.DeepCopy().UnstructuredContent()
+		}
+
+		if cd.GetName() == "" {
+			cd.SetName(cd.GetGenerateName() + fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:5])
+		}
+
+		for j := range overlays {
+			o := &overlays[j]
+			if o.GetAnnotations()[AnnotationKeyCompositionResourceName] != name {
+				continue
+			}
+			overlaid := composed.New()
+			if err := merge(cd, o, overlaid); err != nil {
+				return Inputs{}, errors.Wrapf(err, "cannot merge status overlay into observed composed resource %q", name)
+			}
+			cd = overlaid
+		}
+
+		next.ObservedResources = append(next.ObservedResources, *cd)
+	}
+
+	return next, nil
+}
+
+// Stable returns true if the observed XR and composed resources of the
+// supplied inputs are equivalent, ignoring volatile fields.
+func Stable(a, b Inputs) bool {
+	return cmp.Equal(observedObjects(a), observedObjects(b))
+}
+
+// Objects returns the supplied outputs as an array of objects with volatile
+// fields removed, suitable for diffing.
+func Objects(out Outputs) []map[string]any {
+	objs := make([]map[string]any, 0, len(out.ComposedResources)+1)
+	if out.CompositeResource != nil {
+		objs = append(objs, out.CompositeResource.DeepCopy().UnstructuredContent())
+	}
+	for i := range out.ComposedResources {
+		objs = append(objs, out.ComposedResources[i].DeepCopy().UnstructuredContent())
+	}
+	for i := range objs {
+		RemoveVolatileFields(objs[i])
+	}
+	return objs
+}
+
+func observedObjects(in Inputs) []map[string]any {
+	return Objects(Outputs{CompositeResource: in.CompositeResource, ComposedResources: in.ObservedResources})
+}
+
+// RemoveVolatileFields removes fields that change each time an object is
+// rendered, or that are set by the API server, from the supplied object.
+func RemoveVolatileFields(obj map[string]any) {
+	for _, f := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields"} {
+		unstructured.RemoveNestedField(obj, "metadata", f)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for i := range conditions {
+		if c, ok := conditions[i].(map[string]any); ok {
+			delete(c, "lastTransitionTime")
+		}
+	}
+	if len(conditions) > 0 {
+		_ = unstructured.SetNestedSlice(obj, conditions, "status", "conditions")
+	}
+}
+
+// merge the patch object into the original object, like a JSON merge patch.
+func merge(original, patch, into any) error {
+	o, err := json.Marshal(original)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal original object")
+	}
+	p, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal patch object")
+	}
+	m, err := jsonpatch.MergePatch(o, p)
+	if err != nil {
+		return errors.Wrap(err, "cannot merge objects")
+	}
+	return errors.Wrap(json.Unmarshal(m, into), "cannot unmarshal merged object")
+}
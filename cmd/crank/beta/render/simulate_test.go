@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestSimulate(t *testing.T) {
+	// Resource b depends on the status of resource a, which is patched to the
+	// XR. It takes three reconciles for b to be rendered with a's status, and
+	// a fourth to observe that nothing changed.
+	comp := &apiextensionsv1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-simulate"},
+		Spec: apiextensionsv1.CompositionSpec{
+			Resources: []apiextensionsv1.ComposedTemplate{
+				{
+					Name: ptr.To("a"),
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"atest.crossplane.io/v1","kind":"AComposed"}`)},
+					Patches: []apiextensionsv1.Patch{{
+						Type:          apiextensionsv1.PatchTypeToCompositeFieldPath,
+						FromFieldPath: ptr.To("status.id"),
+						ToFieldPath:   ptr.To("status.aID"),
+					}},
+				},
+				{
+					Name: ptr.To("b"),
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"btest.crossplane.io/v1","kind":"BComposed"}`)},
+					Patches: []apiextensionsv1.Patch{{
+						FromFieldPath: ptr.To("status.aID"),
+						ToFieldPath:   ptr.To("spec.aID"),
+					}},
+				},
+			},
+		},
+	}
+
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+		"apiVersion": "nop.example.org/v1alpha1",
+		"kind": "XNopResource",
+		"metadata": {
+			"name": "test-simulate"
+		}
+	}`)}}
+
+	overlays := []composed.Unstructured{{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+		"apiVersion": "atest.crossplane.io/v1",
+		"kind": "AComposed",
+		"metadata": {
+			"annotations": {
+				"crossplane.io/composition-resource-name": "a"
+			}
+		},
+		"status": {
+			"id": "cool-id"
+		}
+	}`)}}}
+
+	type args struct {
+		iterations int
+		overlays   []composed.Unstructured
+	}
+	type want struct {
+		steps int
+		aID   string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Converges": {
+			reason: "We should stop simulating once the observed state stabilizes.",
+			args: args{
+				iterations: 10,
+				overlays:   overlays,
+			},
+			want: want{
+				steps: 4,
+				aID:   "cool-id",
+			},
+		},
+		"MaxIterations": {
+			reason: "We should stop simulating after the supplied number of iterations.",
+			args: args{
+				iterations: 2,
+				overlays:   overlays,
+			},
+			want: want{
+				steps: 2,
+			},
+		},
+		"NoOverlays": {
+			reason: "Without status overlays nothing is patched from a to b. The second reconcile only records the names of the composed resources.",
+			args: args{
+				iterations: 10,
+			},
+			want: want{
+				steps: 3,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			steps, err := Simulate(context.Background(), Inputs{CompositeResource: xr, Composition: comp}, tc.args.iterations, tc.args.overlays)
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nSimulate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.steps, len(steps)); diff != "" {
+				t.Errorf("%s\nSimulate(...): -want steps, +got steps:\n%s", tc.reason, diff)
+			}
+
+			out := steps[len(steps)-1].Outputs
+			b := out.ComposedResources[1]
+			aID, _, _ := unstructured.NestedString(b.Object, "spec", "aID")
+			if diff := cmp.Diff(tc.want.aID, aID); diff != "" {
+				t.Errorf("%s\nSimulate(...): -want b spec.aID, +got b spec.aID:\n%s", tc.reason, diff)
+			}
+			for i := range out.ComposedResources {
+				if out.ComposedResources[i].GetName() == "" && len(steps) > 1 {
+					t.Errorf("%s\nSimulate(...): composed resource %d wasn't named", tc.reason, i)
+				}
+			}
+		})
+	}
+}
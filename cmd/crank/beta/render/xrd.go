@@ -0,0 +1,272 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	uclaim "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// IsClaim returns true if the supplied resource is a claim of the supplied
+// XRD.
+func IsClaim(u *unstructured.Unstructured, xrd *apiextensionsv1.CompositeResourceDefinition) bool {
+	if !xrd.OffersClaim() {
+		return false
+	}
+	return u.GroupVersionKind().GroupKind() == xrd.GetClaimGroupVersionKind().GroupKind()
+}
+
+// DefaultClaim sets the defaults specified by the supplied XRD's claim schema
+// on the supplied claim, like the API server would when the claim is created.
+func DefaultClaim(cm *uclaim.Unstructured, xrd *apiextensionsv1.CompositeResourceDefinition) error {
+	crd, err := xcrd.ForCompositeResourceClaim(xrd)
+	if err != nil {
+		return errors.Wrap(err, "cannot derive claim CRD from XRD")
+	}
+	return DefaultResource(&cm.Unstructured, crd)
+}
+
+// DefaultCompositeResource sets the defaults specified by the supplied XRD's
+// schema on the supplied XR, like the API server would when the XR is created.
+func DefaultCompositeResource(xr *ucomposite.Unstructured, xrd *apiextensionsv1.CompositeResourceDefinition) error {
+	crd, err := xcrd.ForCompositeResource(xrd)
+	if err != nil {
+		return errors.Wrap(err, "cannot derive composite resource CRD from XRD")
+	}
+	return DefaultResource(&xr.Unstructured, crd)
+}
+
+// DefaultResource sets the defaults specified by the schema of the supplied
+// CRD version that matches the supplied resource's apiVersion.
+func DefaultResource(u *unstructured.Unstructured, crd *extv1.CustomResourceDefinition) error {
+	gvk := u.GroupVersionKind()
+	if gvk.Group != crd.Spec.Group || gvk.Kind != crd.Spec.Names.Kind {
+		return errors.Errorf("cannot default %s with the schema of %s", gvk.GroupKind(), schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind})
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name != gvk.Version {
+			continue
+		}
+		if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			return nil
+		}
+		return errors.Wrapf(DefaultValues(u.Object, *v.Schema.OpenAPIV3Schema), "cannot default %s", gvk)
+	}
+	return errors.Errorf("%s has no version %q", schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}, gvk.Version)
+}
+
+// DefaultValues sets the defaults specified by the supplied OpenAPI schema on
+// the supplied value. Like the API server, it sets the default of any missing
+// object property, or of any null property that isn't nullable, then recurses
+// into properties, array items, and additional properties.
+func DefaultValues(v any, s extv1.JSONSchemaProps) error { //nolint:gocyclo // Walking a schema is inherently branchy.
+	switch v := v.(type) {
+	case map[string]any:
+		for name, prop := range s.Properties {
+			if prop.Default == nil {
+				continue
+			}
+			if pv, ok := v[name]; ok && (pv != nil || prop.Nullable) {
+				continue
+			}
+			var d any
+			if err := json.Unmarshal(prop.Default.Raw, &d); err != nil {
+				return errors.Wrapf(err, "cannot unmarshal default value of property %q", name)
+			}
+			v[name] = d
+		}
+		for name, pv := range v {
+			if prop, ok := s.Properties[name]; ok {
+				if err := DefaultValues(pv, prop); err != nil {
+					return errors.Wrapf(err, "cannot default property %q", name)
+				}
+				continue
+			}
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+				if err := DefaultValues(pv, *s.AdditionalProperties.Schema); err != nil {
+					return errors.Wrapf(err, "cannot default property %q", name)
+				}
+			}
+		}
+	case []any:
+		if s.Items == nil || s.Items.Schema == nil {
+			return nil
+		}
+		for i := range v {
+			if err := DefaultValues(v[i], *s.Items.Schema); err != nil {
+				return errors.Wrapf(err, "cannot default item %d", i)
+			}
+		}
+	}
+	return nil
+}
+
+// CompositeFromClaim returns the XR Crossplane would create for the supplied
+// claim. Its spec, labels, and annotations are propagated from the claim the
+// same way the claim reconciler propagates them.
+func CompositeFromClaim(ctx context.Context, cm *uclaim.Unstructured, xrd *apiextensionsv1.CompositeResourceDefinition) (*ucomposite.Unstructured, error) {
+	xr := ucomposite.New(ucomposite.WithGroupVersionKind(xrd.GetCompositeGroupVersionKind()))
+	if err := claim.NewAPIDryRunCompositeConfigurator(&DefinitionClient{}).Configure(ctx, cm, xr); err != nil {
+		return nil, errors.Wrap(err, "cannot configure composite resource from claim")
+	}
+	return xr, nil
+}
+
+// SelectComposition selects a Composition for the supplied XR from the
+// supplied Compositions, the same way Crossplane selects one. An enforced
+// Composition always wins. Otherwise an XR's compositionRef is respected. If it
+// has neither a compositionRef nor a compositionSelector, the XRD's default
+// Composition is used if there is one. Otherwise a compatible Composition
+// matching the XR's compositionSelector, if any, is selected. Crossplane
+// selects at random if more than one Composition is compatible. To render the
+// same output each time, the first compatible Composition sorted by name is
+// selected instead.
+func SelectComposition(ctx context.Context, xr *ucomposite.Unstructured, xrd *apiextensionsv1.CompositeResourceDefinition, comps []apiextensionsv1.Composition) (*apiextensionsv1.Composition, error) {
+	c := &DefinitionClient{Definition: xrd, Compositions: comps}
+	s := composite.NewCompositionSelectorChain(
+		composite.NewEnforcedCompositionSelector(*xrd, event.NewNopRecorder()),
+		composite.NewAPIDefaultCompositionSelector(c, *meta.ReferenceTo(xrd, apiextensionsv1.CompositeResourceDefinitionGroupVersionKind), event.NewNopRecorder()),
+		composite.CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+			return selectFirstCompatible(cr, comps)
+		}),
+	)
+	if err := s.SelectComposition(ctx, xr); err != nil {
+		return nil, errors.Wrap(err, "cannot select Composition")
+	}
+
+	ref := xr.GetCompositionReference()
+	for i := range comps {
+		comp := &comps[i]
+		if comp.GetName() != ref.Name {
+			continue
+		}
+		v, k := xr.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+		if comp.Spec.CompositeTypeRef.APIVersion != v || comp.Spec.CompositeTypeRef.Kind != k {
+			return nil, errors.Errorf("selected Composition %q is not compatible with %s", comp.GetName(), xr.GetObjectKind().GroupVersionKind())
+		}
+		return comp, nil
+	}
+	return nil, errors.Errorf("cannot find selected Composition %q", ref.Name)
+}
+
+// selectFirstCompatible selects the first Composition, sorted by name, that is
+// compatible with the supplied XR and matches its compositionSelector. It does
+// nothing if the XR already references a Composition.
+func selectFirstCompatible(cr resource.Composite, comps []apiextensionsv1.Composition) error {
+	if cr.GetCompositionReference() != nil {
+		return nil
+	}
+	sel := labels.Everything()
+	if s := cr.GetCompositionSelector(); s != nil {
+		sel = labels.SelectorFromSet(s.MatchLabels)
+	}
+	v, k := cr.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	candidates := make([]string, 0, len(comps))
+	for _, comp := range comps {
+		if comp.Spec.CompositeTypeRef.APIVersion != v || comp.Spec.CompositeTypeRef.Kind != k {
+			continue
+		}
+		if !sel.Matches(labels.Set(comp.GetLabels())) {
+			continue
+		}
+		candidates = append(candidates, comp.GetName())
+	}
+	if len(candidates) == 0 {
+		return errors.New("no compatible Compositions found")
+	}
+	sort.Strings(candidates)
+	cr.SetCompositionReference(&corev1.ObjectReference{Name: candidates[0]})
+	return nil
+}
+
+// A DefinitionClient serves an XRD and its Compositions from memory. It's used
+// to drive the claim and composite resource reconcilers' configurators and
+// Composition selectors without an API server.
+type DefinitionClient struct {
+	client.Client
+
+	Definition   *apiextensionsv1.CompositeResourceDefinition
+	Compositions []apiextensionsv1.Composition
+}
+
+// Get the XRD.
+func (c *DefinitionClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	xrd, ok := obj.(*apiextensionsv1.CompositeResourceDefinition)
+	if !ok || c.Definition == nil || key.Name != c.Definition.GetName() {
+		return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	c.Definition.DeepCopyInto(xrd)
+	return nil
+}
+
+// List Compositions matching the supplied labels.
+func (c *DefinitionClient) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	cl, ok := list.(*apiextensionsv1.CompositionList)
+	if !ok {
+		return errors.Errorf("cannot list %T", list)
+	}
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+	cl.Items = make([]apiextensionsv1.Composition, 0, len(c.Compositions))
+	for i := range c.Compositions {
+		comp := c.Compositions[i]
+		if lo.LabelSelector != nil && !lo.LabelSelector.Matches(labels.Set(comp.GetLabels())) {
+			continue
+		}
+		cl.Items = append(cl.Items, comp)
+	}
+	return nil
+}
+
+// Create names the supplied object using its generateName, like a dry-run
+// create would. The name is derived from the generateName so that it's the
+// same each time a claim is rendered.
+func (c *DefinitionClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		obj.SetName(obj.GetGenerateName() + fmt.Sprintf("%x", sha256.Sum256([]byte(obj.GetGenerateName())))[:5])
+	}
+	return nil
+}
+
+// Update does nothing. Selected Compositions are recorded on the supplied
+// object.
+func (c *DefinitionClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return nil
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestDefaultValues(t *testing.T) {
+	s := extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"coolField": {Type: "string", Default: &extv1.JSON{Raw: []byte(`"cool"`)}},
+					"nullable":  {Type: "string", Nullable: true, Default: &extv1.JSON{Raw: []byte(`"default"`)}},
+					"size": {
+						Type:    "object",
+						Default: &extv1.JSON{Raw: []byte(`{}`)},
+						Properties: map[string]extv1.JSONSchemaProps{
+							"replicas": {Type: "integer", Default: &extv1.JSON{Raw: []byte(`3`)}},
+						},
+					},
+					"ports": {
+						Type: "array",
+						Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"protocol": {Type: "string", Default: &extv1.JSON{Raw: []byte(`"TCP"`)}},
+							},
+						}},
+					},
+					"tags": {
+						Type: "object",
+						AdditionalProperties: &extv1.JSONSchemaPropsOrBool{Schema: &extv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"value": {Type: "string", Default: &extv1.JSON{Raw: []byte(`"none"`)}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    string
+		want   string
+	}{
+		"MissingProperties": {
+			reason: "We should default missing properties, then recurse into their defaults.",
+			obj:    `{"spec":{}}`,
+			want:   `{"spec":{"coolField":"cool","nullable":"default","size":{"replicas":3}}}`,
+		},
+		"ExistingProperties": {
+			reason: "We shouldn't override properties that are set.",
+			obj:    `{"spec":{"coolField":"cooler","size":{"replicas":1}}}`,
+			want:   `{"spec":{"coolField":"cooler","nullable":"default","size":{"replicas":1}}}`,
+		},
+		"NullProperties": {
+			reason: "We should default null properties, unless they're nullable.",
+			obj:    `{"spec":{"coolField":null,"nullable":null}}`,
+			want:   `{"spec":{"coolField":"cool","nullable":null,"size":{"replicas":3}}}`,
+		},
+		"ArrayItemsAndAdditionalProperties": {
+			reason: "We should default array items and additional properties.",
+			obj:    `{"spec":{"ports":[{},{"protocol":"UDP"}],"tags":{"a":{}}}}`,
+			want:   `{"spec":{"coolField":"cool","nullable":"default","ports":[{"protocol":"TCP"},{"protocol":"UDP"}],"size":{"replicas":3},"tags":{"a":{"value":"none"}}}}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obj := MustLoadJSON(tc.obj)
+			if err := DefaultValues(obj, s); err != nil {
+				t.Fatalf("\n%s\nDefaultValues(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(MustLoadJSON(tc.want), obj); diff != "" {
+				t.Errorf("\n%s\nDefaultValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositeFromClaim(t *testing.T) {
+	xrd := &apiextensionsv1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xnopresources.nop.example.org"},
+		Spec: apiextensionsv1.CompositeResourceDefinitionSpec{
+			Group:      "nop.example.org",
+			Names:      extv1.CustomResourceDefinitionNames{Kind: "XNopResource", Plural: "xnopresources"},
+			ClaimNames: &extv1.CustomResourceDefinitionNames{Kind: "NopResource", Plural: "nopresources"},
+			Versions: []apiextensionsv1.CompositeResourceDefinitionVersion{{
+				Name:          "v1alpha1",
+				Served:        true,
+				Referenceable: true,
+				Schema: &apiextensionsv1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{
+					"type": "object",
+					"properties": {
+						"spec": {
+							"type": "object",
+							"properties": {
+								"coolField": {"type": "string", "default": "cool"}
+							}
+						}
+					}
+				}`)}},
+			}},
+		},
+	}
+
+	cm := &claim.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(`{
+		"apiVersion": "nop.example.org/v1alpha1",
+		"kind": "NopResource",
+		"metadata": {
+			"name": "my-claim",
+			"namespace": "default",
+			"labels": {
+				"team": "a"
+			}
+		},
+		"spec": {
+			"compositionRef": {
+				"name": "cool-composition"
+			},
+			"writeConnectionSecretToRef": {
+				"name": "my-secret"
+			}
+		}
+	}`)}}
+
+	if !IsClaim(&cm.Unstructured, xrd) {
+		t.Fatalf("IsClaim(...): want true, got false")
+	}
+	if err := DefaultClaim(cm, xrd); err != nil {
+		t.Fatalf("DefaultClaim(...): %s", err)
+	}
+	xr, err := CompositeFromClaim(context.Background(), cm, xrd)
+	if err != nil {
+		t.Fatalf("CompositeFromClaim(...): %s", err)
+	}
+
+	// The claim's writeConnectionSecretToRef is namespaced, so it shouldn't
+	// be propagated to the cluster scoped XR.
+	want := MustLoadJSON(`{
+		"apiVersion": "nop.example.org/v1alpha1",
+		"kind": "XNopResource",
+		"metadata": {
+			"name": "my-claim-a94ff",
+			"generateName": "my-claim-",
+			"labels": {
+				"team": "a",
+				"crossplane.io/claim-name": "my-claim",
+				"crossplane.io/claim-namespace": "default"
+			}
+		},
+		"spec": {
+			"claimRef": {
+				"apiVersion": "nop.example.org/v1alpha1",
+				"kind": "NopResource",
+				"name": "my-claim",
+				"namespace": "default"
+			},
+			"compositionRef": {
+				"name": "cool-composition"
+			},
+			"coolField": "cool"
+		}
+	}`)
+	if diff := cmp.Diff(want, xr.Object); diff != "" {
+		t.Errorf("CompositeFromClaim(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestSelectComposition(t *testing.T) {
+	comp := func(name string, labels map[string]string, kind string) apiextensionsv1.Composition {
+		return apiextensionsv1.Composition{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec: apiextensionsv1.CompositionSpec{
+				CompositeTypeRef: apiextensionsv1.TypeReference{APIVersion: "nop.example.org/v1alpha1", Kind: kind},
+			},
+		}
+	}
+	comps := []apiextensionsv1.Composition{
+		comp("default", map[string]string{"which": "default"}, "XNopResource"),
+		comp("enforced", map[string]string{"which": "enforced"}, "XNopResource"),
+		comp("selected", map[string]string{"which": "selected"}, "XNopResource"),
+		comp("incompatible", map[string]string{"which": "incompatible"}, "XOtherResource"),
+		comp("shared-b", map[string]string{"which": "shared"}, "XNopResource"),
+		comp("shared-a", map[string]string{"which": "shared"}, "XNopResource"),
+	}
+
+	type args struct {
+		xr  string
+		xrd apiextensionsv1.CompositeResourceDefinitionSpec
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Enforced": {
+			reason: "An enforced Composition should override the XR's compositionRef.",
+			args: args{
+				xr: `{"spec":{"compositionRef":{"name":"selected"}}}`,
+				xrd: apiextensionsv1.CompositeResourceDefinitionSpec{
+					EnforcedCompositionRef: &apiextensionsv1.CompositionReference{Name: "enforced"},
+					DefaultCompositionRef:  &apiextensionsv1.CompositionReference{Name: "default"},
+				},
+			},
+			want: want{name: "enforced"},
+		},
+		"Reference": {
+			reason: "We should respect the XR's compositionRef.",
+			args: args{
+				xr: `{"spec":{"compositionRef":{"name":"selected"}}}`,
+				xrd: apiextensionsv1.CompositeResourceDefinitionSpec{
+					DefaultCompositionRef: &apiextensionsv1.CompositionReference{Name: "default"},
+				},
+			},
+			want: want{name: "selected"},
+		},
+		"Default": {
+			reason: "We should use the XRD's default Composition if the XR has no compositionRef or compositionSelector.",
+			args: args{
+				xr: `{"spec":{}}`,
+				xrd: apiextensionsv1.CompositeResourceDefinitionSpec{
+					DefaultCompositionRef: &apiextensionsv1.CompositionReference{Name: "default"},
+				},
+			},
+			want: want{name: "default"},
+		},
+		"Selector": {
+			reason: "We should select a compatible Composition matching the XR's compositionSelector in preference to the default.",
+			args: args{
+				xr: `{"spec":{"compositionSelector":{"matchLabels":{"which":"selected"}}}}`,
+				xrd: apiextensionsv1.CompositeResourceDefinitionSpec{
+					DefaultCompositionRef: &apiextensionsv1.CompositionReference{Name: "default"},
+				},
+			},
+			want: want{name: "selected"},
+		},
+		"SeveralMatches": {
+			reason: "We should select the first compatible Composition sorted by name if several match the XR's compositionSelector.",
+			args: args{
+				xr: `{"spec":{"compositionSelector":{"matchLabels":{"which":"shared"}}}}`,
+			},
+			want: want{name: "shared-a"},
+		},
+		"NoCompatibleComposition": {
+			reason: "We should return an error if no compatible Composition matches the XR's compositionSelector.",
+			args: args{
+				xr: `{"spec":{"compositionSelector":{"matchLabels":{"which":"incompatible"}}}}`,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+		"IncompatibleReference": {
+			reason: "We should return an error if the XR references an incompatible Composition.",
+			args: args{
+				xr: `{"spec":{"compositionRef":{"name":"incompatible"}}}`,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+		"MissingReference": {
+			reason: "We should return an error if the XR references a Composition that doesn't exist.",
+			args: args{
+				xr: `{"spec":{"compositionRef":{"name":"missing"}}}`,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: MustLoadJSON(tc.args.xr)}}
+			xr.SetAPIVersion("nop.example.org/v1alpha1")
+			xr.SetKind("XNopResource")
+			xr.SetName("test-select")
+
+			xrd := &apiextensionsv1.CompositeResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "xnopresources.nop.example.org"},
+				Spec:       tc.args.xrd,
+			}
+
+			got, err := SelectComposition(context.Background(), xr, xrd, comps)
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.name, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, xr.GetCompositionReference().Name); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want compositionRef, +got compositionRef:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
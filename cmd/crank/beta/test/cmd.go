@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package test implements golden file tests for Compositions.
+package test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// Cmd arguments and flags for test subcommand.
+type Cmd struct {
+	// Arguments.
+	Directory string `arg:"" type:"existingdir" default:"." help:"A directory of test cases. Any directory containing an xr.yaml file is a test case."`
+
+	// Flags. Keep them in alphabetical order.
+	IncludeFunctionResults bool          `short:"r" help:"Include informational and warning messages from Functions in the expected output as resources of kind: Result."`
+	Timeout                time.Duration `help:"How long to run each test case before timing out." default:"1m"`
+	Update                 bool          `short:"u" help:"Update the expected output of each test case to match the rendered output, instead of comparing them."`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the test command.
+func (c *Cmd) Help() string {
+	return `
+This command runs golden file tests for Compositions. It renders the composite
+resource (XR) of each test case the same way 'crossplane beta render' does, and
+compares the result to the test case's expected output. It doesn't talk to
+Crossplane.
+
+Each directory containing an xr.yaml file is a test case. A test case may
+contain the following files:
+
+  xr.yaml                    The XR to render. Required.
+  composition.yaml           The Composition to use to render the XR. Required.
+  functions.yaml             The Functions used by the Composition's pipeline.
+  observed.yaml              The observed state of composed resources.
+  extra-resources.yaml       Extra resources Functions may require.
+  environment-configs.yaml   EnvironmentConfigs the Composition may select.
+  function-credentials.yaml  Secrets to use as Function credentials.
+  context.yaml               A YAML object of Function pipeline context.
+  expected.yaml              The expected rendered output.
+
+All files except xr.yaml and expected.yaml may instead be in any parent
+directory of the test case, up to the directory passed to this command. Test
+cases can use this to share a Composition or Functions.
+
+Expected and rendered output are compared structurally. Volatile fields like
+condition transition times are ignored. The command returns a non-zero exit
+code if any test case fails.
+
+Examples:
+
+  # Run all test cases in the current directory.
+  crossplane beta test
+
+  # Run all test cases in the tests directory.
+  crossplane beta test tests
+
+  # Create or update the expected output of all test cases.
+  crossplane beta test tests --update
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run test.
+func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error {
+	cases, err := Discover(c.fs, c.Directory)
+	if err != nil {
+		return errors.Wrap(err, "cannot discover test cases")
+	}
+	if len(cases) == 0 {
+		return errors.Errorf("no test cases found in %q", c.Directory)
+	}
+
+	failed := 0
+	for _, tc := range cases {
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		r, err := Run(ctx, c.fs, c.Directory, tc, c.IncludeFunctionResults, c.Update)
+		cancel()
+
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(k.Stdout, "ERROR  %s: %s\n", tc.Name, err)
+		case r.Updated:
+			fmt.Fprintf(k.Stdout, "UPDATE %s\n", tc.Name)
+		case !r.Passed():
+			failed++
+			fmt.Fprintf(k.Stdout, "FAIL   %s: -want expected, +got rendered:\n%s\n", tc.Name, r.Diff)
+		default:
+			fmt.Fprintf(k.Stdout, "PASS   %s\n", tc.Name)
+		}
+	}
+
+	fmt.Fprintf(k.Stdout, "\n%d passed, %d failed\n", len(cases)-failed, failed)
+	if failed > 0 {
+		return errors.Errorf("%d of %d test cases failed", failed, len(cases))
+	}
+	return nil
+}
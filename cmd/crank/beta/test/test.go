@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/cmd/crank/beta/render"
+)
+
+// The files that make up a test case. Only the composite resource must be in
+// the test case's directory. The others may be in the test case's directory,
+// or in any of its parent directories up to the root of the test suite. This
+// allows test cases to share a Composition, Functions, etc.
+const (
+	FileCompositeResource   = "xr.yaml"
+	FileComposition         = "composition.yaml"
+	FileFunctions           = "functions.yaml"
+	FileObservedResources   = "observed.yaml"
+	FileExtraResources      = "extra-resources.yaml"
+	FileEnvironmentConfigs  = "environment-configs.yaml"
+	FileFunctionCredentials = "function-credentials.yaml"
+	FileContext             = "context.yaml"
+	FileExpected            = "expected.yaml"
+)
+
+// A Case is a golden file test case.
+type Case struct {
+	// Name of the test case - its directory, relative to the test suite.
+	Name string
+
+	// Dir is the directory containing the test case.
+	Dir string
+}
+
+// A Result is the result of running a test case.
+type Result struct {
+	Case Case
+
+	// Diff between the expected and rendered output, if any.
+	Diff string
+
+	// Updated is true if the test case's expected output was updated.
+	Updated bool
+}
+
+// Passed returns true if the test case passed.
+func (r Result) Passed() bool {
+	return r.Diff == ""
+}
+
+// Discover test cases under the supplied root directory. Any directory that
+// contains a composite resource (xr.yaml) is a test case. Cases are returned
+// sorted by name.
+func Discover(fs afero.Fs, root string) ([]Case, error) {
+	cases := make([]Case, 0)
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != FileCompositeResource {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		name, err := filepath.Rel(root, dir)
+		if err != nil {
+			return errors.Wrapf(err, "cannot determine name of test case %q", dir)
+		}
+		cases = append(cases, Case{Name: name, Dir: dir})
+		return nil
+	})
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, errors.Wrapf(err, "cannot discover test cases in %q", root)
+}
+
+// FindFile finds the named file in the supplied directory, or the nearest of
+// its parent directories, stopping at the supplied root directory.
+func FindFile(fs afero.Fs, root, dir, name string) (string, bool) {
+	root = filepath.Clean(root)
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		path := filepath.Join(d, name)
+		if ok, _ := afero.Exists(fs, path); ok {
+			return path, true
+		}
+		if d == root || d == filepath.Dir(d) {
+			return "", false
+		}
+	}
+}
+
+// LoadInputs loads the render inputs for the supplied test case.
+func LoadInputs(fs afero.Fs, root string, c Case) (render.Inputs, error) { //nolint:gocyclo // Just a long list of optional inputs.
+	in := render.Inputs{
+		Functions:          []pkgv1beta1.Function{},
+		ObservedResources:  []composed.Unstructured{},
+		ExtraResources:     []unstructured.Unstructured{},
+		Credentials:        []corev1.Secret{},
+		Context:            map[string][]byte{},
+		EnvironmentConfigs: []v1alpha1.EnvironmentConfig{},
+	}
+
+	xr, err := render.LoadCompositeResource(fs, filepath.Join(c.Dir, FileCompositeResource))
+	if err != nil {
+		return render.Inputs{}, errors.Wrap(err, "cannot load composite resource")
+	}
+	in.CompositeResource = xr
+
+	path, ok := FindFile(fs, root, c.Dir, FileComposition)
+	if !ok {
+		return render.Inputs{}, errors.Errorf("cannot find %s", FileComposition)
+	}
+	if in.Composition, err = render.LoadComposition(fs, path); err != nil {
+		return render.Inputs{}, errors.Wrapf(err, "cannot load Composition from %q", path)
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileFunctions); ok {
+		if in.Functions, err = render.LoadFunctions(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load Functions from %q", path)
+		}
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileObservedResources); ok {
+		if in.ObservedResources, err = render.LoadObservedResources(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load observed composed resources from %q", path)
+		}
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileExtraResources); ok {
+		if in.ExtraResources, err = render.LoadExtraResources(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load extra resources from %q", path)
+		}
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileEnvironmentConfigs); ok {
+		if in.EnvironmentConfigs, err = render.LoadEnvironmentConfigs(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load EnvironmentConfigs from %q", path)
+		}
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileFunctionCredentials); ok {
+		if in.Credentials, err = render.LoadCredentials(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load Function credentials from %q", path)
+		}
+	}
+
+	if path, ok := FindFile(fs, root, c.Dir, FileContext); ok {
+		if in.Context, err = LoadContext(fs, path); err != nil {
+			return render.Inputs{}, errors.Wrapf(err, "cannot load Function pipeline context from %q", path)
+		}
+	}
+
+	return in, nil
+}
+
+// LoadContext loads Function pipeline context from a YAML object. Each of the
+// object's keys is a context key.
+func LoadContext(fs afero.Fs, file string) (map[string][]byte, error) {
+	y, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read context file")
+	}
+	values := map[string]any{}
+	if err := yaml.Unmarshal(y, &values); err != nil {
+		return nil, errors.Wrap(err, "cannot unmarshal context YAML")
+	}
+	fctx := make(map[string][]byte, len(values))
+	for k, v := range values {
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot marshal context value for key %q to JSON", k)
+		}
+		fctx[k] = j
+	}
+	return fctx, nil
+}
+
+// Run the supplied test case. If update is true the rendered output is
+// written to the test case's expected output file instead of being compared
+// to it.
+func Run(ctx context.Context, fs afero.Fs, root string, c Case, includeResults, update bool) (Result, error) {
+	in, err := LoadInputs(fs, root, c)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "cannot load test case inputs")
+	}
+
+	out, err := render.Render(ctx, in)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "cannot render composite resource")
+	}
+
+	got := &bytes.Buffer{}
+	if err := render.WriteYAMLStream(got, out, includeResults); err != nil {
+		return Result{}, errors.Wrap(err, "cannot write rendered output")
+	}
+
+	expected := filepath.Join(c.Dir, FileExpected)
+	if update {
+		return Result{Case: c, Updated: true}, errors.Wrapf(afero.WriteFile(fs, expected, got.Bytes(), 0o644), "cannot write expected output to %q", expected)
+	}
+
+	want, err := afero.ReadFile(fs, expected)
+	if err != nil {
+		return Result{}, errors.Wrapf(err, "cannot read expected output - run with --update to create %s", FileExpected)
+	}
+
+	diff, err := Diff(want, got.Bytes())
+	return Result{Case: c, Diff: diff}, errors.Wrap(err, "cannot compare expected and rendered output")
+}
+
+// Diff returns a structural diff between two YAML streams, ignoring volatile
+// fields like timestamps. It returns an empty string if the streams are
+// equivalent.
+func Diff(want, got []byte) (string, error) {
+	w, err := Parse(want)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse expected output")
+	}
+	g, err := Parse(got)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot parse rendered output")
+	}
+	for i := range w {
+		render.RemoveVolatileFields(w[i])
+	}
+	for i := range g {
+		render.RemoveVolatileFields(g[i])
+	}
+	return cmp.Diff(w, g), nil
+}
+
+// Parse a YAML stream into an array of objects.
+func Parse(stream []byte) ([]map[string]any, error) {
+	objs := make([]map[string]any, 0)
+	yr := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(stream)))
+	for {
+		b, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read YAML stream")
+		}
+		obj := map[string]any{}
+		if err := yaml.Unmarshal(b, &obj); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML manifest")
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+)
+
+const (
+	xr = `
+apiVersion: nop.example.org/v1alpha1
+kind: XNopResource
+metadata:
+  name: test
+spec:
+  coolField: cool
+`
+	composition = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: test
+spec:
+  compositeTypeRef:
+    apiVersion: nop.example.org/v1alpha1
+    kind: XNopResource
+  resources:
+  - name: a
+    base:
+      apiVersion: atest.crossplane.io/v1
+      kind: AComposed
+    patches:
+    - fromFieldPath: spec.coolField
+      toFieldPath: spec.coolerField
+`
+	expected = `
+---
+apiVersion: nop.example.org/v1alpha1
+kind: XNopResource
+metadata:
+  name: test
+status:
+  conditions:
+  - lastTransitionTime: "2023-11-01T00:00:00Z"
+    reason: AllPatchesApplied
+    status: "True"
+    type: PatchesApplied
+---
+apiVersion: atest.crossplane.io/v1
+kind: AComposed
+metadata:
+  annotations:
+    crossplane.io/composition-resource-name: a
+  generateName: test-
+  labels:
+    crossplane.io/claim-name: ""
+    crossplane.io/claim-namespace: ""
+    crossplane.io/composite: test
+  ownerReferences:
+  - apiVersion: nop.example.org/v1alpha1
+    blockOwnerDeletion: true
+    controller: true
+    kind: XNopResource
+    name: test
+    uid: ""
+spec:
+  coolerField: cool
+`
+)
+
+func TestDiscover(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "tests/composition.yaml", []byte(composition), 0o644)
+	_ = afero.WriteFile(fs, "tests/b/xr.yaml", []byte(xr), 0o644)
+	_ = afero.WriteFile(fs, "tests/a/xr.yaml", []byte(xr), 0o644)
+	_ = afero.WriteFile(fs, "tests/a/nested/xr.yaml", []byte(xr), 0o644)
+	_ = afero.WriteFile(fs, "tests/c/observed.yaml", []byte(""), 0o644)
+
+	want := []Case{
+		{Name: "a", Dir: "tests/a"},
+		{Name: "a/nested", Dir: "tests/a/nested"},
+		{Name: "b", Dir: "tests/b"},
+	}
+
+	got, err := Discover(fs, "tests")
+	if err != nil {
+		t.Fatalf("Discover(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Discover(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "composition.yaml", []byte(composition), 0o644)
+	_ = afero.WriteFile(fs, "tests/composition.yaml", []byte(composition), 0o644)
+	_ = afero.WriteFile(fs, "tests/a/composition.yaml", []byte(composition), 0o644)
+	_ = afero.WriteFile(fs, "tests/b/nested/xr.yaml", []byte(xr), 0o644)
+
+	type want struct {
+		path string
+		ok   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		dir    string
+		name   string
+		want   want
+	}{
+		"InCaseDirectory": {
+			reason: "We should prefer a file in the test case's directory.",
+			dir:    "tests/a",
+			name:   "composition.yaml",
+			want:   want{path: "tests/a/composition.yaml", ok: true},
+		},
+		"InParentDirectory": {
+			reason: "We should find a file in the nearest parent directory.",
+			dir:    "tests/b/nested",
+			name:   "composition.yaml",
+			want:   want{path: "tests/composition.yaml", ok: true},
+		},
+		"NotFound": {
+			reason: "We shouldn't look for files outside of the root directory.",
+			dir:    "tests/b/nested",
+			name:   "functions.yaml",
+			want:   want{ok: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, ok := FindFile(fs, "tests", tc.dir, tc.name)
+			if diff := cmp.Diff(tc.want, want{path: path, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nFindFile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	type args struct {
+		files  map[string]string
+		update bool
+	}
+	type want struct {
+		r        Result
+		expected string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Pass": {
+			reason: "A test case should pass if the rendered output matches the expected output, ignoring volatile fields.",
+			args: args{
+				files: map[string]string{
+					"tests/composition.yaml": composition,
+					"tests/a/xr.yaml":        xr,
+					"tests/a/expected.yaml":  expected,
+				},
+			},
+			want: want{
+				r: Result{Case: Case{Name: "a", Dir: "tests/a"}},
+			},
+		},
+		"Fail": {
+			reason: "A test case should fail with a diff if the rendered output doesn't match the expected output.",
+			args: args{
+				files: map[string]string{
+					"tests/composition.yaml": composition,
+					"tests/a/xr.yaml":        xr,
+					"tests/a/expected.yaml":  "---\napiVersion: nop.example.org/v1alpha1\nkind: XNopResource\n",
+				},
+			},
+			want: want{
+				r: Result{Case: Case{Name: "a", Dir: "tests/a"}, Diff: "diff"},
+			},
+		},
+		"MissingExpectedOutput": {
+			reason: "We should return an error if a test case has no expected output.",
+			args: args{
+				files: map[string]string{
+					"tests/composition.yaml": composition,
+					"tests/a/xr.yaml":        xr,
+				},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"Update": {
+			reason: "We should write the rendered output to the expected output file when updating.",
+			args: args{
+				files: map[string]string{
+					"tests/composition.yaml": composition,
+					"tests/a/xr.yaml":        xr,
+				},
+				update: true,
+			},
+			want: want{
+				r:        Result{Case: Case{Name: "a", Dir: "tests/a"}, Updated: true},
+				expected: expected,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for path, data := range tc.args.files {
+				_ = afero.WriteFile(fs, path, []byte(data), 0o644)
+			}
+
+			r, err := Run(context.Background(), fs, "tests", Case{Name: "a", Dir: "tests/a"}, false, tc.args.update)
+
+			// We only care whether there is a diff, not exactly what it is.
+			if r.Diff != "" {
+				r.Diff = "diff"
+			}
+			if diff := cmp.Diff(tc.want.r, r); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if tc.want.expected == "" {
+				return
+			}
+			got, _ := afero.ReadFile(fs, "tests/a/expected.yaml")
+			d, err := Diff([]byte(tc.want.expected), got)
+			if err != nil {
+				t.Fatalf("Diff(...): %s", err)
+			}
+			if d != "" {
+				t.Errorf("\n%s\nRun(...): -want expected output, +got expected output:\n%s", tc.reason, d)
+			}
+		})
+	}
+}
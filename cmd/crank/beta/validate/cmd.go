@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate implements offline validation of resources against the
+// schemas of their CustomResourceDefinitions.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// Cmd arguments and flags for validate subcommand.
+type Cmd struct {
+	// Arguments.
+	Extensions string `arg:"" help:"Comma-separated YAML files, package (.xpkg) files, package cache (.gz) files, or directories of them, containing the CustomResourceDefinitions or CompositeResourceDefinitions of the resources to validate."`
+	Resources  string `arg:"" help:"A YAML file or directory of YAML files specifying the resources to validate. Use - to read resources from stdin."`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the validate command.
+func (c *Cmd) Help() string {
+	return `
+This command validates resources against the OpenAPI schemas of their
+CustomResourceDefinitions (CRDs), the way the API server would validate them.
+It reports fields that aren't declared in the schema, invalid object metadata,
+and values that violate the schema - for example values of the wrong type,
+values that don't match a pattern, and missing required fields. CEL validation
+rules (x-kubernetes-validations) aren't evaluated. It doesn't talk to
+Crossplane or the API server.
+
+CRDs may be loaded from YAML files, from Crossplane package (.xpkg) files, or
+from a Crossplane package cache, in which each package is stored as a .gz
+file. CompositeResourceDefinitions (XRDs) are converted to the CRDs of their
+composite resource (XR) and claim. Resources without a CRD aren't validated.
+The XR output by crossplane beta render only contains its desired status, so
+missing required fields aren't reported for XRs without a spec.
+
+Each error is reported with the file the resource was loaded from, the
+resource's index in the file's YAML stream, and the resource's kind and name.
+The command returns a non-zero exit code if any resource is invalid.
+
+Examples:
+
+  # Validate the output of crossplane beta render against provider CRDs.
+  crossplane beta render xr.yaml composition.yaml functions.yaml \
+    | crossplane beta validate crds/ -
+
+  # Validate resources against the CRDs in a package.
+  crossplane beta validate provider-aws-s3.xpkg resources.yaml
+
+  # Validate resources against the CRDs in a copy of the package cache.
+  crossplane beta validate cache/ resources/
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run validate.
+func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error {
+	crds, err := LoadCRDs(c.fs, strings.Split(c.Extensions, ",")...)
+	if err != nil {
+		return errors.Wrap(err, "cannot load CustomResourceDefinitions")
+	}
+
+	rs, err := LoadResources(c.fs, c.Resources, os.Stdin)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load resources from %q", c.Resources)
+	}
+
+	results, err := Validate(context.Background(), crds, rs)
+	if err != nil {
+		return errors.Wrap(err, "cannot validate resources")
+	}
+
+	invalid := 0
+	for _, r := range results {
+		for _, w := range r.Warnings {
+			fmt.Fprintf(k.Stderr, "WARN  %s: %s\n", r.Name(), w)
+		}
+		if len(r.Errors) == 0 {
+			continue
+		}
+		invalid++
+		for _, e := range r.Errors {
+			fmt.Fprintf(k.Stdout, "ERROR %s: %s\n", r.Name(), e)
+		}
+	}
+
+	fmt.Fprintf(k.Stdout, "\n%d valid, %d invalid, %d resources validated against %d CRDs\n", len(results)-invalid, invalid, len(results), len(crds))
+	if invalid > 0 {
+		return errors.Errorf("%d of %d resources are invalid", invalid, len(results))
+	}
+	return nil
+}
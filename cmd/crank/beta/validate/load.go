@@ -0,0 +1,285 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// The extension of package content stored in a Crossplane package cache.
+const cacheExt = ".gz"
+
+// A Resource to validate.
+type Resource struct {
+	// Source of the resource - its file and its index in that file's YAML
+	// stream, e.g. output.yaml:2.
+	Source string
+
+	// Object to validate.
+	Object *unstructured.Unstructured
+}
+
+// LoadCRDs loads CustomResourceDefinitions from the supplied paths. Each path
+// may be a YAML file, a package (.xpkg) file, a package cache (.gz) file, or a
+// directory of any of these. CompositeResourceDefinitions are converted to the
+// CRDs of their composite resource and claim. Other kinds of resource, like
+// package metadata, are ignored.
+func LoadCRDs(fs afero.Fs, paths ...string) ([]*extv1.CustomResourceDefinition, error) {
+	crds := make([]*extv1.CustomResourceDefinition, 0)
+	for _, p := range paths {
+		files, err := extensionFiles(fs, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot find extensions in %q", p)
+		}
+		for _, f := range files {
+			stream, err := loadExtensionStream(fs, f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot load extensions from %q", f)
+			}
+			c, err := ParseCRDs(stream)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot parse extensions from %q", f)
+			}
+			crds = append(crds, c...)
+		}
+	}
+	return crds, nil
+}
+
+// extensionFiles returns the supplied path if it's a file, or the YAML,
+// package, and package cache files in it if it's a directory.
+func extensionFiles(fs afero.Fs, path string) ([]string, error) {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot stat file")
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := afero.ReadDir(fs, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read directory")
+	}
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch filepath.Ext(e.Name()) {
+		case ".yaml", ".yml", xpkg.XpkgExtension, cacheExt:
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+// loadExtensionStream returns the YAML stream in the supplied file. Packages
+// and package cache files contain the package's YAML stream.
+func loadExtensionStream(fs afero.Fs, file string) ([]byte, error) {
+	switch filepath.Ext(file) {
+	case xpkg.XpkgExtension:
+		return loadPackageStream(fs, file)
+	case cacheExt:
+		c := xpkg.NewFsPackageCache(filepath.Dir(file), fs)
+		rc, err := c.Get(strings.TrimSuffix(filepath.Base(file), cacheExt))
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot open package cache file")
+		}
+		defer rc.Close() //nolint:errcheck // Only open for reading.
+		b, err := io.ReadAll(rc)
+		return b, errors.Wrap(err, "cannot read package cache file")
+	default:
+		b, err := afero.ReadFile(fs, file)
+		return b, errors.Wrap(err, "cannot read file")
+	}
+}
+
+// loadPackageStream returns the package.yaml stream in the supplied package
+// (.xpkg) file.
+func loadPackageStream(fs afero.Fs, file string) ([]byte, error) {
+	img, err := tarball.Image(func() (io.ReadCloser, error) { return fs.Open(file) }, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load package image")
+	}
+
+	rc := mutate.Extract(img)
+	defer rc.Close() //nolint:errcheck // Only open for reading.
+
+	t := tar.NewReader(rc)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.Errorf("package has no %s file", xpkg.StreamFile)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read package filesystem")
+		}
+		if h.Name != xpkg.StreamFile {
+			continue
+		}
+		b, err := io.ReadAll(t)
+		return b, errors.Wrapf(err, "cannot read %s", xpkg.StreamFile)
+	}
+}
+
+// ParseCRDs parses CustomResourceDefinitions from the supplied YAML stream.
+// CompositeResourceDefinitions are converted to the CRDs of their composite
+// resource and claim. Other kinds of resource are ignored.
+func ParseCRDs(stream []byte) ([]*extv1.CustomResourceDefinition, error) {
+	docs, err := parseStream(bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]*extv1.CustomResourceDefinition, 0)
+	for _, y := range docs {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(y, u); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML manifest")
+		}
+
+		switch u.GroupVersionKind().GroupKind() {
+		case extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition").GroupKind():
+			crd := &extv1.CustomResourceDefinition{}
+			if err := yaml.Unmarshal(y, crd); err != nil {
+				return nil, errors.Wrap(err, "cannot parse YAML CustomResourceDefinition manifest")
+			}
+			crds = append(crds, crd)
+		case apiextensionsv1.CompositeResourceDefinitionGroupVersionKind.GroupKind():
+			xrd := &apiextensionsv1.CompositeResourceDefinition{}
+			if err := yaml.Unmarshal(y, xrd); err != nil {
+				return nil, errors.Wrap(err, "cannot parse YAML CompositeResourceDefinition manifest")
+			}
+			crd, err := xcrd.ForCompositeResource(xrd)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot derive composite resource CRD from %q", xrd.GetName())
+			}
+			crds = append(crds, crd)
+			if !xrd.OffersClaim() {
+				continue
+			}
+			crd, err = xcrd.ForCompositeResourceClaim(xrd)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot derive claim CRD from %q", xrd.GetName())
+			}
+			crds = append(crds, crd)
+		}
+	}
+	return crds, nil
+}
+
+// LoadResources loads resources to validate from the supplied YAML file or
+// directory of YAML files. If the path is "-" resources are read from the
+// supplied reader instead.
+func LoadResources(fs afero.Fs, path string, stdin io.Reader) ([]Resource, error) {
+	if path == "-" {
+		return parseResources("stdin", stdin)
+	}
+
+	files := []string{path}
+	info, err := fs.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot stat file")
+	}
+	if info.IsDir() {
+		entries, err := afero.ReadDir(fs, path)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read directory")
+		}
+		files = make([]string, 0, len(entries))
+		for _, e := range entries {
+			switch filepath.Ext(e.Name()) {
+			case ".yaml", ".yml":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(path, e.Name()))
+				}
+			}
+		}
+	}
+
+	rs := make([]Resource, 0)
+	for _, file := range files {
+		f, err := fs.Open(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot open %q", file)
+		}
+		r, err := parseResources(file, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load resources from %q", file)
+		}
+		rs = append(rs, r...)
+	}
+	return rs, nil
+}
+
+func parseResources(source string, r io.Reader) ([]Resource, error) {
+	docs, err := parseStream(r)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]Resource, 0, len(docs))
+	for i, y := range docs {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(y, u); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse YAML manifest %d", i+1)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		rs = append(rs, Resource{Source: fmt.Sprintf("%s:%d", source, i+1), Object: u})
+	}
+	return rs, nil
+}
+
+// parseStream splits the supplied YAML stream into YAML manifests.
+func parseStream(r io.Reader) ([][]byte, error) {
+	out := make([][]byte, 0)
+	yr := yaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		b, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML stream")
+		}
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		out = append(out, b)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/render"
+	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/pkg/validation/resource"
+)
+
+// A Result is the result of validating a resource.
+type Result struct {
+	Resource Resource
+
+	// Warnings about the resource, e.g. that it has no CRD.
+	Warnings []string
+
+	// Errors found validating the resource.
+	Errors field.ErrorList
+}
+
+// Name returns a human readable name for the validated resource, including
+// where it was loaded from.
+func (r Result) Name() string {
+	u := r.Resource.Object
+	name := u.GetName()
+	if name == "" && u.GetGenerateName() != "" {
+		name = u.GetGenerateName() + "*"
+	}
+	if a := u.GetAnnotations()[render.AnnotationKeyCompositionResourceName]; a != "" {
+		name = fmt.Sprintf("%s (%s)", name, a)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Resource.Source, u.GetKind(), name)
+}
+
+// Validate the supplied resources against the supplied CRDs.
+func Validate(ctx context.Context, crds []*extv1.CustomResourceDefinition, rs []Resource) ([]Result, error) {
+	m := make(map[schema.GroupKind]apiextensions.CustomResourceDefinition, len(crds))
+	xrs := map[schema.GroupKind]bool{}
+	for _, crd := range crds {
+		if isCompositeCRD(crd) {
+			xrs[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = true
+		}
+		internal := apiextensions.CustomResourceDefinition{}
+		if err := extv1.Convert_v1_CustomResourceDefinition_To_apiextensions_CustomResourceDefinition(crd, &internal, nil); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert CustomResourceDefinition %q", crd.GetName())
+		}
+		m[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = internal
+	}
+
+	v, err := resource.NewValidator(resource.WithCRDGetterFromMap(m))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create validator")
+	}
+
+	results := make([]Result, 0, len(rs))
+	for _, r := range rs {
+		warns, errs := v.Validate(ctx, withoutUnresolvedOwners(r.Object))
+		if xrs[r.Object.GroupVersionKind().GroupKind()] && isRenderedComposite(r.Object) {
+			errs = withoutRequired(errs)
+		}
+		results = append(results, Result{Resource: r, Warnings: warns, Errors: errs})
+	}
+	return results, nil
+}
+
+// isCompositeCRD returns true if the supplied CRD is the CRD of a composite
+// resource (XR), i.e. it was generated from an XRD.
+func isCompositeCRD(crd *extv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Spec.Names.Categories {
+		if c == xcrd.CategoryComposite {
+			return true
+		}
+	}
+	return false
+}
+
+// isRenderedComposite returns true if the supplied XR looks like the XR output
+// by crossplane beta render. Render only outputs the XR's desired state - its
+// name and status - which Crossplane applies on top of the existing XR.
+func isRenderedComposite(u *unstructured.Unstructured) bool {
+	_, ok := u.Object["spec"]
+	return !ok
+}
+
+// withoutRequired returns the supplied errors, except those about missing
+// required fields. A rendered XR is merged with the existing XR, so its
+// required fields are supplied by the existing XR.
+func withoutRequired(errs field.ErrorList) field.ErrorList {
+	out := make(field.ErrorList, 0, len(errs))
+	for _, err := range errs {
+		if err.Type == field.ErrorTypeRequired {
+			continue
+		}
+		out = append(out, err)
+	}
+	return out
+}
+
+// withoutUnresolvedOwners returns a copy of the supplied resource without any
+// owner references that have no UID. Resources rendered by crossplane beta
+// render reference an XR that was never created, so their owner references
+// have no UID. The API server would reject them, but Crossplane wouldn't create
+// them that way.
+func withoutUnresolvedOwners(u *unstructured.Unstructured) *unstructured.Unstructured {
+	out := u.DeepCopy()
+	refs := out.GetOwnerReferences()
+	if len(refs) == 0 {
+		return out
+	}
+	resolved := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.UID != "" {
+			resolved = append(resolved, ref)
+		}
+	}
+	out.SetOwnerReferences(resolved)
+	return out
+}
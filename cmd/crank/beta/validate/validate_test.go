@@ -0,0 +1,361 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/beta/render"
+)
+
+const (
+	crd = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: buckets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Bucket
+    plural: buckets
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required:
+            - region
+            properties:
+              region:
+                type: string
+`
+	xrd = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xnopresources.nop.example.org
+spec:
+  group: nop.example.org
+  names:
+    kind: XNopResource
+    plural: xnopresources
+  claimNames:
+    kind: NopResource
+    plural: nopresources
+  versions:
+  - name: v1alpha1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+`
+	xrdWithSpec = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xnopresources.nop.example.org
+spec:
+  group: nop.example.org
+  names:
+    kind: XNopResource
+    plural: xnopresources
+  versions:
+  - name: v1alpha1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required:
+            - coolField
+            properties:
+              coolField:
+                type: string
+`
+	meta = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-example
+`
+	resources = `
+---
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  generateName: test-
+  ownerReferences:
+  - apiVersion: nop.example.org/v1alpha1
+    kind: XNopResource
+    name: test
+    uid: ""
+spec:
+  region: us-east-2
+---
+apiVersion: example.org/v1
+kind: Bucket
+metadata:
+  name: test-invalid
+spec:
+  regoin: us-east-2
+---
+apiVersion: example.org/v1
+kind: Queue
+metadata:
+  name: test-unknown
+`
+)
+
+func TestLoadCRDs(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "crds/bucket.yaml", []byte(crd), 0o644)
+	_ = afero.WriteFile(fs, "crds/xrd.yml", []byte(xrd), 0o644)
+	_ = afero.WriteFile(fs, "crds/README.md", []byte("Not YAML"), 0o644)
+
+	gz := &bytes.Buffer{}
+	w := gzip.NewWriter(gz)
+	_, _ = w.Write([]byte(meta + "---" + crd))
+	_ = w.Close()
+	_ = afero.WriteFile(fs, "cache/provider-example.gz", gz.Bytes(), 0o644)
+
+	cases := map[string]struct {
+		reason string
+		paths  []string
+		want   []string
+	}{
+		"YAMLDirectory": {
+			reason: "We should load CRDs from YAML files, and derive the CRDs of XRDs' XRs and claims.",
+			paths:  []string{"crds"},
+			want:   []string{"buckets.example.org", "xnopresources.nop.example.org", "nopresources.nop.example.org"},
+		},
+		"PackageCache": {
+			reason: "We should load CRDs from package cache files, ignoring package metadata.",
+			paths:  []string{"cache/provider-example.gz"},
+			want:   []string{"buckets.example.org"},
+		},
+		"SeveralPaths": {
+			reason: "We should load CRDs from all of the supplied paths.",
+			paths:  []string{"crds/xrd.yml", "cache"},
+			want:   []string{"xnopresources.nop.example.org", "nopresources.nop.example.org", "buckets.example.org"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crds, err := LoadCRDs(fs, tc.paths...)
+			if err != nil {
+				t.Fatalf("\n%s\nLoadCRDs(...): %s", tc.reason, err)
+			}
+			got := make([]string, 0, len(crds))
+			for _, crd := range crds {
+				got = append(got, crd.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLoadCRDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLoadResources(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "resources/a.yaml", []byte(resources), 0o644)
+	_ = afero.WriteFile(fs, "resources/b.yaml", []byte(crd), 0o644)
+
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   []string
+	}{
+		"Directory": {
+			reason: "We should load resources from each YAML file in a directory, recording their source.",
+			path:   "resources",
+			want:   []string{"resources/a.yaml:1", "resources/a.yaml:2", "resources/a.yaml:3", "resources/b.yaml:1"},
+		},
+		"Stdin": {
+			reason: "We should load resources from stdin if the path is -.",
+			path:   "-",
+			want:   []string{"stdin:1", "stdin:2", "stdin:3"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rs, err := LoadResources(fs, tc.path, strings.NewReader(resources))
+			if err != nil {
+				t.Fatalf("\n%s\nLoadResources(...): %s", tc.reason, err)
+			}
+			got := make([]string, 0, len(rs))
+			for _, r := range rs {
+				got = append(got, r.Source)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLoadResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	crds, err := ParseCRDs([]byte(crd))
+	if err != nil {
+		t.Fatalf("ParseCRDs(...): %s", err)
+	}
+	rs, err := LoadResources(afero.NewMemMapFs(), "-", strings.NewReader(resources))
+	if err != nil {
+		t.Fatalf("LoadResources(...): %s", err)
+	}
+
+	results, err := Validate(context.Background(), crds, rs)
+	if err != nil {
+		t.Fatalf("Validate(...): %s", err)
+	}
+
+	type result struct {
+		Name     string
+		Warnings []string
+		Errors   []string
+	}
+	want := []result{
+		{
+			// Owner references without a UID are ignored.
+			Name: "stdin:1 Bucket/test-*",
+		},
+		{
+			Name: "stdin:2 Bucket/test-invalid",
+			Errors: []string{
+				field.Forbidden(field.NewPath("spec.regoin"), "field not declared in schema").Error(),
+				field.Required(field.NewPath("spec", "region"), "").Error(),
+			},
+		},
+		{
+			Name:     "stdin:3 Queue/test-unknown",
+			Warnings: []string{"no CustomResourceDefinition found for Queue.example.org - skipping validation"},
+		},
+	}
+
+	got := make([]result, 0, len(results))
+	for _, r := range results {
+		res := result{Name: r.Name(), Warnings: r.Warnings}
+		for _, e := range r.Errors {
+			res.Errors = append(res.Errors, e.Error())
+		}
+		got = append(got, res)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestValidateRenderOutput(t *testing.T) {
+	xr := composite.New()
+	if err := yaml.Unmarshal([]byte(`
+apiVersion: nop.example.org/v1alpha1
+kind: XNopResource
+metadata:
+  name: test-render
+spec:
+  coolField: us-east-2
+`), xr); err != nil {
+		t.Fatalf("yaml.Unmarshal(...): %s", err)
+	}
+
+	comp := &apiextensionsv1.Composition{}
+	if err := yaml.Unmarshal([]byte(`
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: xnopresources.nop.example.org
+spec:
+  compositeTypeRef:
+    apiVersion: nop.example.org/v1alpha1
+    kind: XNopResource
+  resources:
+  - name: bucket
+    base:
+      apiVersion: example.org/v1
+      kind: Bucket
+      spec: {}
+    patches:
+    - type: FromCompositeFieldPath
+      fromFieldPath: spec.coolField
+      toFieldPath: spec.region
+`), comp); err != nil {
+		t.Fatalf("yaml.Unmarshal(...): %s", err)
+	}
+
+	out, err := render.Render(context.Background(), render.Inputs{CompositeResource: xr, Composition: comp})
+	if err != nil {
+		t.Fatalf("render.Render(...): %s", err)
+	}
+	stream := &bytes.Buffer{}
+	if err := render.WriteYAMLStream(stream, out, false); err != nil {
+		t.Fatalf("render.WriteYAMLStream(...): %s", err)
+	}
+
+	crds, err := ParseCRDs([]byte(crd + "---" + xrdWithSpec))
+	if err != nil {
+		t.Fatalf("ParseCRDs(...): %s", err)
+	}
+	rs, err := LoadResources(afero.NewMemMapFs(), "-", stream)
+	if err != nil {
+		t.Fatalf("LoadResources(...): %s", err)
+	}
+
+	results, err := Validate(context.Background(), crds, rs)
+	if err != nil {
+		t.Fatalf("Validate(...): %s", err)
+	}
+
+	// The rendered XR only contains its desired status, so it has no spec.
+	// Crossplane merges it with the existing XR, so it shouldn't be
+	// reported as missing its required spec.
+	want := []string{"stdin:1 XNopResource/test-render", "stdin:2 Bucket/test-render-* (bucket)"}
+	got := make([]string, 0, len(results))
+	for _, r := range results {
+		got = append(got, r.Name())
+		if len(r.Warnings) > 0 || len(r.Errors) > 0 {
+			t.Errorf("Validate(...): %s: unexpected warnings %v and errors %v", r.Name(), r.Warnings, r.Errors.ToAggregate())
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate(...): -want, +got:\n%s", diff)
+	}
+}
@@ -28,8 +28,16 @@ import (
 	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/afero"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
@@ -98,6 +106,15 @@ type startCommand struct {
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
 
+	MaxCachedFunctionResponses int `help:"The maximum number of Composition Function responses to cache until their TTL expires. Set to 0 to disable caching." default:"1000"`
+	MaxFunctionMessageSize     int `help:"The maximum size, in MiB, of the gRPC messages Crossplane sends to and receives from Composition Functions." default:"4"`
+
+	MaxWebAssemblyFunctionMemory int           `help:"The maximum amount of memory, in MiB, a WebAssembly Composition Function may use while running." default:"64"`
+	WebAssemblyFunctionTimeout   time.Duration `help:"The maximum amount of time a WebAssembly Composition Function may run for." default:"10s"`
+
+	TracingOTLPEndpoint string `placeholder:"host:port" help:"Export OpenTelemetry traces of composite resource reconciles and Composition Function pipelines to this OTLP gRPC endpoint. Tracing is disabled if unset." env:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool   `help:"Export OpenTelemetry traces to the OTLP gRPC endpoint without TLS." env:"TRACING_OTLP_INSECURE"`
+
 	WebhookEnabled bool `help:"Enable webhook configuration." default:"true" env:"WEBHOOK_ENABLED"`
 
 	TLSServerSecretName string `help:"The name of the TLS Secret that will store Crossplane's server certificate." env:"TLS_SERVER_SECRET_NAME"`
@@ -105,10 +122,12 @@ type startCommand struct {
 	TLSClientSecretName string `help:"The name of the TLS Secret that will be store Crossplane's client certificate." env:"TLS_CLIENT_SECRET_NAME"`
 	TLSClientCertsDir   string `help:"The path of the folder which will store TLS client certificate of Crossplane." env:"TLS_CLIENT_CERTS_DIR"`
 
-	EnableEnvironmentConfigs   bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
-	EnableExternalSecretStores bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
-	EnableUsages               bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
-	EnableRealtimeCompositions bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableEnvironmentConfigs           bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
+	EnableExternalSecretStores         bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
+	EnableUsages                       bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
+	EnableRealtimeCompositions         bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableWebAssemblyFunctions         bool `group:"Alpha Features:" help:"Enable support for Composition Functions packaged as WebAssembly modules, which run inside the Crossplane pod."`
+	EnableFunctionIdentityVerification bool `group:"Alpha Features:" help:"Enable verifying that a Composition Function's server certificate has the Function's identity before calling it."`
 
 	EnableCompositionFunctions               bool `group:"Beta Features:" default:"true" help:"Enable support for Composition Functions."`
 	EnableCompositionWebhookSchemaValidation bool `group:"Beta Features:" default:"true" help:"Enable support for Composition validation using schemas."`
@@ -121,6 +140,14 @@ type startCommand struct {
 	EnableCompositionRevisions bool `default:"true" hidden:""`
 }
 
+// Validate the flags of the start command.
+func (c *startCommand) Validate() error {
+	if c.MaxWebAssemblyFunctionMemory <= 0 || c.MaxWebAssemblyFunctionMemory > xfn.MaxWASMMemoryLimit>>20 {
+		return errors.Errorf("--max-web-assembly-function-memory must be between 1 and %d MiB", xfn.MaxWASMMemoryLimit>>20)
+	}
+	return nil
+}
+
 // Run core Crossplane controllers.
 func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //nolint:gocyclo // Only slightly over.
 	cfg, err := ctrl.GetConfig()
@@ -189,11 +216,40 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Features:                &feature.Flags{},
 	}
 
+	// Tracing is a no-op unless an OTLP endpoint is configured.
+	var tp trace.TracerProvider = noop.NewTracerProvider()
+	if c.TracingOTLPEndpoint != "" {
+		eo := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.TracingOTLPEndpoint)}
+		if c.TracingOTLPInsecure {
+			eo = append(eo, otlptracegrpc.WithInsecure())
+		}
+		exp, err := otlptracegrpc.New(context.Background(), eo...)
+		if err != nil {
+			return errors.Wrap(err, "cannot create OTLP trace exporter")
+		}
+		stp := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exp),
+			sdktrace.WithResource(sdkresource.NewSchemaless(attribute.String("service.name", "crossplane"))),
+		)
+		defer stp.Shutdown(context.Background()) //nolint:errcheck // There's nothing to do if we can't flush traces on exit.
+		tp = stp
+		log.Info("Exporting OpenTelemetry traces", "endpoint", c.TracingOTLPEndpoint)
+	}
+
 	if !c.EnableCompositionRevisions {
 		log.Info("CompositionRevisions feature is GA and cannot be disabled. The --enable-composition-revisions flag will be removed in a future release.")
 	}
 
-	var functionRunner *xfn.PackagedFunctionRunner
+	fo := []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)}
+	if c.CABundlePath != "" {
+		rootCAs, err := ParseCertificatesFromPath(c.CABundlePath)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse CA bundle")
+		}
+		fo = append(fo, xpkg.WithCustomCA(rootCAs))
+	}
+
+	var functionRunner xfn.FunctionRunner
 	if c.EnableCompositionFunctions {
 		o.Features.Enable(features.EnableBetaCompositionFunctions)
 		log.Info("Beta feature enabled", "flag", features.EnableBetaCompositionFunctions)
@@ -210,17 +266,59 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		m := xfn.NewMetrics()
 		metrics.Registry.MustRegister(m)
 
-		// We want all XR controllers to share the same gRPC clients.
-		functionRunner = xfn.NewPackagedFunctionRunner(mgr.GetClient(),
+		ro := []xfn.PackagedFunctionRunnerOption{
 			xfn.WithLogger(log),
 			xfn.WithTLSConfig(clienttls),
-			xfn.WithInterceptorCreators(m),
-		)
+			xfn.WithInterceptorCreators(m, xfn.NewTracing(tp, propagation.TraceContext{})),
+			xfn.WithMaxMessageSize(c.MaxFunctionMessageSize << 20),
+		}
+		if c.EnableFunctionIdentityVerification {
+			o.Features.Enable(features.EnableAlphaFunctionIdentityVerification)
+			log.Info("Alpha feature enabled", "flag", features.EnableAlphaFunctionIdentityVerification)
+			ro = append(ro, xfn.WithFunctionIdentityVerification())
+		}
+
+		// We want all XR controllers to share the same gRPC clients.
+		pfr := xfn.NewPackagedFunctionRunner(mgr.GetClient(), ro...)
 
 		// Periodically remove clients for Functions that no longer exist.
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		go functionRunner.GarbageCollectConnections(ctx, 10*time.Minute)
+		go pfr.GarbageCollectConnections(ctx, 10*time.Minute)
+
+		var fr xfn.FunctionRunner = pfr
+		if c.EnableWebAssemblyFunctions {
+			o.Features.Enable(features.EnableAlphaWebAssemblyFunctions)
+			log.Info("Alpha feature enabled", "flag", features.EnableAlphaWebAssemblyFunctions)
+
+			cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+			if err != nil {
+				return errors.Wrap(err, "cannot create Kubernetes clientset")
+			}
+			f, err := xpkg.NewK8sFetcher(cs, append(fo, xpkg.WithNamespace(c.Namespace), xpkg.WithServiceAccount(c.ServiceAccount))...)
+			if err != nil {
+				return errors.Wrap(err, "cannot create package fetcher")
+			}
+
+			// WebAssembly Functions run inside this process, so all XR
+			// controllers share the same WebAssembly runtime.
+			wfr := xfn.NewWASMFunctionRunner(mgr.GetClient(), xfn.NewImageModuleFetcher(f, c.Registry),
+				xfn.WithWASMLogger(log),
+				xfn.WithWASMMemoryLimit(uint64(c.MaxWebAssemblyFunctionMemory)<<20),
+				xfn.WithWASMTimeout(c.WebAssemblyFunctionTimeout),
+			)
+			defer wfr.Close(context.Background()) //nolint:errcheck // There's nothing useful to do with this error.
+
+			// Run each Function using the runtime its package specifies.
+			fr = xfn.NewRuntimeSelectingFunctionRunner(mgr.GetClient(), pfr, wfr)
+		}
+
+		// Reuse the responses of Functions that return a TTL until it expires.
+		functionRunner = xfn.NewCachingFunctionRunner(fr,
+			xfn.WithCacheLogger(log),
+			xfn.WithCacheMetrics(m),
+			xfn.WithMaxCachedResponses(c.MaxCachedFunctionResponses),
+		)
 	}
 	if c.EnableEnvironmentConfigs {
 		o.Features.Enable(features.EnableAlphaEnvironmentConfigs)
@@ -266,6 +364,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		ServiceAccount: c.ServiceAccount,
 		Registry:       c.Registry,
 		FunctionRunner: functionRunner,
+		TracerProvider: tp,
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -289,18 +388,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Namespace:       c.Namespace,
 		ServiceAccount:  c.ServiceAccount,
 		DefaultRegistry: c.Registry,
-		FetcherOptions:  []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
+		FetcherOptions:  fo,
 		PackageRuntime:  pr,
 	}
 
-	if c.CABundlePath != "" {
-		rootCAs, err := ParseCertificatesFromPath(c.CABundlePath)
-		if err != nil {
-			return errors.Wrap(err, "cannot parse CA bundle")
-		}
-		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
-	}
-
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "cannot add packages controllers to manager")
 	}
@@ -19,6 +19,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	admv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +64,7 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 	var steps []initializer.Step
 	tlsGeneratorOpts := []initializer.TLSCertificateGeneratorOption{
 		initializer.TLSCertificateGeneratorWithClientSecretName(c.TLSClientSecretName, []string{fmt.Sprintf("%s.%s", c.ServiceAccount, c.Namespace)}),
+		initializer.TLSCertificateGeneratorWithClientURIs([]*url.URL{initializer.CrossplaneIdentity()}),
 		initializer.TLSCertificateGeneratorWithLogger(log.WithValues("Step", "TLSCertificateGenerator")),
 	}
 	if c.WebhookEnabled {
@@ -27,6 +27,7 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
@@ -45,6 +46,8 @@ const (
 
 	errMergeClaimSpec   = "unable to merge claim spec"
 	errMergeClaimStatus = "unable to merge claim status"
+
+	errPropagateConditions = "cannot propagate status conditions from composite"
 )
 
 // An APIDryRunCompositeConfigurator configures composite resources. It may
@@ -214,6 +217,10 @@ func (c *APIClaimConfigurator) Configure(ctx context.Context, cm resource.Compos
 		return errors.Wrap(err, errMergeClaimStatus)
 	}
 
+	if err := propagateConditions(ucm, ucp); err != nil {
+		return errors.Wrap(err, errPropagateConditions)
+	}
+
 	if err := c.client.Status().Update(ctx, cm); err != nil {
 		return errors.Wrap(err, errUpdateClaimStatus)
 	}
@@ -302,3 +309,69 @@ func merge(dst, src any, opts ...func(*mergeConfig)) error {
 
 	return mergo.Merge(&dstMap, filter(srcMap, config.srcfilter...), config.mergeOptions...)
 }
+
+// propagateConditions propagates any status conditions that Composition
+// Functions targeted at the claim from the supplied composite to the supplied
+// claim. It removes any conditions that were propagated during a previous
+// reconcile but that the composite no longer targets at the claim.
+func propagateConditions(cm *claim.Unstructured, cp *composite.Unstructured) error {
+	path := "status." + xcrd.StatusClaimConditionTypes
+
+	types, err := fieldpath.Pave(cp.Object).GetStringArray(path)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+
+	p := fieldpath.Pave(cm.Object)
+	prev, err := p.GetStringArray(path)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+
+	// Nothing to propagate, and nothing to remove.
+	if len(types) == 0 && len(prev) == 0 {
+		return nil
+	}
+
+	// The path is directly `status` because conditions are inline.
+	conditioned := xpv1.ConditionedStatus{}
+	if err := p.GetValueInto("status", &conditioned); err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+
+	current := make(map[string]bool, len(types))
+	for _, t := range types {
+		current[t] = true
+	}
+	stale := make(map[xpv1.ConditionType]bool, len(prev))
+	for _, t := range prev {
+		if !current[t] {
+			stale[xpv1.ConditionType(t)] = true
+		}
+	}
+	keep := make([]xpv1.Condition, 0, len(conditioned.Conditions))
+	for _, c := range conditioned.Conditions {
+		if !stale[c.Type] {
+			keep = append(keep, c)
+		}
+	}
+	conditioned.Conditions = keep
+
+	propagated := make([]string, 0, len(types))
+	for _, t := range types {
+		c := cp.GetCondition(xpv1.ConditionType(t))
+		if c.Type == "" {
+			continue
+		}
+		conditioned.SetConditions(c)
+		propagated = append(propagated, t)
+	}
+
+	if err := p.SetValue("status.conditions", conditioned.Conditions); err != nil {
+		return err
+	}
+	if len(propagated) == 0 {
+		return p.DeleteField(path)
+	}
+	return p.SetValue(path, propagated)
+}
@@ -1075,6 +1075,90 @@ func TestClaimConfigure(t *testing.T) {
 				},
 			},
 		},
+		"PropagateConditions": {
+			reason: "Conditions that Functions targeted at the claim should be propagated from the composite, replacing stale ones",
+			args: args{
+				client: test.NewMockClient(),
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"status": map[string]any{
+								"conditions": []any{
+									map[string]any{
+										"type":               "Synced",
+										"status":             "True",
+										"reason":             "ReconcileSuccess",
+										"lastTransitionTime": "2023-10-01T00:00:00Z",
+									},
+									map[string]any{
+										"type":               "CacheReady",
+										"status":             "True",
+										"reason":             "Available",
+										"lastTransitionTime": "2023-10-01T00:00:00Z",
+									},
+								},
+								"claimConditionTypes": []any{"CacheReady"},
+							},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name + "-12345",
+							},
+							"status": map[string]any{
+								"conditions": []any{
+									map[string]any{
+										"type":               "DatabaseReady",
+										"status":             "False",
+										"reason":             "QuotaExceeded",
+										"lastTransitionTime": "2023-10-01T00:00:00Z",
+									},
+								},
+								"functionConditionTypes": []any{"DatabaseReady"},
+								"claimConditionTypes":    []any{"DatabaseReady"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"status": map[string]any{
+								"conditions": []any{
+									map[string]any{
+										"type":               "Synced",
+										"status":             "True",
+										"reason":             "ReconcileSuccess",
+										"lastTransitionTime": "2023-10-01T00:00:00Z",
+									},
+									map[string]any{
+										"type":               "DatabaseReady",
+										"status":             "False",
+										"reason":             "QuotaExceeded",
+										"lastTransitionTime": "2023-10-01T00:00:00Z",
+									},
+								},
+								"claimConditionTypes": []any{"DatabaseReady"},
+							},
+						},
+					},
+				},
+			},
+		},
 		"UpdatePolicyManual": {
 			reason: "CompositionRevision of claim should NOT overwritten by the composite",
 			args: args{
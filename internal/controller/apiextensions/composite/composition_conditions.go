@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errConditionGetValue       = "cannot get fieldPath"
+	errConditionUnmarshalValue = "cannot unmarshal value"
+	errConditionNormalizeValue = "cannot normalize the value of fieldPath"
+	errConditionEvaluateExpr   = "cannot evaluate expression"
+
+	errFmtConditionUnnamed      = "composed resource template at index %d uses a condition, but is not named"
+	errFmtEvaluateCondition     = "cannot evaluate condition of composed resource template %q"
+	errFmtConditionType         = "unknown condition type %q"
+	errFmtConditionMissingField = "condition type %s requires %s to be set"
+	errFmtConditionNotBool      = "expression returned %T, not bool"
+)
+
+// SelectTemplates returns the supplied composed resource templates, omitting
+// any template whose condition does not hold for the supplied composite
+// resource. Templates without a condition are always selected.
+func SelectTemplates(xr *composite.Unstructured, cts []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
+	out := make([]v1.ComposedTemplate, 0, len(cts))
+	for i, t := range cts {
+		if t.Condition == nil {
+			out = append(out, t)
+			continue
+		}
+
+		// Omitting an anonymous template would change which existing composed
+		// resources the templates that follow it are associated with.
+		if t.Name == nil {
+			return nil, errors.Errorf(errFmtConditionUnnamed, i)
+		}
+
+		ok, err := EvaluateResourceCondition(xr, *t.Condition)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtEvaluateCondition, *t.Name)
+		}
+		if ok {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// EvaluateResourceCondition returns true if the supplied condition holds for
+// the supplied composite resource.
+func EvaluateResourceCondition(xr *composite.Unstructured, c v1.ResourceCondition) (bool, error) {
+	switch c.Type {
+	case v1.ResourceConditionTypeFieldPathExists:
+		if c.FieldPath == nil {
+			return false, errors.Errorf(errFmtConditionMissingField, c.Type, "fieldPath")
+		}
+		_, err := fieldpath.Pave(xr.UnstructuredContent()).GetValue(*c.FieldPath)
+		if fieldpath.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errConditionGetValue)
+		}
+		return true, nil
+
+	case v1.ResourceConditionTypeFieldPathEquals:
+		if c.FieldPath == nil {
+			return false, errors.Errorf(errFmtConditionMissingField, c.Type, "fieldPath")
+		}
+		if c.Value == nil {
+			return false, errors.Errorf(errFmtConditionMissingField, c.Type, "value")
+		}
+		got, err := fieldpath.Pave(xr.UnstructuredContent()).GetValue(*c.FieldPath)
+		if fieldpath.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errConditionGetValue)
+		}
+		var want any
+		if err := json.Unmarshal(c.Value.Raw, &want); err != nil {
+			return false, errors.Wrap(err, errConditionUnmarshalValue)
+		}
+		// Round-trip the value through JSON so that it's represented the
+		// same way as the value we compare it to, e.g. integers as float64.
+		raw, err := json.Marshal(got)
+		if err != nil {
+			return false, errors.Wrap(err, errConditionNormalizeValue)
+		}
+		got = nil
+		if err := json.Unmarshal(raw, &got); err != nil {
+			return false, errors.Wrap(err, errConditionNormalizeValue)
+		}
+		return reflect.DeepEqual(want, got), nil
+
+	case v1.ResourceConditionTypeExpression:
+		if c.Expression == nil {
+			return false, errors.Errorf(errFmtConditionMissingField, c.Type, "expression")
+		}
+		out, err := celPrograms.Eval(*c.Expression, map[string]any{v1.ResourceConditionVariableXR: xr.UnstructuredContent()})
+		if err != nil {
+			return false, errors.Wrap(err, errConditionEvaluateExpr)
+		}
+		b, ok := out.(bool)
+		if !ok {
+			return false, errors.Errorf(errFmtConditionNotBool, out)
+		}
+		return b, nil
+	}
+
+	return false, errors.Errorf(errFmtConditionType, c.Type)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestSelectTemplates(t *testing.T) {
+	xr := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.Object["spec"] = map[string]any{
+			"parameters": map[string]any{
+				"enableBackups": true,
+				"replicas":      int64(3),
+			},
+		}
+		return xr
+	}
+
+	type args struct {
+		xr  *composite.Unstructured
+		cts []v1.ComposedTemplate
+	}
+	type want struct {
+		cts []v1.ComposedTemplate
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoConditions": {
+			reason: "Templates without a condition should always be selected.",
+			args: args{
+				xr:  xr(),
+				cts: []v1.ComposedTemplate{{Name: ptr.To("a")}, {Name: ptr.To("b")}},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{{Name: ptr.To("a")}, {Name: ptr.To("b")}},
+			},
+		},
+		"FieldPathExists": {
+			reason: "Templates should be selected only if their fieldPath exists.",
+			args: args{
+				xr: xr(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathExists, FieldPath: ptr.To("spec.parameters.replicas")}},
+					{Name: ptr.To("b"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathExists, FieldPath: ptr.To("spec.parameters.region")}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathExists, FieldPath: ptr.To("spec.parameters.replicas")}},
+				},
+			},
+		},
+		"FieldPathEquals": {
+			reason: "Templates should be selected only if their fieldPath equals their value.",
+			args: args{
+				xr: xr(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.enableBackups"), Value: &extv1.JSON{Raw: []byte(`true`)}}},
+					{Name: ptr.To("b"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.replicas"), Value: &extv1.JSON{Raw: []byte(`3`)}}},
+					{Name: ptr.To("c"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.replicas"), Value: &extv1.JSON{Raw: []byte(`"3"`)}}},
+					{Name: ptr.To("d"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.region"), Value: &extv1.JSON{Raw: []byte(`"eu"`)}}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.enableBackups"), Value: &extv1.JSON{Raw: []byte(`true`)}}},
+					{Name: ptr.To("b"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathEquals, FieldPath: ptr.To("spec.parameters.replicas"), Value: &extv1.JSON{Raw: []byte(`3`)}}},
+				},
+			},
+		},
+		"Expression": {
+			reason: "Templates should be selected only if their expression returns true.",
+			args: args{
+				xr: xr(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeExpression, Expression: ptr.To("xr.spec.parameters.replicas > 1")}},
+					{Name: ptr.To("b"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeExpression, Expression: ptr.To("has(xr.spec.parameters.region)")}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeExpression, Expression: ptr.To("xr.spec.parameters.replicas > 1")}},
+				},
+			},
+		},
+		"ExpressionNotBool": {
+			reason: "We should return an error if an expression doesn't return a bool.",
+			args: args{
+				xr: xr(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("a"), Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeExpression, Expression: ptr.To("xr.spec.parameters.replicas")}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtConditionNotBool, int64(3)), errFmtEvaluateCondition, "a"),
+			},
+		},
+		"Unnamed": {
+			reason: "We should return an error if an anonymous template uses a condition.",
+			args: args{
+				xr: xr(),
+				cts: []v1.ComposedTemplate{
+					{Condition: &v1.ResourceCondition{Type: v1.ResourceConditionTypeFieldPathExists, FieldPath: ptr.To("spec")}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConditionUnnamed, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cts, err := SelectTemplates(tc.args.xr, tc.args.cts)
+			if diff := cmp.Diff(tc.want.cts, cts); diff != "" {
+				t.Errorf("\n%s\nSelectTemplates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectTemplates(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
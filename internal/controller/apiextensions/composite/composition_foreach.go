@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errFmtForEachUnnamed     = "composed resource template at index %d uses forEach, but is not named"
+	errFmtForEachFieldPath   = "cannot get forEach fromFieldPath %q of composed resource template %q"
+	errFmtForEachNotArray    = "forEach fromFieldPath %q of composed resource template %q is not an array"
+	errFmtForEachKey         = "cannot get forEach keyFieldPath %q of element %d of composed resource template %q"
+	errFmtForEachKeyType     = "forEach keyFieldPath %q of element %d of composed resource template %q must be a string or an integer, not %T"
+	errFmtForEachDuplicateID = "composed resource template %q renders more than one composed resource named %q"
+)
+
+// A ForEachElement is an element of the composite resource array a forEach
+// composed resource template was expanded for.
+type ForEachElement struct {
+	// Index of the element within the array.
+	Index int
+
+	// Key that uniquely identifies the element within the array.
+	Key string
+
+	// Value of the element.
+	Value any
+}
+
+// ExpandForEachTemplates returns the supplied composed resource templates with
+// each forEach template replaced by one template per element of the composite
+// resource array it ranges over. Each expanded template is named after its
+// forEach template and the key of its element, and is returned along with its
+// element. Templates that don't use forEach are returned unchanged.
+func ExpandForEachTemplates(xr *composite.Unstructured, cts []v1.ComposedTemplate) ([]v1.ComposedTemplate, map[ResourceName]ForEachElement, error) { //nolint:gocyclo // Only slightly over.
+	names := make(map[ResourceName]bool, len(cts))
+	for _, t := range cts {
+		names[ResourceName(t.GetName())] = true
+	}
+
+	out := make([]v1.ComposedTemplate, 0, len(cts))
+	elements := make(map[ResourceName]ForEachElement)
+	for i, t := range cts {
+		if t.ForEach == nil {
+			out = append(out, t)
+			continue
+		}
+		if t.Name == nil {
+			return nil, nil, errors.Errorf(errFmtForEachUnnamed, i)
+		}
+
+		in, err := fieldpath.Pave(xr.UnstructuredContent()).GetValue(t.ForEach.FromFieldPath)
+		if fieldpath.IsNotFound(err) {
+			// There are no elements, and thus nothing to render.
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtForEachFieldPath, t.ForEach.FromFieldPath, *t.Name)
+		}
+		arr, ok := in.([]any)
+		if !ok {
+			return nil, nil, errors.Errorf(errFmtForEachNotArray, t.ForEach.FromFieldPath, *t.Name)
+		}
+
+		for j, v := range arr {
+			key, err := forEachKey(t, j, v)
+			if err != nil {
+				return nil, nil, err
+			}
+			name := ResourceName(fmt.Sprintf("%s-%s", *t.Name, key))
+			if _, seen := elements[name]; seen || names[name] {
+				return nil, nil, errors.Errorf(errFmtForEachDuplicateID, *t.Name, name)
+			}
+
+			et := *t.DeepCopy()
+			et.Name = ptr.To(string(name))
+			et.ForEach = nil
+			out = append(out, et)
+			elements[name] = ForEachElement{Index: j, Key: key, Value: v}
+		}
+	}
+
+	return out, elements, nil
+}
+
+// forEachKey returns the key of the supplied element of the supplied forEach
+// template's array.
+func forEachKey(t v1.ComposedTemplate, i int, v any) (string, error) {
+	if t.ForEach.KeyFieldPath == nil {
+		return strconv.Itoa(i), nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", errors.Errorf(errFmtForEachKeyType, *t.ForEach.KeyFieldPath, i, t.GetName(), v)
+	}
+	k, err := fieldpath.Pave(m).GetValue(*t.ForEach.KeyFieldPath)
+	if err != nil {
+		return "", errors.Wrapf(err, errFmtForEachKey, *t.ForEach.KeyFieldPath, i, t.GetName())
+	}
+	switch key := k.(type) {
+	case string:
+		return key, nil
+	case int64:
+		return strconv.FormatInt(key, 10), nil
+	case float64:
+		// JSON numbers may be decoded as float64. Only integers are allowed.
+		if key == float64(int64(key)) {
+			return strconv.FormatInt(int64(key), 10), nil
+		}
+	}
+	return "", errors.Errorf(errFmtForEachKeyType, *t.ForEach.KeyFieldPath, i, t.GetName(), k)
+}
+
+// ForEachSource returns a copy of the supplied composite resource with the
+// supplied element set at the forEach field path, such that it may be used as
+// the source of the FromComposite patches of an expanded forEach template.
+func ForEachSource(xr *composite.Unstructured, e ForEachElement) *composite.Unstructured {
+	src := &composite.Unstructured{Unstructured: *xr.Unstructured.DeepCopy()}
+	src.Object[v1.ForEachFieldPath] = map[string]any{
+		"index":   int64(e.Index),
+		"key":     e.Key,
+		"element": e.Value,
+	}
+	return src
+}
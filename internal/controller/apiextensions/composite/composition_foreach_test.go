@@ -0,0 +1,195 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestExpandForEachTemplates(t *testing.T) {
+	subnets := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.Object["spec"] = map[string]any{
+			"subnets": []any{
+				map[string]any{"name": "a", "cidr": "10.0.0.0/24"},
+				map[string]any{"name": "b", "cidr": "10.0.1.0/24"},
+			},
+		}
+		return xr
+	}
+
+	type args struct {
+		xr  *composite.Unstructured
+		cts []v1.ComposedTemplate
+	}
+	type want struct {
+		cts      []v1.ComposedTemplate
+		elements map[ResourceName]ForEachElement
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoForEach": {
+			reason: "Templates that don't use forEach should be returned unchanged.",
+			args: args{
+				xr:  subnets(),
+				cts: []v1.ComposedTemplate{{Name: ptr.To("vpc")}},
+			},
+			want: want{
+				cts:      []v1.ComposedTemplate{{Name: ptr.To("vpc")}},
+				elements: map[ResourceName]ForEachElement{},
+			},
+		},
+		"KeyedByIndex": {
+			reason: "A forEach template without a keyFieldPath should be expanded into one template per element, keyed by index.",
+			args: args{
+				xr: subnets(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("vpc")},
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec.subnets"}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("vpc")},
+					{Name: ptr.To("subnet-0")},
+					{Name: ptr.To("subnet-1")},
+				},
+				elements: map[ResourceName]ForEachElement{
+					"subnet-0": {Index: 0, Key: "0", Value: map[string]any{"name": "a", "cidr": "10.0.0.0/24"}},
+					"subnet-1": {Index: 1, Key: "1", Value: map[string]any{"name": "b", "cidr": "10.0.1.0/24"}},
+				},
+			},
+		},
+		"KeyedByField": {
+			reason: "A forEach template with a keyFieldPath should be expanded into one template per element, keyed by the field.",
+			args: args{
+				xr: subnets(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec.subnets", KeyFieldPath: ptr.To("name")}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet-a")},
+					{Name: ptr.To("subnet-b")},
+				},
+				elements: map[ResourceName]ForEachElement{
+					"subnet-a": {Index: 0, Key: "a", Value: map[string]any{"name": "a", "cidr": "10.0.0.0/24"}},
+					"subnet-b": {Index: 1, Key: "b", Value: map[string]any{"name": "b", "cidr": "10.0.1.0/24"}},
+				},
+			},
+		},
+		"MissingArray": {
+			reason: "A forEach template whose array doesn't exist should render no templates.",
+			args: args{
+				xr: composite.New(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec.subnets"}},
+				},
+			},
+			want: want{
+				cts:      []v1.ComposedTemplate{},
+				elements: map[ResourceName]ForEachElement{},
+			},
+		},
+		"NotAnArray": {
+			reason: "We should return an error if the forEach fromFieldPath isn't an array.",
+			args: args{
+				xr: subnets(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec"}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtForEachNotArray, "spec", "subnet"),
+			},
+		},
+		"DuplicateKey": {
+			reason: "We should return an error if two elements have the same key.",
+			args: args{
+				xr: subnets(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet-a")},
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec.subnets", KeyFieldPath: ptr.To("name")}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtForEachDuplicateID, "subnet", "subnet-a"),
+			},
+		},
+		"InvalidKeyType": {
+			reason: "We should return an error if a key isn't a string or an integer.",
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.Object["spec"] = map[string]any{"subnets": []any{map[string]any{"name": true}}}
+					return xr
+				}(),
+				cts: []v1.ComposedTemplate{
+					{Name: ptr.To("subnet"), ForEach: &v1.ForEach{FromFieldPath: "spec.subnets", KeyFieldPath: ptr.To("name")}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtForEachKeyType, "name", 0, "subnet", true),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cts, elements, err := ExpandForEachTemplates(tc.args.xr, tc.args.cts)
+			if diff := cmp.Diff(tc.want.cts, cts); diff != "" {
+				t.Errorf("\n%s\nExpandForEachTemplates(...): -want templates, +got templates:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.elements, elements); diff != "" {
+				t.Errorf("\n%s\nExpandForEachTemplates(...): -want elements, +got elements:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExpandForEachTemplates(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestForEachSource(t *testing.T) {
+	xr := composite.New()
+	xr.SetName("cool-xr")
+
+	src := ForEachSource(xr, ForEachElement{Index: 1, Key: "b", Value: "10.0.1.0/24"})
+
+	want := map[string]any{"index": int64(1), "key": "b", "element": "10.0.1.0/24"}
+	if diff := cmp.Diff(want, src.Object[v1.ForEachFieldPath]); diff != "" {
+		t.Errorf("ForEachSource(...): -want, +got:\n%s", diff)
+	}
+	if _, ok := xr.Object[v1.ForEachFieldPath]; ok {
+		t.Errorf("ForEachSource(...): the supplied composite resource should not be modified")
+	}
+}
@@ -20,19 +20,28 @@ import (
 	"fmt"
 	"sort"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -41,6 +50,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 // Error strings.
@@ -56,6 +67,7 @@ const (
 	errXRAsStruct               = "cannot encode composite resource to protocol buffer Struct well-known type"
 	errEnvAsStruct              = "cannot encode environment to protocol buffer Struct well-known type"
 	errStructFromUnstructured   = "cannot create Struct"
+	errSetFunctionConditions    = "cannot set composite resource conditions returned by the Function pipeline"
 
 	errFmtApplyCD                    = "cannot apply composed resource %q"
 	errFmtFetchCDConnectionDetails   = "cannot fetch connection details for composed resource %q (a %s named %s)"
@@ -65,6 +77,17 @@ const (
 	errFmtUnmarshalDesiredCD         = "cannot unmarshal desired composed resource %q from RunFunctionResponse"
 	errFmtCDAsStruct                 = "cannot encode composed resource %q to protocol buffer Struct well-known type"
 	errFmtFatalResult                = "pipeline step %q returned a fatal result: %s"
+	errFmtEvaluateStepCondition      = "cannot evaluate condition of Composition pipeline step %q"
+	errFmtStateAsMap                 = "cannot convert %s state to a map"
+	errFmtGetCredentialsFromSecret   = "cannot get Composition pipeline step %q credential %q from Secret"
+	errFmtRequirementsNotStable      = "requirements didn't stabilize after %d runs"
+	errFmtFetchExtraResources        = "cannot fetch extra resources %q"
+	errFmtExtraResourceAsStruct      = "cannot encode extra resource %q to protocol buffer Struct well-known type"
+	errFmtExtraResourceSecret        = "cannot require %s extra resources; Functions may not read Secrets"
+	errFmtGetExtraResource           = "cannot get extra resource %q"
+	errFmtListExtraResources         = "cannot list extra resources"
+
+	errExtraResourceSelectorNoMatch = "extra resource selector must match either a name or labels"
 )
 
 // Server-side-apply field owners. We need two of these because it's possible
@@ -90,12 +113,19 @@ const (
 	FunctionContextKeyEnvironment = "apiextensions.crossplane.io/environment"
 )
 
+// MaxRequirementsIterations is the maximum number of times a Function is run
+// in a single pipeline step. A Function is run again each time it returns
+// requirements that differ from those it returned when it was last run.
+const MaxRequirementsIterations = 5
+
 // A FunctionComposer supports composing resources using a pipeline of
 // Composition Functions. It ignores the P&T resources array.
 type FunctionComposer struct {
 	client    client.Client
 	composite xr
 	pipeline  FunctionRunner
+	log       logging.Logger
+	tracer    trace.Tracer
 }
 
 type xr struct {
@@ -103,6 +133,7 @@ type xr struct {
 	managed.ConnectionDetailsFetcher
 	ComposedResourceObserver
 	ComposedResourceGarbageCollector
+	ExtraResourcesFetcher
 }
 
 // A FunctionRunner runs a single Composition Function.
@@ -119,6 +150,20 @@ func (fn FunctionRunnerFn) RunFunction(ctx context.Context, name string, req *v1
 	return fn(ctx, name, req)
 }
 
+// An ExtraResourcesFetcher fetches the extra resources a Function requires.
+type ExtraResourcesFetcher interface {
+	FetchExtraResources(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error)
+}
+
+// An ExtraResourcesFetcherFn fetches the extra resources a Function requires.
+type ExtraResourcesFetcherFn func(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error)
+
+// FetchExtraResources fetches the extra resources selected by the supplied
+// selector.
+func (fn ExtraResourcesFetcherFn) FetchExtraResources(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error) {
+	return fn(ctx, rs)
+}
+
 // A ComposedResourceObserver observes existing composed resources.
 type ComposedResourceObserver interface {
 	ObserveComposedResources(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error)
@@ -175,6 +220,30 @@ func WithComposedResourceGarbageCollector(d ComposedResourceGarbageCollector) Fu
 	}
 }
 
+// WithExtraResourcesFetcher configures how the FunctionComposer should fetch
+// the extra resources Functions require.
+func WithExtraResourcesFetcher(f ExtraResourcesFetcher) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.ExtraResourcesFetcher = f
+	}
+}
+
+// WithFunctionComposerLogger configures how the FunctionComposer should log,
+// e.g. when it skips a pipeline step.
+func WithFunctionComposerLogger(l logging.Logger) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.log = l
+	}
+}
+
+// WithFunctionComposerTracerProvider configures the OpenTelemetry tracer
+// provider the FunctionComposer should use to trace each pipeline step.
+func WithFunctionComposerTracerProvider(tp trace.TracerProvider) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.tracer = tp.Tracer(tracerName)
+	}
+}
+
 // NewFunctionComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComposerOption) *FunctionComposer {
@@ -192,9 +261,12 @@ func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComp
 			ComposedResourceObserver:         NewExistingComposedResourceObserver(kube, f),
 			ComposedResourceGarbageCollector: NewDeletingComposedResourceGarbageCollector(kube),
 			NameGenerator:                    NewAPINameGenerator(kube),
+			ExtraResourcesFetcher:            NewExistingExtraResourcesFetcher(kube),
 		},
 
 		pipeline: r,
+		log:      logging.NewNopLogger(),
+		tracer:   noop.NewTracerProvider().Tracer(tracerName),
 	}
 
 	for _, fn := range o {
@@ -204,6 +276,157 @@ func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComp
 	return c
 }
 
+// runFunction runs the named Function. If the Function requires extra
+// resources it fetches them and runs the Function again, until the Function
+// returns the same requirements twice in a row. The supplied observed state is
+// only non-nil if the Function should be sent only the observed composed
+// resources it requires. In that case the Function is also run again with any
+// observed composed resources it requires.
+func (c *FunctionComposer) runFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest, observed *v1beta1.State) (*v1beta1.RunFunctionResponse, error) {
+	var requirements *v1beta1.Requirements
+	for i := 0; i < MaxRequirementsIterations; i++ {
+		rsp, err := c.pipeline.RunFunction(ctx, name, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// The Function is done once it stops changing its requirements, i.e.
+		// once we've satisfied them.
+		newRequirements := rsp.GetRequirements()
+		n := len(newRequirements.GetExtraResources())
+		if observed != nil {
+			n += len(newRequirements.GetObservedResources())
+		}
+		if n == 0 || proto.Equal(newRequirements, requirements) {
+			return rsp, nil
+		}
+		requirements = newRequirements
+
+		// Run the Function again with the extra resources it requires. We
+		// include an entry for every requirement, even if no resources
+		// matched it, so the Function knows we tried to satisfy it.
+		extra := make(map[string]*v1beta1.Resources, len(requirements.GetExtraResources()))
+		for k, rs := range requirements.GetExtraResources() {
+			resources, err := c.composite.FetchExtraResources(ctx, rs)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtFetchExtraResources, k)
+			}
+			extra[k] = resources
+		}
+		req.ExtraResources = extra
+
+		if observed != nil {
+			req.Observed = RequiredObservedState(observed, requirements.GetObservedResources())
+		}
+	}
+
+	return nil, errors.Errorf(errFmtRequirementsNotStable, MaxRequirementsIterations)
+}
+
+// An ExistingExtraResourcesFetcher fetches extra resources from the API
+// server. Its reads are limited by the RBAC permissions of the client it uses,
+// and it never reads Secrets.
+type ExistingExtraResourcesFetcher struct {
+	client client.Reader
+}
+
+// NewExistingExtraResourcesFetcher returns an ExtraResourcesFetcher that
+// fetches extra resources from the API server.
+func NewExistingExtraResourcesFetcher(c client.Reader) *ExistingExtraResourcesFetcher {
+	return &ExistingExtraResourcesFetcher{client: c}
+}
+
+// FetchExtraResources fetches the extra resources selected by the supplied
+// selector. It returns an empty set of resources if none match.
+func (e *ExistingExtraResourcesFetcher) FetchExtraResources(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error) {
+	gvk := schema.FromAPIVersionAndKind(rs.GetApiVersion(), rs.GetKind())
+
+	// Crossplane can read Secrets in order to handle connection details, but
+	// a Function shouldn't be able to use it to read them.
+	if gvk.GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+		return nil, errors.Errorf(errFmtExtraResourceSecret, rs.GetKind())
+	}
+
+	switch match := rs.GetMatch().(type) {
+	case *v1beta1.ResourceSelector_MatchName:
+		r := &kunstructured.Unstructured{}
+		r.SetGroupVersionKind(gvk)
+		err := e.client.Get(ctx, types.NamespacedName{Namespace: rs.GetNamespace(), Name: match.MatchName}, r)
+		if kerrors.IsNotFound(err) {
+			return &v1beta1.Resources{}, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetExtraResource, match.MatchName)
+		}
+		o, err := AsStruct(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtExtraResourceAsStruct, r.GetName())
+		}
+		return &v1beta1.Resources{Items: []*v1beta1.Resource{{Resource: o}}}, nil
+
+	case *v1beta1.ResourceSelector_MatchLabels:
+		l := &kunstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := e.client.List(ctx, l, client.InNamespace(rs.GetNamespace()), client.MatchingLabels(match.MatchLabels.GetLabels())); err != nil {
+			return nil, errors.Wrap(err, errFmtListExtraResources)
+		}
+		out := &v1beta1.Resources{Items: make([]*v1beta1.Resource, 0, len(l.Items))}
+		for i := range l.Items {
+			o, err := AsStruct(&l.Items[i])
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtExtraResourceAsStruct, l.Items[i].GetName())
+			}
+			out.Items = append(out.Items, &v1beta1.Resource{Resource: o})
+		}
+		return out, nil
+	}
+
+	return nil, errors.New(errExtraResourceSelectorNoMatch)
+}
+
+// EvaluatePipelineStepCondition returns true if the supplied pipeline step
+// condition holds for the supplied observed state, desired state, and pipeline
+// context.
+func EvaluatePipelineStepCondition(condition string, o, d *v1beta1.State, fctx *structpb.Struct) (bool, error) {
+	vars := map[string]any{}
+	for name, m := range map[string]proto.Message{
+		v1.PipelineStepConditionVariableObserved: o,
+		v1.PipelineStepConditionVariableDesired:  d,
+		v1.PipelineStepConditionVariableContext:  fctx,
+	} {
+		v, err := AsMap(m)
+		if err != nil {
+			return false, errors.Wrapf(err, errFmtStateAsMap, name)
+		}
+		vars[name] = v
+	}
+
+	out, err := celPrograms.Eval(condition, vars)
+	if err != nil {
+		return false, errors.Wrap(err, errConditionEvaluateExpr)
+	}
+	b, ok := out.(bool)
+	if !ok {
+		return false, errors.Errorf(errFmtConditionNotBool, out)
+	}
+	return b, nil
+}
+
+// AsMap returns the JSON representation of the supplied protocol buffer
+// message as a map. Integral numbers are represented as int64.
+func AsMap(m proto.Message) (map[string]any, error) {
+	out := map[string]any{}
+	if m == nil {
+		return out, nil
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	// The apimachinery JSON package decodes integral numbers as int64.
+	return out, json.Unmarshal(b, &out)
+}
+
 // Compose resources using the Functions pipeline.
 func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructured, req CompositionRequest) (CompositionResult, error) { //nolint:gocyclo // We probably don't want any further abstraction for the sake of reduced complexity.
 	// Observe our existing composed resources. We need to do this before we
@@ -237,6 +460,11 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 	events := []event.Event{}
 
+	// Status conditions returned by the Function pipeline, by type. Conditions
+	// returned by later pipeline steps override those returned by earlier
+	// steps.
+	conditions := map[xpv1.ConditionType]*v1beta1.Condition{}
+
 	// The Function context starts empty...
 	fctx := &structpb.Struct{Fields: map[string]*structpb.Value{}}
 
@@ -253,6 +481,18 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// the desired state returned by the last, and each Function may produce
 	// results that will be emitted as events.
 	for _, fn := range req.Revision.Spec.Pipeline {
+		if fn.Condition != nil {
+			run, err := EvaluatePipelineStepCondition(*fn.Condition, o, d, fctx)
+			if err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtEvaluateStepCondition, fn.Step)
+			}
+			if !run {
+				c.log.Debug("Skipping Composition pipeline step because its condition is false", "step", fn.Step, "function", fn.FunctionRef.Name, "condition", *fn.Condition)
+				events = append(events, event.Normal(reasonCompose, fmt.Sprintf("Pipeline step %q: skipped because its condition is false", fn.Step)))
+				continue
+			}
+		}
+
 		req := &v1beta1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
 
 		if fn.Input != nil {
@@ -263,12 +503,52 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			req.Input = in
 		}
 
+		// Load any credentials the Function needs. Their data must never be
+		// logged or included in events.
+		for _, cs := range fn.Credentials {
+			// For now we only support loading credentials from secrets.
+			if cs.Source != v1.FunctionCredentialsSourceSecret || cs.SecretRef == nil {
+				continue
+			}
+
+			s := &corev1.Secret{}
+			if err := c.client.Get(ctx, client.ObjectKey{Namespace: cs.SecretRef.Namespace, Name: cs.SecretRef.Name}, s); err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtGetCredentialsFromSecret, fn.Step, cs.Name)
+			}
+
+			if req.Credentials == nil {
+				req.Credentials = make(map[string]*v1beta1.Credentials)
+			}
+			req.Credentials[cs.Name] = &v1beta1.Credentials{
+				Source: &v1beta1.Credentials_CredentialData{
+					CredentialData: &v1beta1.CredentialData{Data: s.Data},
+				},
+			}
+		}
+
+		// Steps that only send the observed composed resources their Function
+		// requires start by sending only the observed XR. This keeps requests
+		// small when the XR composes many resources.
+		var all *v1beta1.State
+		if fn.SendsRequiredObservedResources() {
+			all = o
+			req.Observed = RequiredObservedState(o, nil)
+		}
+
 		// TODO(negz): Generate a content-addressable tag for this request.
 		// Perhaps using https://github.com/cerbos/protoc-gen-go-hashpb ?
-		rsp, err := c.pipeline.RunFunction(ctx, fn.FunctionRef.Name, req)
+		sctx, span := c.tracer.Start(ctx, "RunPipelineStep", trace.WithAttributes(
+			attribute.String(AttributeKeyPipelineStep, fn.Step),
+			attribute.String(AttributeKeyFunctionName, fn.FunctionRef.Name),
+		))
+		rsp, err := c.runFunction(sctx, fn.FunctionRef.Name, req, all)
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "cannot run Function")
+			span.End()
 			return CompositionResult{}, errors.Wrapf(err, errFmtRunPipelineStep, fn.Step)
 		}
+		span.End()
 
 		// Pass the desired state returned by this Function to the next one.
 		d = rsp.GetDesired()
@@ -294,6 +574,15 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 				events = append(events, event.Warning(reasonCompose, errors.Errorf("Pipeline step %q returned a result of unknown severity (assuming warning): %s", fn.Step, rs.GetMessage())))
 			}
 		}
+
+		for _, cd := range rsp.GetConditions() {
+			t := xpv1.ConditionType(cd.GetType())
+			if t == "" || t == xpv1.TypeReady || t == xpv1.TypeSynced {
+				events = append(events, event.Warning(reasonCompose, errors.Errorf("Pipeline step %q returned a condition of type %q, which Functions may not set", fn.Step, t)))
+				continue
+			}
+			conditions[t] = cd
+		}
 	}
 
 	// Load our desired composed resources from the Function pipeline.
@@ -392,6 +681,14 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		return CompositionResult{}, errors.Wrap(err, errApplyXRStatus)
 	}
 
+	// Set the conditions returned by the Function pipeline, replacing any set
+	// during a previous reconcile. We do this after the above status patch,
+	// which loads the latest version of the XR. The Reconciler persists these
+	// conditions when it updates the XR's status.
+	if err := SetFunctionConditions(xr, conditions); err != nil {
+		return CompositionResult{}, errors.Wrap(err, errSetFunctionConditions)
+	}
+
 	// Produce our array of resources to return to the Reconciler. The
 	// Reconciler uses this array to determine whether the XR is ready.
 	resources := make([]ComposedResource, 0, len(desired))
@@ -415,6 +712,101 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	return CompositionResult{ConnectionDetails: d.GetComposite().GetConnectionDetails(), Composed: resources, Events: events}, nil
 }
 
+// SetFunctionConditions sets the supplied status conditions returned by a
+// Function pipeline on the supplied XR. It removes any conditions that were set
+// by a Function pipeline during a previous reconcile but that aren't supplied.
+// It records the types of the supplied conditions in the XR's status, including
+// the types of the conditions that should be propagated to the XR's claim.
+func SetFunctionConditions(xr *composite.Unstructured, cs map[xpv1.ConditionType]*v1beta1.Condition) error {
+	p := fieldpath.Pave(xr.Object)
+
+	prev, err := p.GetStringArray("status." + xcrd.StatusFunctionConditionTypes)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+	stale := make([]xpv1.ConditionType, 0, len(prev))
+	for _, t := range prev {
+		if _, ok := cs[xpv1.ConditionType(t)]; !ok {
+			stale = append(stale, xpv1.ConditionType(t))
+		}
+	}
+	if err := RemoveConditions(p, stale...); err != nil {
+		return err
+	}
+
+	types := make([]string, 0, len(cs))
+	claim := make([]string, 0, len(cs))
+	for t, c := range cs {
+		xr.SetConditions(FunctionCondition(c))
+		types = append(types, string(t))
+		if c.GetTarget() == v1beta1.Target_TARGET_COMPOSITE_AND_CLAIM {
+			claim = append(claim, string(t))
+		}
+	}
+	sort.Strings(types)
+	sort.Strings(claim)
+
+	if err := SetStringArrayOrDelete(p, "status."+xcrd.StatusFunctionConditionTypes, types); err != nil {
+		return err
+	}
+	return SetStringArrayOrDelete(p, "status."+xcrd.StatusClaimConditionTypes, claim)
+}
+
+// FunctionCondition converts the supplied status condition returned by a
+// Function to a Crossplane status condition.
+func FunctionCondition(c *v1beta1.Condition) xpv1.Condition {
+	status := corev1.ConditionUnknown
+	switch c.GetStatus() {
+	case v1beta1.Status_STATUS_CONDITION_TRUE:
+		status = corev1.ConditionTrue
+	case v1beta1.Status_STATUS_CONDITION_FALSE:
+		status = corev1.ConditionFalse
+	}
+
+	return xpv1.Condition{
+		Type:               xpv1.ConditionType(c.GetType()),
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(c.GetReason()),
+		Message:            c.GetMessage(),
+	}
+}
+
+// RemoveConditions removes any status conditions of the supplied types from
+// the supplied paved object.
+func RemoveConditions(p *fieldpath.Paved, types ...xpv1.ConditionType) error {
+	if len(types) == 0 {
+		return nil
+	}
+
+	// The path is directly `status` because conditions are inline.
+	conditioned := xpv1.ConditionedStatus{}
+	if err := p.GetValueInto("status", &conditioned); err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+
+	remove := make(map[xpv1.ConditionType]bool, len(types))
+	for _, t := range types {
+		remove[t] = true
+	}
+	keep := make([]xpv1.Condition, 0, len(conditioned.Conditions))
+	for _, c := range conditioned.Conditions {
+		if !remove[c.Type] {
+			keep = append(keep, c)
+		}
+	}
+	return p.SetValue("status.conditions", keep)
+}
+
+// SetStringArrayOrDelete sets the supplied field path of the supplied paved
+// object to the supplied strings, or deletes it if there are none.
+func SetStringArrayOrDelete(p *fieldpath.Paved, path string, s []string) error {
+	if len(s) == 0 {
+		return p.DeleteField(path)
+	}
+	return p.SetValue(path, s)
+}
+
 // An ExistingComposedResourceObserver uses an XR's resource references to load
 // any existing composed resources from the API server. It also loads their
 // connection details.
@@ -507,6 +899,19 @@ func AsState(xr resource.Composite, xc managed.ConnectionDetails, rs ComposedRes
 	return &v1beta1.State{Composite: oxr, Resources: ocds}, nil
 }
 
+// RequiredObservedState returns observed state that includes the observed XR,
+// but only the named observed composed resources of the supplied state. Names
+// that don't match an observed composed resource are ignored.
+func RequiredObservedState(o *v1beta1.State, names []string) *v1beta1.State {
+	rs := make(map[string]*v1beta1.Resource, len(names))
+	for _, name := range names {
+		if r, ok := o.GetResources()[name]; ok {
+			rs[name] = r
+		}
+	}
+	return &v1beta1.State{Composite: o.GetComposite(), Resources: rs}
+}
+
 // AsStruct converts the supplied object to a protocol buffer Struct well-known
 // type.
 func AsStruct(o runtime.Object) (*structpb.Struct, error) {
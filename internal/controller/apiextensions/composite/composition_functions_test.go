@@ -18,10 +18,15 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -35,8 +40,10 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -61,7 +68,6 @@ func TestFunctionCompose(t *testing.T) {
 		o    []FunctionComposerOption
 	}
 	type args struct {
-		ctx context.Context
 		xr  *composite.Unstructured
 		req CompositionRequest
 	}
@@ -220,6 +226,380 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "oh no"),
 			},
 		},
+		"GetCredentialsSecretError": {
+			reason: "We should return any error encountered while getting the credentials secret for a Composition Function",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+									Credentials: []v1.FunctionCredentials{
+										{
+											Name:   "cool-secret",
+											Source: v1.FunctionCredentialsSourceSecret,
+											SecretRef: &xpv1.SecretReference{
+												Namespace: "default",
+												Name:      "cool-secret",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetCredentialsFromSecret, "run-cool-function", "cool-secret"),
+			},
+		},
+		"Credentials": {
+			reason: "We should pass credentials loaded from a secret to a Composition Function",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok {
+							s.Data = map[string][]byte{"password": []byte("hunter2")}
+						}
+						return nil
+					}),
+				},
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					// Return a fatal result with the password, to prove we
+					// were passed it.
+					pw := req.GetCredentials()["cool-secret"].GetCredentialData().GetData()["password"]
+					r := &v1beta1.Result{
+						Severity: v1beta1.Severity_SEVERITY_FATAL,
+						Message:  string(pw),
+					}
+					return &v1beta1.RunFunctionResponse{Results: []*v1beta1.Result{r}}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+									Credentials: []v1.FunctionCredentials{
+										{
+											Name:   "cool-secret",
+											Source: v1.FunctionCredentialsSourceSecret,
+											SecretRef: &xpv1.SecretReference{
+												Namespace: "default",
+												Name:      "cool-secret",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "hunter2"),
+			},
+		},
+		"FetchExtraResourcesError": {
+			reason: "We should return any error encountered while fetching the extra resources a Function requires",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					return &v1beta1.RunFunctionResponse{
+						Requirements: &v1beta1.Requirements{
+							ExtraResources: map[string]*v1beta1.ResourceSelector{
+								"vpcs": {ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-vpc"}},
+							},
+						},
+					}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithExtraResourcesFetcher(ExtraResourcesFetcherFn(func(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errBoom, errFmtFetchExtraResources, "vpcs"), errFmtRunPipelineStep, "run-cool-function"),
+			},
+		},
+		"RequirementsNotStable": {
+			reason: "We should return an error if a Function's requirements don't stabilize",
+			params: params{
+				r: func() FunctionRunner {
+					calls := 0
+					return FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+						calls++
+						return &v1beta1.RunFunctionResponse{
+							Requirements: &v1beta1.Requirements{
+								ExtraResources: map[string]*v1beta1.ResourceSelector{
+									"vpcs": {ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: fmt.Sprintf("vpc-%d", calls)}},
+								},
+							},
+						}, nil
+					})
+				}(),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithExtraResourcesFetcher(ExtraResourcesFetcherFn(func(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error) {
+						return &v1beta1.Resources{}, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRequirementsNotStable, MaxRequirementsIterations), errFmtRunPipelineStep, "run-cool-function"),
+			},
+		},
+		"RequiredExtraResources": {
+			reason: "We should run a Function again with the extra resources it requires",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					rsp = &v1beta1.RunFunctionResponse{
+						Requirements: &v1beta1.Requirements{
+							ExtraResources: map[string]*v1beta1.ResourceSelector{
+								"vpcs": {ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-vpc"}},
+							},
+						},
+					}
+					// Return a fatal result once we've been passed the VPC,
+					// to prove that we were.
+					if len(req.GetExtraResources()["vpcs"].GetItems()) == 1 {
+						rsp.Results = []*v1beta1.Result{{Severity: v1beta1.Severity_SEVERITY_FATAL, Message: "got the vpc"}}
+					}
+					return rsp, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithExtraResourcesFetcher(ExtraResourcesFetcherFn(func(ctx context.Context, rs *v1beta1.ResourceSelector) (*v1beta1.Resources, error) {
+						return &v1beta1.Resources{Items: []*v1beta1.Resource{{Resource: MustStruct(map[string]any{"kind": rs.GetKind()})}}}, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "got the vpc"),
+			},
+		},
+		"RequiredObservedResources": {
+			reason: "We should initially send only the observed XR to a step that sends required observed resources, and run its Function again with the observed composed resources it requires",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					rsp = &v1beta1.RunFunctionResponse{
+						Requirements: &v1beta1.Requirements{
+							ObservedResources: []string{"cool-resource"},
+						},
+					}
+					// Return a fatal result once we've been passed only the
+					// observed resource we require, to prove that we were.
+					rs := req.GetObserved().GetResources()
+					if _, ok := rs["cool-resource"]; ok && len(rs) == 1 {
+						rsp.Results = []*v1beta1.Result{{Severity: v1beta1.Severity_SEVERITY_FATAL, Message: "got the resource"}}
+					}
+					return rsp, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return ComposedResourceStates{
+							"cool-resource":  ComposedResourceState{Resource: composed.New()},
+							"other-resource": ComposedResourceState{Resource: composed.New()},
+						}, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:              "run-cool-function",
+									FunctionRef:       v1.FunctionReference{Name: "cool-function"},
+									ObservedResources: ptr.To(v1.ObservedResourcesRequired),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "got the resource"),
+			},
+		},
+		"SkippedPipelineStep": {
+			reason: "We should skip any pipeline step whose condition is false",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (rsp *v1beta1.RunFunctionResponse, err error) {
+					if name == "expensive-function" {
+						return nil, errBoom
+					}
+					r := &v1beta1.Result{
+						Severity: v1beta1.Severity_SEVERITY_FATAL,
+						Message:  "oh no",
+					}
+					return &v1beta1.RunFunctionResponse{Results: []*v1beta1.Result{r}}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.Object["spec"] = map[string]any{"replicas": int64(1)}
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-expensive-function",
+									FunctionRef: v1.FunctionReference{Name: "expensive-function"},
+									Condition:   ptr.To("observed.composite.resource.spec.replicas > 1"),
+								},
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+									Condition:   ptr.To("observed.composite.resource.spec.replicas == 1"),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFatalResult, "run-cool-function", "oh no"),
+			},
+		},
+		"PipelineStepConditionError": {
+			reason: "We should return any error encountered while evaluating a pipeline step's condition",
+			params: params{
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+									Condition:   ptr.To(`"not-a-bool"`),
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtConditionNotBool, "not-a-bool"), errFmtEvaluateStepCondition, "run-cool-function"),
+			},
+		},
 		"RenderComposedResourceMetadataError": {
 			reason: "We should return any error we encounter when rendering composed resource metadata",
 			params: params{
@@ -559,6 +939,18 @@ func TestFunctionCompose(t *testing.T) {
 								Message:  "A result of unspecified severity",
 							},
 						},
+						Conditions: []*v1beta1.Condition{
+							{
+								Type:   "DatabaseReady",
+								Status: v1beta1.Status_STATUS_CONDITION_TRUE,
+								Reason: "Available",
+							},
+							{
+								Type:   "Ready",
+								Status: v1beta1.Status_STATUS_CONDITION_TRUE,
+								Reason: "Available",
+							},
+						},
 					}
 					return rsp, nil
 				}),
@@ -636,6 +1028,11 @@ func TestFunctionCompose(t *testing.T) {
 							Reason:  "ComposeResources",
 							Message: "Pipeline step \"run-cool-function\" returned a result of unknown severity (assuming warning): A result of unspecified severity",
 						},
+						{
+							Type:    "Warning",
+							Reason:  "ComposeResources",
+							Message: "Pipeline step \"run-cool-function\" returned a condition of type \"Ready\", which Functions may not set",
+						},
 					},
 				},
 				err: nil,
@@ -647,7 +1044,7 @@ func TestFunctionCompose(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 
 			c := NewFunctionComposer(tc.params.kube, tc.params.r, tc.params.o...)
-			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
+			res, err := c.Compose(context.Background(), tc.args.xr, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
@@ -899,6 +1296,80 @@ func TestGetComposedResources(t *testing.T) {
 	}
 }
 
+func TestFunctionComposeTracing(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+	r := FunctionRunnerFn(func(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+		if name == "broken-function" {
+			return nil, errBoom
+		}
+		return &v1beta1.RunFunctionResponse{}, nil
+	})
+
+	c := NewFunctionComposer(nil, r,
+		WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+			return nil, nil
+		})),
+		WithFunctionComposerTracerProvider(tp),
+	)
+
+	req := CompositionRequest{
+		Revision: &v1.CompositionRevision{
+			Spec: v1.CompositionRevisionSpec{
+				Pipeline: []v1.PipelineStep{
+					{Step: "run-cool-function", FunctionRef: v1.FunctionReference{Name: "cool-function"}},
+					{Step: "run-broken-function", FunctionRef: v1.FunctionReference{Name: "broken-function"}},
+				},
+			},
+		},
+	}
+
+	_, err := c.Compose(context.Background(), composite.New(), req)
+	if diff := cmp.Diff(errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-broken-function"), err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nc.Compose(...): -want error, +got error:\n%s", diff)
+	}
+
+	type span struct {
+		Name   string
+		Status codes.Code
+		Attrs  []attribute.KeyValue
+	}
+
+	want := []span{
+		{
+			Name:   "RunPipelineStep",
+			Status: codes.Unset,
+			Attrs: []attribute.KeyValue{
+				attribute.String(AttributeKeyPipelineStep, "run-cool-function"),
+				attribute.String(AttributeKeyFunctionName, "cool-function"),
+			},
+		},
+		{
+			Name:   "RunPipelineStep",
+			Status: codes.Error,
+			Attrs: []attribute.KeyValue{
+				attribute.String(AttributeKeyPipelineStep, "run-broken-function"),
+				attribute.String(AttributeKeyFunctionName, "broken-function"),
+			},
+		},
+	}
+
+	got := make([]span, 0)
+	for _, s := range exp.GetSpans() {
+		got = append(got, span{Name: s.Name, Status: s.Status.Code, Attrs: s.Attributes})
+	}
+
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("\nc.Compose(...): -want spans, +got spans:\n%s", diff)
+	}
+}
+
 func TestAsState(t *testing.T) {
 	type args struct {
 		xr resource.Composite
@@ -1202,3 +1673,273 @@ func TestUpdateResourceRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFunctionConditions(t *testing.T) {
+	type args struct {
+		xr *composite.Unstructured
+		cs map[xpv1.ConditionType]*v1beta1.Condition
+	}
+	type want struct {
+		conditions    []xpv1.Condition
+		functionTypes []string
+		claimTypes    []string
+		err           error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoConditions": {
+			reason: "We should not touch the XR's conditions if no Function ever returned any.",
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetConditions(xpv1.ReconcileSuccess())
+					return xr
+				}(),
+			},
+			want: want{
+				conditions: []xpv1.Condition{xpv1.ReconcileSuccess()},
+			},
+		},
+		"SetConditions": {
+			reason: "We should set the supplied conditions, and record which should be propagated to the claim.",
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetConditions(xpv1.ReconcileSuccess())
+					return xr
+				}(),
+				cs: map[xpv1.ConditionType]*v1beta1.Condition{
+					"DatabaseReady": {
+						Type:    "DatabaseReady",
+						Status:  v1beta1.Status_STATUS_CONDITION_FALSE,
+						Reason:  "QuotaExceeded",
+						Message: ptr.To("Database quota exceeded"),
+						Target:  v1beta1.Target_TARGET_COMPOSITE_AND_CLAIM.Enum(),
+					},
+					"CacheReady": {
+						Type:   "CacheReady",
+						Status: v1beta1.Status_STATUS_CONDITION_TRUE,
+						Reason: "Available",
+					},
+				},
+			},
+			want: want{
+				conditions: []xpv1.Condition{
+					xpv1.ReconcileSuccess(),
+					{Type: "DatabaseReady", Status: corev1.ConditionFalse, Reason: "QuotaExceeded", Message: "Database quota exceeded"},
+					{Type: "CacheReady", Status: corev1.ConditionTrue, Reason: "Available"},
+				},
+				functionTypes: []string{"CacheReady", "DatabaseReady"},
+				claimTypes:    []string{"DatabaseReady"},
+			},
+		},
+		"ReplaceStaleConditions": {
+			reason: "We should remove conditions that were set by a previous reconcile but weren't returned this time.",
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetConditions(
+						xpv1.ReconcileSuccess(),
+						xpv1.Condition{Type: "DatabaseReady", Status: corev1.ConditionFalse, Reason: "QuotaExceeded"},
+						xpv1.Condition{Type: "CacheReady", Status: corev1.ConditionTrue, Reason: "Available"},
+					)
+					_ = fieldpath.Pave(xr.Object).SetValue("status."+xcrd.StatusFunctionConditionTypes, []string{"CacheReady", "DatabaseReady"})
+					_ = fieldpath.Pave(xr.Object).SetValue("status."+xcrd.StatusClaimConditionTypes, []string{"DatabaseReady"})
+					return xr
+				}(),
+				cs: map[xpv1.ConditionType]*v1beta1.Condition{
+					"CacheReady": {
+						Type:   "CacheReady",
+						Status: v1beta1.Status_STATUS_CONDITION_UNSPECIFIED,
+						Reason: "Unknown",
+					},
+				},
+			},
+			want: want{
+				conditions: []xpv1.Condition{
+					xpv1.ReconcileSuccess(),
+					{Type: "CacheReady", Status: corev1.ConditionUnknown, Reason: "Unknown"},
+				},
+				functionTypes: []string{"CacheReady"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetFunctionConditions(tc.args.xr, tc.args.cs)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetFunctionConditions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			p := fieldpath.Pave(tc.args.xr.Object)
+			got := xpv1.ConditionedStatus{}
+			_ = p.GetValueInto("status", &got)
+			if diff := cmp.Diff(tc.want.conditions, got.Conditions, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime"), cmpopts.SortSlices(func(a, b xpv1.Condition) bool { return a.Type < b.Type })); diff != "" {
+				t.Errorf("\n%s\nSetFunctionConditions(...): -want conditions, +got conditions:\n%s", tc.reason, diff)
+			}
+
+			ft, _ := p.GetStringArray("status." + xcrd.StatusFunctionConditionTypes)
+			if diff := cmp.Diff(tc.want.functionTypes, ft, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nSetFunctionConditions(...): -want function condition types, +got function condition types:\n%s", tc.reason, diff)
+			}
+
+			ct, _ := p.GetStringArray("status." + xcrd.StatusClaimConditionTypes)
+			if diff := cmp.Diff(tc.want.claimTypes, ct, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nSetFunctionConditions(...): -want claim condition types, +got claim condition types:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFetchExtraResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	vpc := func(name string) *kunstructured.Unstructured {
+		u := &kunstructured.Unstructured{}
+		u.SetAPIVersion("test.crossplane.io/v1")
+		u.SetKind("VPC")
+		u.SetName(name)
+		return u
+	}
+
+	type params struct {
+		c client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		rs  *v1beta1.ResourceSelector
+	}
+	type want struct {
+		res *v1beta1.Resources
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"MatchName": {
+			reason: "We should return the resource with the selected name.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*kunstructured.Unstructured).Object = vpc("cool-vpc").Object
+						return nil
+					}),
+				},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-vpc"}},
+			},
+			want: want{
+				res: &v1beta1.Resources{Items: []*v1beta1.Resource{{Resource: MustStruct(vpc("cool-vpc").Object)}}},
+			},
+		},
+		"MatchNameNotFound": {
+			reason: "We should return no resources if the selected name doesn't exist.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-vpc")),
+				},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-vpc"}},
+			},
+			want: want{
+				res: &v1beta1.Resources{},
+			},
+		},
+		"MatchNameError": {
+			reason: "We should return any error encountered while getting the selected resource.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-vpc"}},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetExtraResource, "cool-vpc"),
+			},
+		},
+		"MatchLabels": {
+			reason: "We should return all resources with the selected labels.",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{*vpc("cool-vpc"), *vpc("uncool-vpc")}
+						return nil
+					}),
+				},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchLabels{MatchLabels: &v1beta1.MatchLabels{Labels: map[string]string{"cool": "true"}}}},
+			},
+			want: want{
+				res: &v1beta1.Resources{Items: []*v1beta1.Resource{
+					{Resource: MustStruct(vpc("cool-vpc").Object)},
+					{Resource: MustStruct(vpc("uncool-vpc").Object)},
+				}},
+			},
+		},
+		"MatchLabelsError": {
+			reason: "We should return any error encountered while listing the selected resources.",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC", Match: &v1beta1.ResourceSelector_MatchLabels{MatchLabels: &v1beta1.MatchLabels{}}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFmtListExtraResources),
+			},
+		},
+		"Secret": {
+			reason: "We should refuse to fetch Secrets.",
+			params: params{
+				c: &test.MockClient{},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "v1", Kind: "Secret", Match: &v1beta1.ResourceSelector_MatchName{MatchName: "cool-secret"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtExtraResourceSecret, "Secret"),
+			},
+		},
+		"NoMatch": {
+			reason: "We should return an error if the selector doesn't match a name or labels.",
+			params: params{
+				c: &test.MockClient{},
+			},
+			args: args{
+				rs: &v1beta1.ResourceSelector{ApiVersion: "test.crossplane.io/v1", Kind: "VPC"},
+			},
+			want: want{
+				err: errors.New(errExtraResourceSelectorNoMatch),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewExistingExtraResourcesFetcher(tc.params.c)
+			res, err := f.FetchExtraResources(tc.args.ctx, tc.args.rs)
+			if diff := cmp.Diff(tc.want.res, res, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nFetchExtraResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetchExtraResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
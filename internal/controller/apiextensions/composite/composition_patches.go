@@ -17,13 +17,14 @@ limitations under the License.
 package composite
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
@@ -40,8 +41,19 @@ const (
 	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
 	errFmtCombineStrategyFailed       = "%s strategy could not combine"
 	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
+	errFmtWildcardCountMismatch       = "ToFieldPath %s must contain either no wildcards or as many wildcards as FromFieldPath %s"
+	errFmtRequiredFieldPathNoMatches  = "FromFieldPath %s did not match any fields"
+	errFmtPatchExpandedFieldPath      = "cannot patch from expanded FromFieldPath %s"
+	errFmtPatchErrorAction            = "unknown patch error action %q"
+	errFmtPatchDefaultValue           = "cannot patch default value after error: %s"
+	errPatchDefaultUnmarshal          = "cannot unmarshal default value"
+	errFmtResetArray                  = "cannot reset array at ToFieldPath %s"
 )
 
+// wildcard is the field path segment that matches every element of an array
+// (or every field of an object).
+const wildcard = "[*]"
+
 // ApplyEnvironmentPatch executes a patching operation between the cp and env objects.
 func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error {
 	// TODO(negz): Should this take composite.Resource and *env.Environment as
@@ -95,6 +107,65 @@ func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, only ...v1.PatchType) err
 	return errors.Errorf(errFmtInvalidPatchType, p.Type)
 }
 
+// ApplyWithErrorPolicy works like ApplyToObjects, but handles a failure to
+// apply the patch according to its onError policy, or the supplied policy if
+// it has none. It returns the error as a tolerated error if the policy skips
+// or defaults the failed patch, and as an error otherwise.
+func ApplyWithErrorPolicy(p v1.Patch, ep *v1.PatchErrorPolicy, cp, cd runtime.Object, only ...v1.PatchType) (tolerated, err error) { //nolint:nonamedreturns // Naming the returns makes them easier to tell apart.
+	err = ApplyToObjects(p, cp, cd, only...)
+	if err == nil {
+		return nil, nil
+	}
+
+	if p.Policy != nil && p.Policy.OnError != nil {
+		ep = p.Policy.OnError
+	}
+
+	switch ep.GetAction() {
+	case v1.PatchErrorActionFail:
+		return nil, err
+	case v1.PatchErrorActionSkip:
+		return err, nil
+	case v1.PatchErrorActionDefault:
+		if derr := applyDefaultValue(p, ep, cp, cd); derr != nil {
+			return nil, errors.Wrapf(derr, errFmtPatchDefaultValue, err)
+		}
+		return err, nil
+	}
+	return nil, errors.Errorf(errFmtPatchErrorAction, ep.GetAction())
+}
+
+// applyDefaultValue patches the default value of the supplied error policy to
+// the ToFieldPath of the supplied patch.
+func applyDefaultValue(p v1.Patch, ep *v1.PatchErrorPolicy, cp, cd runtime.Object) error {
+	if ep.Value == nil {
+		return errors.Errorf(errFmtRequiredField, "Value", v1.PatchErrorActionDefault)
+	}
+	var value any
+	if err := json.Unmarshal(ep.Value.Raw, &value); err != nil {
+		return errors.Wrap(err, errPatchDefaultUnmarshal)
+	}
+
+	toFieldPath := p.ToFieldPath
+	if toFieldPath == nil {
+		toFieldPath = p.FromFieldPath
+	}
+	if toFieldPath == nil {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+	}
+
+	to := cd
+	switch p.GetType() { //nolint:exhaustive // Other patch types patch the composed resource.
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath, v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
+		to = cp
+	}
+
+	if strings.Contains(*toFieldPath, wildcard) {
+		return patchFieldValueToMultiple(*toFieldPath, value, to, p.Policy)
+	}
+	return patchFieldValueToObject(*toFieldPath, value, to, p.Policy)
+}
+
 // filterPatch returns true if patch should be filtered (not applied)
 func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 	// filter does not apply if not set
@@ -103,18 +174,48 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 	}
 
 	for _, patchType := range only {
-		if patchType == p.Type {
+		if patchType == p.GetType() {
 			return false
 		}
 	}
 	return true
 }
 
-// ResolveTransforms applies a list of transforms to a patch value.
-func ResolveTransforms(c v1.Patch, input any) (any, error) {
+// resetAppendedArrays sets the toFieldPath of each of the supplied patches that
+// appends to an array of the composite resource to an empty array. Appending
+// patches run every time the composite resource is reconciled, so the arrays
+// they append to must be built from scratch rather than appended to as read
+// from the API server. Paths in the supplied set aren't reset again, and reset
+// paths are added to it.
+func resetAppendedArrays(xr runtime.Object, reset map[string]bool, pas ...v1.Patch) error {
+	for _, p := range pas {
+		if filterPatch(p, patchTypesToXR()...) || p.ToFieldPath == nil || p.Policy == nil || p.Policy.ToFieldPath == nil {
+			continue
+		}
+		switch *p.Policy.ToFieldPath { //nolint:exhaustive // Only appending policies build arrays.
+		case v1.ToFieldPathPolicyAppendArray, v1.ToFieldPathPolicyAppendUniqueArray:
+		default:
+			continue
+		}
+		// We can't reset a path with wildcards before knowing what it
+		// expands to.
+		if reset[*p.ToFieldPath] || strings.Contains(*p.ToFieldPath, wildcard) {
+			continue
+		}
+		if err := patchFieldValueToObject(*p.ToFieldPath, []any{}, xr, nil); err != nil {
+			return errors.Wrapf(err, errFmtResetArray, *p.ToFieldPath)
+		}
+		reset[*p.ToFieldPath] = true
+	}
+	return nil
+}
+
+// ResolveTransforms applies a list of transforms to a patch value. The supplied
+// source is the object the patch reads from.
+func ResolveTransforms(c v1.Patch, source map[string]any, input any) (any, error) {
 	var err error
 	for i, t := range c.Transforms {
-		if input, err = Resolve(t, input); err != nil {
+		if input, err = ResolveFrom(t, source, input); err != nil {
 			// TODO(negz): Including the type might help find the offending transform faster.
 			return nil, errors.Wrapf(err, errFmtTransformAtIndex, i)
 		}
@@ -125,7 +226,7 @@ func ResolveTransforms(c v1.Patch, input any) (any, error) {
 // patchFieldValueToMultiple, given a path with wildcards in an array index,
 // expands the arrays paths in the "to" object and patches the value into each
 // of the resulting fields, returning any errors as they occur.
-func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, mo *xpv1.MergeOptions) error {
+func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, pp *v1.PatchPolicy) error {
 	paved, err := fieldpath.PaveObject(to)
 	if err != nil {
 		return err
@@ -141,7 +242,81 @@ func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, m
 	}
 
 	for _, field := range arrayFieldPaths {
-		if err := paved.MergeValue(field, value, mo); err != nil {
+		if err := mergeValue(paved, field, value, pp); err != nil {
+			return err
+		}
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
+}
+
+// patchFieldValuesFromMultiple, given a FromFieldPath with wildcards, expands
+// the paths in the "from" object and patches the transformed value of each of
+// the resulting fields into the "to" object. The wildcards of the ToFieldPath
+// are replaced with the array indices (or object fields) that the wildcards of
+// the FromFieldPath expanded to, in order. If the ToFieldPath contains no
+// wildcards the transformed values are patched into it as an array.
+func patchFieldValuesFromMultiple(p v1.Patch, from *fieldpath.Paved, to runtime.Object) error {
+	fromSegments, err := fieldpath.Parse(*p.FromFieldPath)
+	if err != nil {
+		return err
+	}
+	toSegments, err := fieldpath.Parse(*p.ToFieldPath)
+	if err != nil {
+		return err
+	}
+	toWildcards := countWildcards(toSegments)
+	if toWildcards != 0 && toWildcards != countWildcards(fromSegments) {
+		return errors.Errorf(errFmtWildcardCountMismatch, *p.ToFieldPath, *p.FromFieldPath)
+	}
+
+	fromFieldPaths, err := from.ExpandWildcards(*p.FromFieldPath)
+	if err != nil {
+		return err
+	}
+	if len(fromFieldPaths) == 0 {
+		if p.Policy.GetFromFieldPathPolicy() == v1.FromFieldPathPolicyRequired {
+			return errors.Errorf(errFmtRequiredFieldPathNoMatches, *p.FromFieldPath)
+		}
+		return nil
+	}
+
+	// Expanded object fields are returned in random order.
+	expanded := make([]fieldpath.Segments, len(fromFieldPaths))
+	for i := range fromFieldPaths {
+		if expanded[i], err = fieldpath.Parse(fromFieldPaths[i]); err != nil {
+			return err
+		}
+	}
+	sort.SliceStable(expanded, func(i, j int) bool { return lessSegments(expanded[i], expanded[j]) })
+
+	paved, err := fieldpath.PaveObject(to)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, 0, len(expanded))
+	for _, segments := range expanded {
+		in, err := from.GetValue(segments.String())
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatchExpandedFieldPath, segments.String())
+		}
+		out, err := ResolveTransforms(p, from.UnstructuredContent(), in)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatchExpandedFieldPath, segments.String())
+		}
+		if toWildcards == 0 {
+			values = append(values, out)
+			continue
+		}
+		toFieldPath := replaceWildcards(toSegments, expandedWildcards(fromSegments, segments)).String()
+		if err := mergeValue(paved, toFieldPath, out, p.Policy); err != nil {
+			return errors.Wrapf(err, errFmtPatchExpandedFieldPath, segments.String())
+		}
+	}
+
+	if toWildcards == 0 {
+		if err := mergeValue(paved, *p.ToFieldPath, values, p.Policy); err != nil {
 			return err
 		}
 	}
@@ -149,6 +324,63 @@ func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, m
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
 }
 
+// isWildcard returns true if the supplied segment is a wildcard.
+func isWildcard(s fieldpath.Segment) bool {
+	return s.Type == fieldpath.SegmentField && s.Field == "*"
+}
+
+// countWildcards returns the number of wildcards in the supplied segments.
+func countWildcards(s fieldpath.Segments) int {
+	n := 0
+	for _, seg := range s {
+		if isWildcard(seg) {
+			n++
+		}
+	}
+	return n
+}
+
+// expandedWildcards returns the segments that the wildcards of the supplied
+// pattern expanded to in the supplied expanded segments, in order.
+func expandedWildcards(pattern, expanded fieldpath.Segments) fieldpath.Segments {
+	out := make(fieldpath.Segments, 0, len(pattern))
+	for i := range pattern {
+		if isWildcard(pattern[i]) && i < len(expanded) {
+			out = append(out, expanded[i])
+		}
+	}
+	return out
+}
+
+// replaceWildcards returns a copy of the supplied segments with each wildcard
+// replaced by the next of the supplied replacements.
+func replaceWildcards(s, replacements fieldpath.Segments) fieldpath.Segments {
+	out := make(fieldpath.Segments, len(s))
+	copy(out, s)
+	r := 0
+	for i := range out {
+		if isWildcard(out[i]) && r < len(replacements) {
+			out[i] = replacements[r]
+			r++
+		}
+	}
+	return out
+}
+
+// lessSegments orders segments by their array indices and object fields.
+func lessSegments(a, b fieldpath.Segments) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if a[i].Type == fieldpath.SegmentIndex && b[i].Type == fieldpath.SegmentIndex {
+			return a[i].Index < b[i].Index
+		}
+		return a[i].Field < b[i].Field
+	}
+	return len(a) < len(b)
+}
+
 // ApplyFromFieldPathPatch patches the "to" resource, using a source field
 // on the "from" resource. Values may be transformed if any are defined on
 // the patch.
@@ -167,6 +399,12 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 		return err
 	}
 
+	// Patch each expanded field individually if the FromFieldPath contains
+	// wildcards.
+	if strings.Contains(*p.FromFieldPath, wildcard) {
+		return patchFieldValuesFromMultiple(p, fieldpath.Pave(fromMap), to)
+	}
+
 	in, err := fieldpath.Pave(fromMap).GetValue(*p.FromFieldPath)
 	if IsOptionalFieldPathNotFound(err, p.Policy) {
 		return nil
@@ -175,23 +413,18 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 		return err
 	}
 
-	var mo *xpv1.MergeOptions
-	if p.Policy != nil {
-		mo = p.Policy.MergeOptions
-	}
-
 	// Apply transform pipeline
-	out, err := ResolveTransforms(p, in)
+	out, err := ResolveTransforms(p, fromMap, in)
 	if err != nil {
 		return err
 	}
 
 	// Patch all expanded fields if the ToFieldPath contains wildcards
-	if strings.Contains(*p.ToFieldPath, "[*]") {
-		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, mo)
+	if strings.Contains(*p.ToFieldPath, wildcard) {
+		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, p.Policy)
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, p.Policy)
 }
 
 // ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
@@ -251,12 +484,12 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 	}
 
 	// Apply transform pipeline
-	out, err := ResolveTransforms(p, cb)
+	out, err := ResolveTransforms(p, fromMap, cb)
 	if err != nil {
 		return err
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, nil)
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, p.Policy)
 }
 
 // IsOptionalFieldPathNotFound returns true if the supplied error indicates a
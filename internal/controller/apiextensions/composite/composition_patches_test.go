@@ -18,6 +18,7 @@ package composite
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -95,6 +96,45 @@ func TestPatchApply(t *testing.T) {
 				err: errors.Errorf(errFmtInvalidPatchType, "invalid-patchtype"),
 			},
 		},
+		"ValidCompositeFieldPathPatchWithTemplate": {
+			reason: "Should render a Template string transform against the composite resource",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.name"),
+					ToFieldPath:   ptr.To("objectMeta.labels.owner"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:     v1.StringTransformTypeTemplate,
+							Template: ptr.To(`{{ .input }}.{{ index .source.objectMeta.labels "Test" }}`),
+						},
+					}},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"owner": "cp.blah",
+						},
+					},
+				},
+			},
+		},
 		"ValidCompositeFieldPathPatch": {
 			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
 			args: args{
@@ -128,6 +168,39 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
+		"UntypedPatchWithFilter": {
+			reason: "A patch with no type should be treated as a FromCompositeFieldPath patch when filtering by type",
+			args: args{
+				patch: v1.Patch{
+					FromFieldPath: ptr.To("objectMeta.labels"),
+					ToFieldPath:   ptr.To("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+				only: []v1.PatchType{v1.PatchTypeFromCompositeFieldPath},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+				err: nil,
+			},
+		},
 		"ValidCompositeFieldPathPatchWithNilLastPublishTime": {
 			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
 			args: args{
@@ -235,6 +308,116 @@ func TestPatchApply(t *testing.T) {
 				err: errors.Errorf(errFmtExpandingArrayFieldPaths, "objectMeta.ownerReferences[*].badField"),
 			},
 		},
+		"ValidCompositeFieldPathPatchFromWildcards": {
+			reason: "When passed a wildcarded FromFieldPath, patches each transformed element to the corresponding element of the ToFieldPath",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   ptr.To("objectMeta.ownerReferences[*].name"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:   v1.StringTransformTypeFormat,
+							Format: ptr.To("subnet-%s"),
+						},
+					}},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a"},
+							{Name: "b"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "subnet-a"},
+							{Name: "subnet-b"},
+						},
+					},
+				},
+			},
+		},
+		"ValidCompositeFieldPathPatchFromWildcardsToArray": {
+			reason: "When passed a wildcarded FromFieldPath and a ToFieldPath without wildcards, patches the transformed elements as an array",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   ptr.To("objectMeta.finalizers"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a"},
+							{Name: "b"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a", "b"},
+					},
+				},
+			},
+		},
+		"InvalidCompositeFieldPathPatchFromWildcardsCountMismatch": {
+			reason: "Should return an error if the ToFieldPath contains a different number of wildcards than the FromFieldPath",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   ptr.To("spec.things[*].names[*]"),
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtWildcardCountMismatch, "spec.things[*].names[*]", "objectMeta.ownerReferences[*].name"),
+			},
+		},
+		"RequiredCompositeFieldPathPatchFromWildcardsNoMatches": {
+			reason: "Should return an error if a required wildcarded FromFieldPath does not match any fields",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.ownerReferences[*].name"),
+					Policy: &v1.PatchPolicy{
+						FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired),
+					},
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtRequiredFieldPathNoMatches, "objectMeta.ownerReferences[*].name"),
+			},
+		},
 		"MissingOptionalFieldPath": {
 			reason: "A FromFieldPath patch should be a no-op when an optional fromFieldPath doesn't exist",
 			args: args{
@@ -401,6 +584,258 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ToFieldPathPolicyMergeObjects": {
+			reason: "Setting policy.toFieldPath = MergeObjects merges new map values into existing ones, overwriting existing keys",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.labels"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyMergeObjects),
+					},
+					ToFieldPath: ptr.To("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"labelone": "foo",
+							"labeltwo": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"labeltwo":   "qux",
+							"labelthree": "baz",
+						},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"labelone":   "foo",
+							"labeltwo":   "bar",
+							"labelthree": "baz",
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyReplace": {
+			reason: "Setting policy.toFieldPath = Replace replaces the existing value",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.finalizers"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyReplace),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"b", "c"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a"},
+					},
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyAppendArray": {
+			reason: "Setting policy.toFieldPath = AppendArray appends the elements of an array to the existing array of the XR, including duplicates",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.finalizers"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendArray),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a", "b"},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a", "a", "b"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyAppendUniqueArray": {
+			reason: "Setting policy.toFieldPath = AppendUniqueArray appends only the elements the existing array of the XR doesn't contain",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.finalizers"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendUniqueArray),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a", "b"},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a", "b"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyAppendUniqueArrayIdempotent": {
+			reason: "Applying an AppendUniqueArray patch again should not change the existing array of the XR",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.finalizers"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendUniqueArray),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a", "b"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a", "b"},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"a", "b"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyAppendUniqueArrayCombine": {
+			reason: "Setting policy.toFieldPath = AppendUniqueArray on a combine patch appends the combined value to the existing array",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.name"},
+							{FromFieldPath: "objectMeta.labels.source"},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendUniqueArray),
+					},
+					ToFieldPath: ptr.To("objectMeta.finalizers"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"source": "foo"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"a", "cp-foo"},
+					},
+				},
+				err: nil,
+			},
+		},
+		"ToFieldPathPolicyAppendArrayNotArray": {
+			reason: "Should return an error when appending to a field that isn't an array",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.name"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendArray),
+					},
+					ToFieldPath: ptr.To("objectMeta.namespace"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta:                          metav1.ObjectMeta{Name: "cp"},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "ns"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd", Namespace: "ns"},
+				},
+				err: fmt.Errorf(errFmtAppendNotArray, "objectMeta.namespace"),
+			},
+		},
 		"FilterExcludeCompositeFieldPathPatch": {
 			reason: "Should not apply the patch as the v1.PatchType is not present in filter.",
 			args: args{
@@ -1090,6 +1525,96 @@ func TestPatchApply(t *testing.T) {
 	}
 }
 
+func TestApplyWithErrorPolicy(t *testing.T) {
+	errNotFound := func() error {
+		p := &fieldpath.Paved{}
+		_, err := p.GetValue("spec.missing")
+		return err
+	}
+	patch := func(ep *v1.PatchErrorPolicy) v1.Patch {
+		return v1.Patch{
+			Type:          v1.PatchTypeFromCompositeFieldPath,
+			FromFieldPath: ptr.To("spec.missing"),
+			ToFieldPath:   ptr.To("objectMeta.labels[default]"),
+			Policy: &v1.PatchPolicy{
+				FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired),
+				OnError:       ep,
+			},
+		}
+	}
+
+	type args struct {
+		p  v1.Patch
+		ep *v1.PatchErrorPolicy
+	}
+	type want struct {
+		cd        *fake.Composed
+		tolerated error
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Fail": {
+			reason: "A failed patch should return an error by default.",
+			args: args{
+				p: patch(nil),
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errNotFound(),
+			},
+		},
+		"Skip": {
+			reason: "A failed patch should be tolerated if the error policy skips it.",
+			args: args{
+				p:  patch(nil),
+				ep: &v1.PatchErrorPolicy{Action: v1.PatchErrorActionSkip},
+			},
+			want: want{
+				cd:        &fake.Composed{},
+				tolerated: errNotFound(),
+			},
+		},
+		"Default": {
+			reason: "A failed patch should patch the default value if the error policy of the patch overrides that of its template.",
+			args: args{
+				p: patch(&v1.PatchErrorPolicy{
+					Action: v1.PatchErrorActionDefault,
+					Value:  &extv1.JSON{Raw: []byte(`"cool"`)},
+				}),
+				ep: &v1.PatchErrorPolicy{Action: v1.PatchErrorActionFail},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"default": "cool"},
+				}},
+				tolerated: errNotFound(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cp := &fake.Composite{}
+			cd := &fake.Composed{}
+			tolerated, err := ApplyWithErrorPolicy(tc.args.p, tc.args.ep, cp, cd)
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nApplyWithErrorPolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tolerated, tolerated, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyWithErrorPolicy(...): -want tolerated, +got tolerated:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nApplyWithErrorPolicy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestOptionalFieldPathNotFound(t *testing.T) {
 	errBoom := errors.New("boom")
 	errNotFound := func() error {
@@ -1462,7 +1987,7 @@ func TestResolveTransforms(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ResolveTransforms(v1.Patch{Transforms: tt.args.ts}, tt.args.input)
+			got, err := ResolveTransforms(v1.Patch{Transforms: tt.args.ts}, nil, tt.args.input)
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("ResolveTransforms(...): -want error, +got error:\n%s", diff)
 			}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -42,11 +43,14 @@ import (
 
 // Error strings
 const (
-	errGetComposed   = "cannot get composed resource"
-	errGCComposed    = "cannot garbage collect composed resource"
-	errApplyComposed = "cannot apply composed resource"
-	errFetchDetails  = "cannot fetch connection details"
-	errInline        = "cannot inline Composition patch sets"
+	errGetComposed     = "cannot get composed resource"
+	errGCComposed      = "cannot garbage collect composed resource"
+	errApplyComposed   = "cannot apply composed resource"
+	errFetchDetails    = "cannot fetch connection details"
+	errInline          = "cannot inline Composition patch sets"
+	errForEach         = "cannot expand forEach composed resource templates"
+	errSelectTemplates = "cannot evaluate composed resource template conditions"
+	errResetArrays     = "cannot reset composite resource arrays built by appending patches"
 
 	errFmtPatchEnvironment             = "cannot apply environment patch at index %d"
 	errFmtParseBase                    = "cannot parse base template of composed resource %q"
@@ -55,6 +59,7 @@ const (
 	errFmtRenderFromEnvironmentPatches = "cannot render FromEnvironment patches for composed resource %q"
 	errFmtRenderMetadata               = "cannot render metadata for composed resource %q"
 	errFmtGenerateName                 = "cannot generate a name for composed resource %q"
+	errFmtPatchTolerated               = "tolerated failed patch of composed resource %q"
 	errFmtExtractDetails               = "cannot extract composite resource connection details from composed resource %q"
 	errFmtCheckReadiness               = "cannot check whether composed resource %q is ready"
 )
@@ -166,6 +171,23 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		return CompositionResult{}, errors.Wrap(err, errInline)
 	}
 
+	// Omit templates whose condition doesn't hold. Composed resources that
+	// were rendered from an omitted template are garbage collected when we
+	// associate templates below.
+	ct, err = SelectTemplates(xr, ct)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errSelectTemplates)
+	}
+
+	// Expand forEach templates into one template per element of the XR array
+	// they range over. Composed resources whose element no longer exists are
+	// garbage collected when we associate templates below, because there is
+	// no longer a template with their name.
+	ct, elements, err := ExpandForEachTemplates(xr, ct)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errForEach)
+	}
+
 	// Figure out which templates are associated with which existing composed
 	// resources. This results in an array of templates associated with an array
 	// of entries in the XR's spec.resourceRefs array. If we're using a
@@ -178,9 +200,21 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		return CompositionResult{}, errors.Wrap(err, errAssociate)
 	}
 
+	// Patches that append to an array of the XR build it from scratch each
+	// reconcile. We reset each array once, before the first patch that
+	// appends to it.
+	reset := make(map[string]bool)
+
 	// If we have an environment, run all environment patches before composing
 	// resources.
 	if req.Environment != nil && req.Revision.Spec.Environment != nil {
+		pas := make([]v1.Patch, 0, len(req.Revision.Spec.Environment.Patches))
+		for i := range req.Revision.Spec.Environment.Patches {
+			pas = append(pas, *req.Revision.Spec.Environment.Patches[i].ToPatch())
+		}
+		if err := resetAppendedArrays(xr, reset, pas...); err != nil {
+			return CompositionResult{}, errors.Wrap(err, errResetArrays)
+		}
 		for i, p := range req.Revision.Spec.Environment.Patches {
 			if err := ApplyEnvironmentPatch(p, xr, req.Environment); err != nil {
 				return CompositionResult{}, errors.Wrapf(err, errFmtPatchEnvironment, i)
@@ -188,8 +222,21 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		}
 	}
 
+	// Events about a composed resource are annotated with the name of its
+	// template, so they can be attributed to it.
 	events := make([]event.Event, 0)
 
+	// Patches that fail but whose error policy tolerates the failure don't
+	// prevent a composed resource from being rendered. We report them as
+	// warnings, and in the XR's PatchesApplied condition.
+	tolerated := make(map[string]bool)
+	tolerate := func(name string, errs []error) {
+		for _, err := range errs {
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtPatchTolerated, name), AnnotationKeyCompositionResourceName, name))
+			tolerated[name] = true
+		}
+	}
+
 	// We optimistically render all composed resources that we are able to with
 	// the expectation that any that we fail to render will subsequently have
 	// their error corrected by manual intervention or propagation of a required
@@ -216,24 +263,35 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		// error when a patch failed we might never reach the patch that would
 		// unblock it.
 
+		// The patches of an expanded forEach template may read their element
+		// from the XR.
+		src := xr
+		if e, ok := elements[ResourceName(name)]; ok {
+			src = ForEachSource(xr, e)
+		}
+
 		rendered := true
-		if err := RenderFromCompositePatches(r, xr, ta.Template.Patches); err != nil {
-			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderFromCompositePatches, name)))
+		terrs, err := RenderFromCompositePatches(r, src, ta.Template.Patches, ta.Template.OnPatchError)
+		tolerate(name, terrs)
+		if err != nil {
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderFromCompositePatches, name), AnnotationKeyCompositionResourceName, name))
 			rendered = false
 		}
 
-		if err = RenderToAndFromEnvironmentPatches(r, req.Environment, ta.Template.Patches); err != nil {
-			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderFromEnvironmentPatches, name)))
+		terrs, err = RenderToAndFromEnvironmentPatches(r, req.Environment, ta.Template.Patches, ta.Template.OnPatchError)
+		tolerate(name, terrs)
+		if err != nil {
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderFromEnvironmentPatches, name), AnnotationKeyCompositionResourceName, name))
 			rendered = false
 		}
 
 		if err := RenderComposedResourceMetadata(r, xr, ResourceName(ptr.Deref(ta.Template.Name, ""))); err != nil {
-			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderMetadata, name)))
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderMetadata, name), AnnotationKeyCompositionResourceName, name))
 			rendered = false
 		}
 
 		if err := c.composed.GenerateName(ctx, r); err != nil {
-			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtGenerateName, name)))
+			events = append(events, event.Warning(reasonCompose, errors.Wrapf(err, errFmtGenerateName, name), AnnotationKeyCompositionResourceName, name))
 			rendered = false
 		}
 
@@ -291,6 +349,9 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// in tas - i.e. a resources resource for every resource template.
 	resources := make([]ComposedResource, len(tas))
 	xrConnDetails := managed.ConnectionDetails{}
+	if err := resetAppendedArrays(xr, reset, toXRPatchesFromTAs(tas)...); err != nil {
+		return CompositionResult{}, errors.Wrap(err, errResetArrays)
+	}
 	for i := range tas {
 		t := tas[i].Template
 		cd := cds[i]
@@ -307,7 +368,9 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			continue
 		}
 
-		if err := RenderToCompositePatches(xr, cd, t.Patches); err != nil {
+		terrs, err := RenderToCompositePatches(xr, cd, t.Patches, t.OnPatchError)
+		tolerate(string(name), terrs)
+		if err != nil {
 			// Failures to render ToComposite patches are terminal because this
 			// indicates a Required ToCompositeFieldPath patch failed; i.e. the
 			// composite was _required_ to be patched, but wasn't.
@@ -354,7 +417,16 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		return CompositionResult{}, errors.Wrap(err, errUpdate)
 	}
 
-	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events}, nil
+	patched := v1.AllPatchesApplied()
+	if len(tolerated) > 0 {
+		names := make([]string, 0, len(tolerated))
+		for name := range tolerated {
+			names = append(names, name)
+		}
+		patched = v1.PatchesSkipped(fmt.Sprintf("Skipped or defaulted failed patches of composed resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, names)))
+	}
+
+	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events, Conditions: []xpv1.Condition{patched}}, nil
 }
 
 // toXRPatchesFromTAs selects patches defined in composed templates,
@@ -377,7 +449,7 @@ func filterPatches(pas []v1.Patch, onlyTypes ...v1.PatchType) []v1.Patch {
 		include[t] = true
 	}
 	for _, p := range pas {
-		if include[p.Type] {
+		if include[p.GetType()] {
 			filtered = append(filtered, p)
 		}
 	}
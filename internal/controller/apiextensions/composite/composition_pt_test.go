@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,8 +32,10 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -44,6 +47,7 @@ import (
 
 func TestPTCompose(t *testing.T) {
 	errBoom := errors.New("boom")
+	_, errMissing := fieldpath.Pave(map[string]any{}).GetValue("spec.missing")
 	details := managed.ConnectionDetails{"a": []byte("b")}
 	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource"}`)}
 
@@ -393,6 +397,80 @@ func TestPTCompose(t *testing.T) {
 						Ready:        true,
 					}},
 					ConnectionDetails: details,
+					Conditions:        []xpv1.Condition{v1.AllPatchesApplied()},
+				},
+			},
+		},
+		"ToleratedPatchErrors": {
+			reason: "We should compose resources whose failed patches are tolerated by their error policy, and report the failed patches.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet:    test.NewMockGetFn(nil),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockPatch:  test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: ptr.To("cool-resource"),
+								Base: base,
+								Patches: []v1.Patch{
+									{
+										Type:          v1.PatchTypeFromCompositeFieldPath,
+										FromFieldPath: ptr.To("spec.missing"),
+										Policy:        &v1.PatchPolicy{FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired)},
+									},
+									{
+										Type:          v1.PatchTypeFromCompositeFieldPath,
+										FromFieldPath: ptr.To("spec.missing"),
+										Policy: &v1.PatchPolicy{
+											FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired),
+											OnError: &v1.PatchErrorPolicy{
+												Action: v1.PatchErrorActionDefault,
+												Value:  &extv1.JSON{Raw: []byte(`"default"`)},
+											},
+										},
+									},
+								},
+								OnPatchError: &v1.PatchErrorPolicy{Action: v1.PatchErrorActionSkip},
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(NameGeneratorFn(func(ctx context.Context, cd resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+					}},
+					ConnectionDetails: details,
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errors.Wrapf(errMissing, errFmtPatch, v1.PatchTypeFromCompositeFieldPath, 0), errFmtPatchTolerated, "cool-resource"), AnnotationKeyCompositionResourceName, "cool-resource"),
+						event.Warning(reasonCompose, errors.Wrapf(errors.Wrapf(errMissing, errFmtPatch, v1.PatchTypeFromCompositeFieldPath, 1), errFmtPatchTolerated, "cool-resource"), AnnotationKeyCompositionResourceName, "cool-resource"),
+					},
+					Conditions: []xpv1.Condition{v1.PatchesSkipped("Skipped or defaulted failed patches of composed resources: cool-resource")},
 				},
 			},
 		},
@@ -464,8 +542,9 @@ func TestPTCompose(t *testing.T) {
 					},
 					ConnectionDetails: details,
 					Events: []event.Event{
-						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtGenerateName, "uncool-resource")),
+						event.Warning(reasonCompose, errors.Wrapf(errBoom, errFmtGenerateName, "uncool-resource"), AnnotationKeyCompositionResourceName, "uncool-resource"),
 					},
+					Conditions: []xpv1.Condition{v1.AllPatchesApplied()},
 				},
 			},
 		},
@@ -477,7 +556,7 @@ func TestPTCompose(t *testing.T) {
 			c := NewPTComposer(tc.params.kube, tc.params.o...)
 			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
 
-			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty()); diff != "" {
+			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
 			}
 
@@ -489,6 +568,71 @@ func TestPTCompose(t *testing.T) {
 	}
 }
 
+func TestPTComposeAppendArray(t *testing.T) {
+	template := func(name, zones string, policy v1.ToFieldPathPolicy, to string) v1.ComposedTemplate {
+		return v1.ComposedTemplate{
+			Name: ptr.To(name),
+			Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource","spec":{"zones":` + zones + `}}`)},
+			Patches: []v1.Patch{{
+				Type:          v1.PatchTypeToCompositeFieldPath,
+				FromFieldPath: ptr.To("spec.zones"),
+				ToFieldPath:   ptr.To(to),
+				Policy:        &v1.PatchPolicy{ToFieldPath: ptr.To(policy)},
+			}},
+		}
+	}
+	tas := []TemplateAssociation{
+		{Template: template("a", `["a","b"]`, v1.ToFieldPathPolicyAppendArray, "status.zones")},
+		{Template: template("b", `["b","c"]`, v1.ToFieldPathPolicyAppendArray, "status.zones")},
+		{Template: template("c", `["a","b"]`, v1.ToFieldPathPolicyAppendUniqueArray, "status.uniqueZones")},
+		{Template: template("d", `["b","c"]`, v1.ToFieldPathPolicyAppendUniqueArray, "status.uniqueZones")},
+	}
+
+	c := NewPTComposer(&test.MockClient{
+		MockUpdate: test.NewMockUpdateFn(nil),
+
+		// Apply uses Get, Create, and Patch.
+		MockGet:    test.NewMockGetFn(nil),
+		MockCreate: test.NewMockCreateFn(nil),
+		MockPatch:  test.NewMockPatchFn(nil),
+	},
+		WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+			return tas, nil
+		})),
+		WithComposedNameGenerator(NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+		WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (bool, error) {
+			return true, nil
+		})),
+	)
+
+	// The XR has values appended by composed resources that have gone away.
+	xr := WithParentLabel()
+	xr.SetAPIVersion("example.org/v1")
+	xr.SetKind("XR")
+	_ = fieldpath.Pave(xr.Object).SetValue("status.zones", []any{"gone"})
+	_ = fieldpath.Pave(xr.Object).SetValue("status.uniqueZones", []any{"gone"})
+
+	want := map[string]any{
+		"zones":       []any{"a", "b", "b", "c"},
+		"uniqueZones": []any{"a", "b", "c"},
+	}
+
+	// Each reconcile should build the arrays from scratch, rather than append
+	// to the arrays built by the last reconcile.
+	for i := 0; i < 2; i++ {
+		if _, err := c.Compose(context.Background(), xr, CompositionRequest{Revision: &v1.CompositionRevision{}}); err != nil {
+			t.Fatalf("\nCompose(...): reconcile %d: %s", i+1, err)
+		}
+		got, _ := fieldpath.Pave(xr.Object).GetValue("status")
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("\nCompose(...): reconcile %d: -want status, +got status:\n%s", i+1, diff)
+		}
+	}
+}
+
 func TestAssociateByOrder(t *testing.T) {
 	t0 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("zero")}}
 	t1 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("one")}}
@@ -544,6 +688,41 @@ func TestAssociateByOrder(t *testing.T) {
 	}
 }
 
+func TestFilterPatches(t *testing.T) {
+	untyped := v1.Patch{FromFieldPath: ptr.To("spec.a")}
+	from := v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: ptr.To("spec.b")}
+	to := v1.Patch{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: ptr.To("status.c")}
+
+	cases := map[string]struct {
+		reason string
+		pas    []v1.Patch
+		only   []v1.PatchType
+		want   []v1.Patch
+	}{
+		"FromCompositeFieldPath": {
+			reason: "A patch with no type should be selected as a FromCompositeFieldPath patch.",
+			pas:    []v1.Patch{untyped, from, to},
+			only:   []v1.PatchType{v1.PatchTypeFromCompositeFieldPath},
+			want:   []v1.Patch{untyped, from},
+		},
+		"ToCompositeFieldPath": {
+			reason: "A patch with no type should not be selected as a ToCompositeFieldPath patch.",
+			pas:    []v1.Patch{untyped, from, to},
+			only:   []v1.PatchType{v1.PatchTypeToCompositeFieldPath},
+			want:   []v1.Patch{to},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := filterPatches(tc.pas, tc.only...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nfilterPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGarbageCollectingAssociator(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -81,40 +81,62 @@ func RenderFromJSON(o resource.Object, data []byte) error {
 }
 
 // RenderFromCompositePatches renders the supplied composed resource by applying
-// all patches that are _from_ the supplied composite resource.
-func RenderFromCompositePatches(cd resource.Composed, xr resource.Composite, p []v1.Patch) error {
+// all patches that are _from_ the supplied composite resource. Patches that fail
+// are handled according to their error policy, or the supplied policy if they
+// have none. It returns the errors of any patches that were tolerated.
+func RenderFromCompositePatches(cd resource.Composed, xr resource.Composite, p []v1.Patch, ep *v1.PatchErrorPolicy) ([]error, error) {
+	tolerated := make([]error, 0)
 	for i := range p {
-		if err := Apply(p[i], xr, cd, patchTypesFromXR()...); err != nil {
-			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		terr, err := ApplyWithErrorPolicy(p[i], ep, xr, cd, patchTypesFromXR()...)
+		if err != nil {
+			return tolerated, errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if terr != nil {
+			tolerated = append(tolerated, errors.Wrapf(terr, errFmtPatch, p[i].Type, i))
 		}
 	}
-	return nil
+	return tolerated, nil
 }
 
 // RenderToAndFromEnvironmentPatches renders the supplied composed resource by
-// applying all patches that are from or to the supplied environment.
-func RenderToAndFromEnvironmentPatches(cd resource.Composed, e *Environment, p []v1.Patch) error {
+// applying all patches that are from or to the supplied environment. Patches
+// that fail are handled according to their error policy, or the supplied
+// policy if they have none. It returns the errors of any patches that were
+// tolerated.
+func RenderToAndFromEnvironmentPatches(cd resource.Composed, e *Environment, p []v1.Patch, ep *v1.PatchErrorPolicy) ([]error, error) {
+	tolerated := make([]error, 0)
 	if e == nil {
-		return nil
+		return tolerated, nil
 	}
 	for i := range p {
-		if err := ApplyToObjects(p[i], e, cd, patchTypesFromToEnvironment()...); err != nil {
-			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		terr, err := ApplyWithErrorPolicy(p[i], ep, e, cd, patchTypesFromToEnvironment()...)
+		if err != nil {
+			return tolerated, errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if terr != nil {
+			tolerated = append(tolerated, errors.Wrapf(terr, errFmtPatch, p[i].Type, i))
 		}
 	}
-	return nil
+	return tolerated, nil
 }
 
 // RenderToCompositePatches renders the supplied composite resource by applying
 // all patches that are _from_ the supplied composed resource. composed resource
-// and template.
-func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v1.Patch) error {
+// and template. Patches that fail are handled according to their error policy,
+// or the supplied policy if they have none. It returns the errors of any
+// patches that were tolerated.
+func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v1.Patch, ep *v1.PatchErrorPolicy) ([]error, error) {
+	tolerated := make([]error, 0)
 	for i := range p {
-		if err := Apply(p[i], xr, cd, patchTypesToXR()...); err != nil {
-			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		terr, err := ApplyWithErrorPolicy(p[i], ep, xr, cd, patchTypesToXR()...)
+		if err != nil {
+			return tolerated, errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if terr != nil {
+			tolerated = append(tolerated, errors.Wrapf(terr, errFmtPatch, p[i].Type, i))
 		}
 	}
-	return nil
+	return tolerated, nil
 }
 
 // RenderComposedResourceMetadata derives composed resource metadata from the
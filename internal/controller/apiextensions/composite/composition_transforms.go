@@ -25,9 +25,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/adler32"
+	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -36,11 +39,17 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcel"
+	"github.com/crossplane/crossplane/internal/xtemplate"
+	"github.com/crossplane/crossplane/internal/xtime"
 )
 
 const (
 	errMathTransformTypeFailed = "type %s is not supported for math transform type"
 	errFmtMathInputNonNumber   = "input is required to be a number for math transformer, got %T"
+	errFmtMathOutOfRange       = "result %v is out of range for an integer"
+	errMathDivideByZero        = "cannot divide by zero"
+	errMathOverflow            = "integer overflow"
 
 	errFmtRequiredField                 = "%s is required by type %s"
 	errFmtConvertInputTypeNotSupported  = "invalid input type %T"
@@ -66,10 +75,27 @@ const (
 	errStringTransformTypeConvert       = "string transform of type %s convert is not set"
 	errStringTransformTypeTrim          = "string transform of type %s trim is not set"
 	errStringTransformTypeRegexp        = "string transform of type %s regexp is not set"
+	errStringTransformTypeTemplate      = "string transform of type %s template is not set"
 	errStringTransformTypeRegexpFailed  = "could not compile regexp"
 	errStringTransformTypeRegexpNoMatch = "regexp %q had no matches for group %d"
 	errStringConvertTypeFailed          = "type %s is not supported for string convert"
 
+	errFmtCELOutputType = "expression returned %s, not %s"
+
+	errFmtTimeInputNotString   = "input is required to be a string for time transform, got %T"
+	errFmtTimeTransformType    = "type %s is not supported for time transform type"
+	errFmtTimeParse            = "cannot parse time %q with layout %q"
+	errFmtTimeTransformMissing = "time transform of type %s requires %s"
+	errTimeParseDuration       = "cannot parse duration"
+	errTimeUnit                = "cannot convert duration"
+
+	errFmtCollectionInputType    = "collection transform of type %s requires %s input, got %T"
+	errFmtCollectionTransform    = "type %s is not supported for collection transform type"
+	errFmtCollectionJoinElement  = "cannot join element at index %d of type %T"
+	errFmtCollectionSortElement  = "cannot sort element at index %d of type %T"
+	errCollectionSortMixedTypes  = "cannot sort an array of both strings and numbers"
+	errFmtCollectionNotFlattened = "cannot flatten element at index %d: not an array"
+
 	errDecodeString = "string is not valid base64"
 	errMarshalJSON  = "cannot marshal to JSON"
 	errHash         = "cannot generate hash"
@@ -77,7 +103,13 @@ const (
 )
 
 // Resolve the supplied Transform.
-func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
+func Resolve(t v1.Transform, input any) (any, error) {
+	return ResolveFrom(t, nil, input)
+}
+
+// ResolveFrom resolves the supplied Transform. The supplied source is the
+// object the patch reads from, which Template string transforms may reference.
+func ResolveFrom(t v1.Transform, source map[string]any, input any) (any, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
 	var out any
 	var err error
 
@@ -101,12 +133,27 @@ func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This
 		if t.String == nil {
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
-		out, err = ResolveString(*t.String, input)
+		out, err = ResolveStringFrom(*t.String, source, input)
 	case v1.TransformTypeConvert:
 		if t.Convert == nil {
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
 		out, err = ResolveConvert(*t.Convert, input)
+	case v1.TransformTypeCEL:
+		if t.CEL == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveCEL(*t.CEL, input)
+	case v1.TransformTypeTime:
+		if t.Time == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveTime(*t.Time, input)
+	case v1.TransformTypeCollection:
+		if t.Collection == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveCollection(*t.Collection, input)
 	default:
 		return nil, errors.Errorf(errFmtTypeNotSupported, string(t.Type))
 	}
@@ -129,6 +176,12 @@ func ResolveMath(t v1.MathTransform, input any) (any, error) {
 		return resolveMathMultiply(t, input)
 	case v1.MathTransformTypeClampMin, v1.MathTransformTypeClampMax:
 		return resolveMathClamp(t, input)
+	case v1.MathTransformTypeAdd, v1.MathTransformTypeSubtract, v1.MathTransformTypeModulo:
+		return resolveMathArithmetic(t, input)
+	case v1.MathTransformTypeDivide:
+		return resolveMathDivide(t, input)
+	case v1.MathTransformTypeRound, v1.MathTransformTypeCeil, v1.MathTransformTypeFloor:
+		return resolveMathRound(t, input)
 	default:
 		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
 	}
@@ -140,9 +193,9 @@ func ResolveMath(t v1.MathTransform, input any) (any, error) {
 func resolveMathMultiply(t v1.MathTransform, input any) (any, error) {
 	switch i := input.(type) {
 	case int:
-		return int64(i) * *t.Multiply, nil
+		return multiplyInt64(int64(i), *t.Multiply)
 	case int64:
-		return i * *t.Multiply, nil
+		return multiplyInt64(i, *t.Multiply)
 	case float64:
 		return i * float64(*t.Multiply), nil
 	default:
@@ -151,8 +204,9 @@ func resolveMathMultiply(t v1.MathTransform, input any) (any, error) {
 }
 
 // resolveMathClamp resolves a clamp transform, returning an error if the input
-// is not a number. depending on the type of clamp, the result will be either
-// the input or the clamp value, preserving their original types.
+// is not a number. Float inputs are truncated to an integer before they're
+// compared to the clamp value. If the input is clamped the result is the int64
+// clamp value, otherwise it's the unchanged input.
 func resolveMathClamp(t v1.MathTransform, input any) (any, error) {
 	in := int64(0)
 	switch i := input.(type) {
@@ -181,6 +235,144 @@ func resolveMathClamp(t v1.MathTransform, input any) (any, error) {
 	return input, nil
 }
 
+// resolveMathArithmetic resolves an add, subtract, or modulo transform. If the
+// input is a float, the result will be a float64, otherwise it will be an
+// int64.
+func resolveMathArithmetic(t v1.MathTransform, input any) (any, error) {
+	switch i := input.(type) {
+	case int, int64:
+		in := toInt64(i)
+		switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+		case v1.MathTransformTypeAdd:
+			return addInt64(in, *t.Add)
+		case v1.MathTransformTypeSubtract:
+			return subtractInt64(in, *t.Subtract)
+		case v1.MathTransformTypeModulo:
+			if *t.Modulo == 0 {
+				return nil, errors.New(errMathDivideByZero)
+			}
+			return in % *t.Modulo, nil
+		}
+	case float64:
+		switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+		case v1.MathTransformTypeAdd:
+			return i + float64(*t.Add), nil
+		case v1.MathTransformTypeSubtract:
+			return i - float64(*t.Subtract), nil
+		case v1.MathTransformTypeModulo:
+			if *t.Modulo == 0 {
+				return nil, errors.New(errMathDivideByZero)
+			}
+			return math.Mod(i, float64(*t.Modulo)), nil
+		}
+	}
+	return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
+}
+
+// resolveMathDivide resolves a divide transform. The result is always a
+// float64.
+func resolveMathDivide(t v1.MathTransform, input any) (any, error) {
+	if *t.Divide == 0 {
+		return nil, errors.New(errMathDivideByZero)
+	}
+	switch i := input.(type) {
+	case int, int64:
+		return float64(toInt64(i)) / float64(*t.Divide), nil
+	case float64:
+		return i / float64(*t.Divide), nil
+	}
+	return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+}
+
+// resolveMathRound resolves a round, ceil, or floor transform, which rounds
+// the input to the nearest, next, or previous multiple. The result is always
+// an int64. Integer inputs are rounded without converting them to floats.
+func resolveMathRound(t v1.MathTransform, input any) (any, error) {
+	m := t.GetMultiple()
+	switch i := input.(type) {
+	case int, int64:
+		in := toInt64(i)
+		var q int64
+		switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+		case v1.MathTransformTypeFloor:
+			q = floorDivInt64(in, m)
+		case v1.MathTransformTypeCeil:
+			q = -floorDivInt64(-in, m)
+		case v1.MathTransformTypeRound:
+			// Round half away from zero, like math.Round.
+			q = in / m
+			if r := in % m; r >= m-r {
+				q++
+			} else if -r >= m+r {
+				q--
+			}
+		}
+		return multiplyInt64(q, m)
+	case float64:
+		var q float64
+		switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+		case v1.MathTransformTypeFloor:
+			q = math.Floor(i / float64(m))
+		case v1.MathTransformTypeCeil:
+			q = math.Ceil(i / float64(m))
+		case v1.MathTransformTypeRound:
+			q = math.Round(i / float64(m))
+		}
+		out := q * float64(m)
+		if math.IsNaN(out) || out < math.MinInt64 || out >= math.MaxInt64 {
+			return nil, errors.Errorf(errFmtMathOutOfRange, out)
+		}
+		return int64(out), nil
+	}
+	return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+}
+
+// toInt64 returns the supplied int or int64 as an int64.
+func toInt64(i any) int64 {
+	if v, ok := i.(int); ok {
+		return int64(v)
+	}
+	return i.(int64) //nolint:forcetypeassert // Only called with ints and int64s.
+}
+
+// addInt64 returns a+b, or an error if the result overflows.
+func addInt64(a, b int64) (any, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return nil, errors.New(errMathOverflow)
+	}
+	return a + b, nil
+}
+
+// subtractInt64 returns a-b, or an error if the result overflows.
+func subtractInt64(a, b int64) (any, error) {
+	if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+		return nil, errors.New(errMathOverflow)
+	}
+	return a - b, nil
+}
+
+// multiplyInt64 returns a*b, or an error if the result overflows.
+func multiplyInt64(a, b int64) (any, error) {
+	if a == 0 || b == 0 {
+		return int64(0), nil
+	}
+	r := a * b
+	if r/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return nil, errors.New(errMathOverflow)
+	}
+	return r, nil
+}
+
+// floorDivInt64 returns a/b rounded toward negative infinity. b must be
+// positive.
+func floorDivInt64(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
 // ResolveMap resolves a Map transform.
 func ResolveMap(t v1.MapTransform, input any) (any, error) {
 	switch i := input.(type) {
@@ -282,6 +474,12 @@ func unmarshalJSON(j extv1.JSON, output *any) error {
 
 // ResolveString resolves a String transform.
 func ResolveString(t v1.StringTransform, input any) (string, error) {
+	return ResolveStringFrom(t, nil, input)
+}
+
+// ResolveStringFrom resolves a String transform. The supplied source is the
+// object the patch reads from, which Template string transforms may reference.
+func ResolveStringFrom(t v1.StringTransform, source map[string]any, input any) (string, error) {
 	switch t.Type {
 	case v1.StringTransformTypeFormat:
 		if t.Format == nil {
@@ -303,6 +501,14 @@ func ResolveString(t v1.StringTransform, input any) (string, error) {
 			return "", errors.Errorf(errStringTransformTypeRegexp, string(t.Type))
 		}
 		return stringRegexpTransform(input, *t.Regexp)
+	case v1.StringTransformTypeTemplate:
+		if t.Template == nil {
+			return "", errors.Errorf(errStringTransformTypeTemplate, string(t.Type))
+		}
+		return templates.Execute(*t.Template, map[string]any{
+			v1.StringTransformTemplateVariableInput:  input,
+			v1.StringTransformTemplateVariableSource: source,
+		})
 	default:
 		return "", errors.Errorf(errStringTransformTypeFailed, string(t.Type))
 	}
@@ -384,6 +590,246 @@ func stringRegexpTransform(input any, r v1.StringTransformRegexp) (string, error
 	return groups[g], nil
 }
 
+// celPrograms caches compiled CEL programs, so that the expressions of CEL
+// transforms are not parsed and checked every time a Composition is rendered.
+var celPrograms = xcel.NewCache(xcel.DefaultCacheSize) //nolint:gochecknoglobals // Transforms are resolved by plain functions.
+
+// templates caches parsed templates, so that the templates of Template string
+// transforms are not parsed every time a Composition is rendered.
+var templates = xtemplate.NewCache(xtemplate.DefaultCacheSize) //nolint:gochecknoglobals // Transforms are resolved by plain functions.
+
+// ResolveCEL resolves a CEL transform by evaluating its expression with the
+// input bound to a variable.
+func ResolveCEL(t v1.CELTransform, input any) (any, error) {
+	out, err := celPrograms.Eval(t.Expression, map[string]any{v1.CELTransformVariableInput: input})
+	if err != nil {
+		return nil, err
+	}
+	if t.OutputType == nil {
+		return out, nil
+	}
+	if got := transformIOTypeOf(out); !got.IsEquivalent(*t.OutputType) {
+		return nil, errors.Errorf(errFmtCELOutputType, got, *t.OutputType)
+	}
+	return out, nil
+}
+
+// transformIOTypeOf returns the TransformIOType of the supplied plain Go value,
+// or an empty type if it has none.
+func transformIOTypeOf(v any) v1.TransformIOType {
+	switch v.(type) {
+	case string:
+		return v1.TransformIOTypeString
+	case bool:
+		return v1.TransformIOTypeBool
+	case int:
+		return v1.TransformIOTypeInt
+	case int64:
+		return v1.TransformIOTypeInt64
+	case float64:
+		return v1.TransformIOTypeFloat64
+	case map[string]any:
+		return v1.TransformIOTypeObject
+	case []any:
+		return v1.TransformIOTypeArray
+	}
+	return ""
+}
+
+// ResolveTime resolves a Time transform.
+func ResolveTime(t v1.TimeTransform, input any) (any, error) {
+	in, ok := input.(string)
+	if !ok {
+		return nil, errors.Errorf(errFmtTimeInputNotString, input)
+	}
+
+	switch t.Type {
+	case v1.TimeTransformTypeFormat, v1.TimeTransformTypeAddDuration:
+		ts, err := time.Parse(t.GetInputLayout(), in)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtTimeParse, in, t.GetInputLayout())
+		}
+		if t.Type == v1.TimeTransformTypeAddDuration {
+			if t.Duration == nil {
+				return nil, errors.Errorf(errFmtTimeTransformMissing, t.Type, "duration")
+			}
+			d, err := xtime.ParseDuration(*t.Duration)
+			if err != nil {
+				return nil, errors.Wrap(err, errTimeParseDuration)
+			}
+			ts = ts.Add(d)
+		}
+		return ts.Format(t.GetOutputLayout()), nil
+	case v1.TimeTransformTypeConvertDuration:
+		if t.Unit == nil {
+			return nil, errors.Errorf(errFmtTimeTransformMissing, t.Type, "unit")
+		}
+		d, err := xtime.ParseDuration(in)
+		if err != nil {
+			return nil, errors.Wrap(err, errTimeParseDuration)
+		}
+		u, err := xtime.Unit(*t.Unit)
+		if err != nil {
+			return nil, errors.Wrap(err, errTimeUnit)
+		}
+		return int64(d / u), nil
+	}
+
+	return nil, errors.Errorf(errFmtTimeTransformType, t.Type)
+}
+
+// ResolveCollection resolves a Collection transform.
+func ResolveCollection(t v1.CollectionTransform, input any) (any, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if got := transformIOTypeOf(input); got != t.GetInputType() {
+		return nil, errors.Errorf(errFmtCollectionInputType, t.Type, t.GetInputType(), input)
+	}
+
+	switch t.Type {
+	case v1.CollectionTransformTypeJoin:
+		in := input.([]any) //nolint:forcetypeassert // Checked above.
+		elems := make([]string, len(in))
+		for i, e := range in {
+			switch v := e.(type) {
+			case string:
+				elems[i] = v
+			case bool, int, int64, float64:
+				elems[i] = fmt.Sprint(v)
+			default:
+				return nil, errors.Errorf(errFmtCollectionJoinElement, i, e)
+			}
+		}
+		return strings.Join(elems, t.GetSeparator()), nil
+
+	case v1.CollectionTransformTypeSplit:
+		in := input.(string) //nolint:forcetypeassert // Checked above.
+		out := make([]any, 0)
+		if in == "" {
+			return out, nil
+		}
+		for _, e := range strings.Split(in, t.GetSeparator()) {
+			out = append(out, e)
+		}
+		return out, nil
+
+	case v1.CollectionTransformTypeFlatten:
+		out := make([]any, 0)
+		for i, e := range input.([]any) { //nolint:forcetypeassert // Checked above.
+			a, ok := e.([]any)
+			if !ok {
+				return nil, errors.Errorf(errFmtCollectionNotFlattened, i)
+			}
+			out = append(out, a...)
+		}
+		return out, nil
+
+	case v1.CollectionTransformTypeSort:
+		return sortCollection(input.([]any)) //nolint:forcetypeassert // Checked above.
+
+	case v1.CollectionTransformTypeUnique:
+		out := make([]any, 0)
+		for _, e := range input.([]any) { //nolint:forcetypeassert // Checked above.
+			if !containsValue(out, e) {
+				out = append(out, e)
+			}
+		}
+		return out, nil
+
+	case v1.CollectionTransformTypeKeys, v1.CollectionTransformTypeValues:
+		in := input.(map[string]any) //nolint:forcetypeassert // Checked above.
+		keys := make([]string, 0, len(in))
+		for k := range in {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = k
+			if t.Type == v1.CollectionTransformTypeValues {
+				out[i] = in[k]
+			}
+		}
+		return out, nil
+
+	case v1.CollectionTransformTypePick, v1.CollectionTransformTypeOmit:
+		in := input.(map[string]any) //nolint:forcetypeassert // Checked above.
+		fields := make(map[string]bool, len(t.Fields))
+		for _, f := range t.Fields {
+			fields[f] = true
+		}
+		out := make(map[string]any)
+		for k, v := range in {
+			if fields[k] == (t.Type == v1.CollectionTransformTypePick) {
+				out[k] = v
+			}
+		}
+		return out, nil
+	}
+
+	return nil, errors.Errorf(errFmtCollectionTransform, t.Type)
+}
+
+// sortCollection returns a copy of the supplied array sorted in ascending
+// order. The array must contain only strings, or only numbers.
+func sortCollection(in []any) (any, error) {
+	var strs, nums bool
+	for i, e := range in {
+		switch e.(type) {
+		case string:
+			strs = true
+		case int, int64, float64:
+			nums = true
+		default:
+			return nil, errors.Errorf(errFmtCollectionSortElement, i, e)
+		}
+	}
+	if strs && nums {
+		return nil, errors.New(errCollectionSortMixedTypes)
+	}
+
+	out := make([]any, len(in))
+	copy(out, in)
+	sort.SliceStable(out, func(i, j int) bool {
+		if strs {
+			return out[i].(string) < out[j].(string) //nolint:forcetypeassert // Checked above.
+		}
+		return lessNumber(out[i], out[j])
+	})
+	return out, nil
+}
+
+// lessNumber returns true if number a is less than number b. Integers are
+// compared without converting them to floats.
+func lessNumber(a, b any) bool {
+	ai, aInt := a.(int64)
+	if v, ok := a.(int); ok {
+		ai, aInt = int64(v), true
+	}
+	bi, bInt := b.(int64)
+	if v, ok := b.(int); ok {
+		bi, bInt = int64(v), true
+	}
+	if aInt && bInt {
+		return ai < bi
+	}
+	return toFloat64(a) < toFloat64(b)
+}
+
+// toFloat64 returns the supplied number as a float64.
+func toFloat64(n any) float64 {
+	switch v := n.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
 // ResolveConvert resolves a Convert transform by looking up the appropriate
 // conversion function for the given input type and invoking it.
 func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
@@ -19,9 +19,12 @@ package composite
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -459,12 +462,16 @@ func TestMatchResolve(t *testing.T) {
 
 func TestMathResolve(t *testing.T) {
 	two := int64(2)
+	ten := int64(10)
+	zero := int64(0)
 
 	type args struct {
 		mathType   v1.MathTransformType
 		multiplier *int64
 		clampMin   *int64
 		clampMax   *int64
+		operand    *int64
+		multiple   *int64
 		i          any
 	}
 	type want struct {
@@ -624,10 +631,246 @@ func TestMathResolve(t *testing.T) {
 				},
 			},
 		},
+		"ClampMinFloatBelow": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				clampMin: &two,
+				i:        1.5,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMinFloatAbove": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMin,
+				clampMin: &two,
+				i:        2.5,
+			},
+			want: want{
+				o: 2.5,
+			},
+		},
+		"ClampMaxFloatAbove": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        3.5,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"ClampMaxFloatBelow": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        1.5,
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"ClampMaxFloatTruncated": {
+			args: args{
+				mathType: v1.MathTransformTypeClampMax,
+				clampMax: &two,
+				i:        2.5,
+			},
+			want: want{
+				o: 2.5,
+			},
+		},
+		"MultiplyOverflow": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: &two,
+				i:          int64(math.MaxInt64),
+			},
+			want: want{
+				err: errors.New(errMathOverflow),
+			},
+		},
+		"AddSuccessInt64": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				operand:  &two,
+				i:        int64(9007199254740993),
+			},
+			want: want{
+				o: int64(9007199254740995),
+			},
+		},
+		"AddSuccessFloat": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				operand:  &two,
+				i:        0.5,
+			},
+			want: want{
+				o: 2.5,
+			},
+		},
+		"AddNoConfig": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "add",
+				},
+			},
+		},
+		"SubtractSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeSubtract,
+				operand:  &ten,
+				i:        3,
+			},
+			want: want{
+				o: int64(-7),
+			},
+		},
+		"SubtractOverflow": {
+			args: args{
+				mathType: v1.MathTransformTypeSubtract,
+				operand:  &two,
+				i:        int64(math.MinInt64),
+			},
+			want: want{
+				err: errors.New(errMathOverflow),
+			},
+		},
+		"DivideSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeDivide,
+				operand:  &two,
+				i:        int64(5),
+			},
+			want: want{
+				o: 2.5,
+			},
+		},
+		"DivideByZero": {
+			args: args{
+				mathType: v1.MathTransformTypeDivide,
+				operand:  &zero,
+				i:        int64(5),
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "divide",
+				},
+			},
+		},
+		"ModuloSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				operand:  &ten,
+				i:        int64(25),
+			},
+			want: want{
+				o: int64(5),
+			},
+		},
+		"ModuloSuccessFloat": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				operand:  &ten,
+				i:        25.5,
+			},
+			want: want{
+				o: 5.5,
+			},
+		},
+		"RoundSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeRound,
+				i:        2.5,
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"RoundToMultipleInt": {
+			args: args{
+				mathType: v1.MathTransformTypeRound,
+				multiple: &ten,
+				i:        -25,
+			},
+			want: want{
+				o: int64(-30),
+			},
+		},
+		"CeilToMultiple": {
+			args: args{
+				mathType: v1.MathTransformTypeCeil,
+				multiple: &ten,
+				i:        int64(21),
+			},
+			want: want{
+				o: int64(30),
+			},
+		},
+		"CeilToMultipleNegative": {
+			args: args{
+				mathType: v1.MathTransformTypeCeil,
+				multiple: &ten,
+				i:        int64(-21),
+			},
+			want: want{
+				o: int64(-20),
+			},
+		},
+		"FloorToMultipleFloat": {
+			args: args{
+				mathType: v1.MathTransformTypeFloor,
+				multiple: &ten,
+				i:        -20.5,
+			},
+			want: want{
+				o: int64(-30),
+			},
+		},
+		"FloorPreservesPrecision": {
+			args: args{
+				mathType: v1.MathTransformTypeFloor,
+				i:        int64(9007199254740993),
+			},
+			want: want{
+				o: int64(9007199254740993),
+			},
+		},
+		"InvalidMultiple": {
+			args: args{
+				mathType: v1.MathTransformTypeFloor,
+				multiple: &zero,
+				i:        1,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "multiple",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			tr := v1.MathTransform{Type: tc.mathType, Multiply: tc.multiplier, ClampMin: tc.clampMin, ClampMax: tc.clampMax}
+			tr := v1.MathTransform{Type: tc.mathType, Multiply: tc.multiplier, ClampMin: tc.clampMin, ClampMax: tc.clampMax, Multiple: tc.multiple}
+			switch tc.mathType { //nolint:exhaustive // Only these types take an operand.
+			case v1.MathTransformTypeAdd:
+				tr.Add = tc.operand
+			case v1.MathTransformTypeSubtract:
+				tr.Subtract = tc.operand
+			case v1.MathTransformTypeDivide:
+				tr.Divide = tc.operand
+			case v1.MathTransformTypeModulo:
+				tr.Modulo = tc.operand
+			}
 			got, err := ResolveMath(tr, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
@@ -1040,6 +1283,88 @@ func TestStringResolve(t *testing.T) {
 	}
 }
 
+func TestStringTemplateResolve(t *testing.T) {
+	source := map[string]any{
+		"spec": map[string]any{
+			"user": "admin",
+			"host": "db.example.org",
+			"port": int64(5432),
+		},
+	}
+
+	type args struct {
+		template *string
+		source   map[string]any
+		i        any
+	}
+	type want struct {
+		o   string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"TemplateNotSet": {
+			reason: "An error should be returned if the template is not set",
+			args: args{
+				i: "mydb",
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"InputAndSource": {
+			reason: "A template should be able to reference both the input and the source object",
+			args: args{
+				template: ptr.To("postgres://{{ .source.spec.user }}@{{ .source.spec.host }}:{{ .source.spec.port }}/{{ .input }}"),
+				source:   source,
+				i:        "mydb",
+			},
+			want: want{
+				o: "postgres://admin@db.example.org:5432/mydb",
+			},
+		},
+		"Helpers": {
+			reason: "A template should be able to use helpers in pipelines",
+			args: args{
+				template: ptr.To(`{{ .input | trimPrefix "arn:" | upper }}-{{ index .source.spec "region" | default "us-east-1" }}`),
+				source:   source,
+				i:        "arn:bucket",
+			},
+			want: want{
+				o: "BUCKET-us-east-1",
+			},
+		},
+		"MissingField": {
+			reason: "An error should be returned if a template references a missing field",
+			args: args{
+				template: ptr.To("{{ .source.spec.region }}"),
+				source:   source,
+				i:        "mydb",
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := v1.StringTransform{Type: v1.StringTransformTypeTemplate, Template: tc.template}
+			got, err := ResolveStringFrom(tr, tc.source, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("%s\nResolveStringFrom(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nResolveStringFrom(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestConvertResolve(t *testing.T) {
 	type args struct {
 		to     v1.TransformIOType
@@ -1325,3 +1650,403 @@ func TestConvertTransformGetConversionFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestCELResolve(t *testing.T) {
+	type args struct {
+		t v1.CELTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Conditional": {
+			reason: "An expression should be able to branch on its input",
+			args: args{
+				t: v1.CELTransform{Expression: `input.size() > 0 ? input[0] : "none"`},
+				i: []any{"us-east-1a", "us-east-1b"},
+			},
+			want: want{
+				o: "us-east-1a",
+			},
+		},
+		"Arithmetic": {
+			reason: "Integer arithmetic should return an int64",
+			args: args{
+				t: v1.CELTransform{Expression: `input * 1024 + 10`},
+				i: int64(2),
+			},
+			want: want{
+				o: int64(2058),
+			},
+		},
+		"BuildList": {
+			reason: "An expression should be able to build a list",
+			args: args{
+				t: v1.CELTransform{Expression: `input.map(z, "zone-" + z)`},
+				i: []any{"a", "b"},
+			},
+			want: want{
+				o: []any{"zone-a", "zone-b"},
+			},
+		},
+		"BuildObject": {
+			reason: "An expression should be able to build an object",
+			args: args{
+				t: v1.CELTransform{Expression: `{"name": input.name, "size": input.size * 2}`},
+				i: map[string]any{"name": "cool", "size": int64(5)},
+			},
+			want: want{
+				o: map[string]any{"name": "cool", "size": int64(10)},
+			},
+		},
+		"OutputTypeMismatch": {
+			reason: "An error should be returned if the expression does not return the declared type",
+			args: args{
+				t: v1.CELTransform{Expression: `input`, OutputType: ptr.To(v1.TransformIOTypeString)},
+				i: int64(2),
+			},
+			want: want{
+				err: errors.Errorf(errFmtCELOutputType, v1.TransformIOTypeInt64, v1.TransformIOTypeString),
+			},
+		},
+		"OutputTypeEquivalent": {
+			reason: "An int64 result should satisfy a declared int output type",
+			args: args{
+				t: v1.CELTransform{Expression: `input + 1`, OutputType: ptr.To(v1.TransformIOTypeInt)},
+				i: int64(2),
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"EvalError": {
+			reason: "An error should be returned if the expression cannot be evaluated",
+			args: args{
+				t: v1.CELTransform{Expression: `input.missing`},
+				i: map[string]any{},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("no such key: missing"), "cannot evaluate CEL expression"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveCEL(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("%s\nResolveCEL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nResolveCEL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTimeResolve(t *testing.T) {
+	type args struct {
+		t v1.TimeTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NotString": {
+			reason: "An error should be returned if the input is not a string",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeFormat},
+				i: int64(2),
+			},
+			want: want{
+				err: errors.Errorf(errFmtTimeInputNotString, int64(2)),
+			},
+		},
+		"Format": {
+			reason: "A time should be parsed with the input layout and formatted with the output layout",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeFormat, OutputLayout: ptr.To("DateOnly")},
+				i: "2023-10-18T12:30:00Z",
+			},
+			want: want{
+				o: "2023-10-18",
+			},
+		},
+		"FormatCustomLayouts": {
+			reason: "Go time layouts should be supported",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeFormat, InputLayout: ptr.To("02/01/2006"), OutputLayout: ptr.To("RFC1123")},
+				i: "18/10/2023",
+			},
+			want: want{
+				o: "Wed, 18 Oct 2023 00:00:00 UTC",
+			},
+		},
+		"FormatInvalidTime": {
+			reason: "An error should be returned if the input can't be parsed with the input layout",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeFormat},
+				i: "yesterday",
+			},
+			want: want{
+				err: errors.Wrapf(func() error { _, err := time.Parse(time.RFC3339, "yesterday"); return err }(), errFmtTimeParse, "yesterday", time.RFC3339),
+			},
+		},
+		"AddDuration": {
+			reason: "A duration in days should be added to a time",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeAddDuration, Duration: ptr.To("30d")},
+				i: "2023-10-18T12:30:00Z",
+			},
+			want: want{
+				o: "2023-11-17T12:30:00Z",
+			},
+		},
+		"AddNegativeDuration": {
+			reason: "A negative duration should be subtracted from a time",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeAddDuration, Duration: ptr.To("-1w12h")},
+				i: "2023-10-18T12:30:00Z",
+			},
+			want: want{
+				o: "2023-10-11T00:30:00Z",
+			},
+		},
+		"ConvertDuration": {
+			reason: "A duration in days should be converted to seconds",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeConvertDuration, Unit: ptr.To("s")},
+				i: "30d",
+			},
+			want: want{
+				o: int64(2592000),
+			},
+		},
+		"ConvertDurationTruncates": {
+			reason: "A duration should be truncated to a whole number of units",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeConvertDuration, Unit: ptr.To("h")},
+				i: "1h59m",
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ConvertInvalidDuration": {
+			reason: "An error should be returned if the input is not a duration",
+			args: args{
+				t: v1.TimeTransform{Type: v1.TimeTransformTypeConvertDuration, Unit: ptr.To("s")},
+				i: "30 days",
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`invalid duration "30 days"`), errTimeParseDuration),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTime(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("%s\nResolveTime(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nResolveTime(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCollectionResolve(t *testing.T) {
+	type args struct {
+		t v1.CollectionTransform
+		i any
+	}
+	type want struct {
+		o   any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"WrongInputType": {
+			reason: "An error should be returned if the input is not of the type required by the transform",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeJoin},
+				i: "a,b",
+			},
+			want: want{
+				err: errors.Errorf(errFmtCollectionInputType, v1.CollectionTransformTypeJoin, v1.TransformIOTypeArray, "a,b"),
+			},
+		},
+		"Join": {
+			reason: "An array of scalars should be joined with the separator",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeJoin, Separator: ptr.To(", ")},
+				i: []any{"us-east-1a", int64(2), true},
+			},
+			want: want{
+				o: "us-east-1a, 2, true",
+			},
+		},
+		"JoinObject": {
+			reason: "An error should be returned if an element to be joined is not a scalar",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeJoin},
+				i: []any{"a", map[string]any{}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCollectionJoinElement, 1, map[string]any{}),
+			},
+		},
+		"Split": {
+			reason: "A string should be split at the default separator",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeSplit},
+				i: "us-east-1a,us-east-1b",
+			},
+			want: want{
+				o: []any{"us-east-1a", "us-east-1b"},
+			},
+		},
+		"SplitEmpty": {
+			reason: "An empty string should be split into an empty array",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeSplit},
+				i: "",
+			},
+			want: want{
+				o: []any{},
+			},
+		},
+		"Flatten": {
+			reason: "An array of arrays should be flattened by one level",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeFlatten},
+				i: []any{[]any{"a", []any{"b"}}, []any{}, []any{"c"}},
+			},
+			want: want{
+				o: []any{"a", []any{"b"}, "c"},
+			},
+		},
+		"FlattenNotArray": {
+			reason: "An error should be returned if an element to be flattened is not an array",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeFlatten},
+				i: []any{[]any{"a"}, "b"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCollectionNotFlattened, 1),
+			},
+		},
+		"SortStrings": {
+			reason: "An array of strings should be sorted",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeSort},
+				i: []any{"c", "a", "b"},
+			},
+			want: want{
+				o: []any{"a", "b", "c"},
+			},
+		},
+		"SortNumbers": {
+			reason: "An array of integers and floats should be sorted",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeSort},
+				i: []any{int64(3), 1.5, int64(-1)},
+			},
+			want: want{
+				o: []any{int64(-1), 1.5, int64(3)},
+			},
+		},
+		"SortMixed": {
+			reason: "An error should be returned if an array of strings and numbers is sorted",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeSort},
+				i: []any{"a", int64(1)},
+			},
+			want: want{
+				err: errors.New(errCollectionSortMixedTypes),
+			},
+		},
+		"Unique": {
+			reason: "Duplicate elements should be removed, keeping the first of each",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeUnique},
+				i: []any{"b", "a", "b", map[string]any{"a": "b"}, map[string]any{"a": "b"}},
+			},
+			want: want{
+				o: []any{"b", "a", map[string]any{"a": "b"}},
+			},
+		},
+		"Keys": {
+			reason: "The keys of an object should be returned in order",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeKeys},
+				i: map[string]any{"b": int64(2), "a": int64(1)},
+			},
+			want: want{
+				o: []any{"a", "b"},
+			},
+		},
+		"Values": {
+			reason: "The values of an object should be returned ordered by key",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeValues},
+				i: map[string]any{"b": int64(2), "a": int64(1)},
+			},
+			want: want{
+				o: []any{int64(1), int64(2)},
+			},
+		},
+		"Pick": {
+			reason: "Only the supplied fields should be picked",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypePick, Fields: []string{"a", "c"}},
+				i: map[string]any{"a": int64(1), "b": int64(2)},
+			},
+			want: want{
+				o: map[string]any{"a": int64(1)},
+			},
+		},
+		"Omit": {
+			reason: "The supplied fields should be omitted",
+			args: args{
+				t: v1.CollectionTransform{Type: v1.CollectionTransformTypeOmit, Fields: []string{"a"}},
+				i: map[string]any{"a": int64(1), "b": int64(2)},
+			},
+			want: want{
+				o: map[string]any{"b": int64(2)},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveCollection(tc.args.t, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("%s\nResolveCollection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nResolveCollection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,16 +18,23 @@ package composite
 
 import (
 	"context"
+	"reflect"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 )
 
+const (
+	errFmtUnknownToFieldPathPolicy = "unknown toFieldPath policy %q"
+	errFmtAppendNotArray           = "cannot append to %s: not an array"
+)
+
 // mergePath merges the value at the given field path of the src object into
 // the dst object.
 func mergePath(path string, dst, src runtime.Object, mergeOptions *xpv1.MergeOptions) error {
@@ -45,7 +52,7 @@ func mergePath(path string, dst, src runtime.Object, mergeOptions *xpv1.MergeOpt
 		return err
 	}
 
-	return patchFieldValueToObject(path, val, dst, mergeOptions)
+	return patchFieldValueToObject(path, val, dst, &v1.PatchPolicy{MergeOptions: mergeOptions})
 }
 
 // mergeReplace merges the value at path from dst into
@@ -84,18 +91,87 @@ func mergeOptions(pas []v1.Patch) []resource.ApplyOption {
 }
 
 // patchFieldValueToObject applies the value to the "to" object at the given
-// path with the given merge options, returning any errors as they occur.
-// If no merge options is supplied, then destination field is replaced
+// path with the given patch policy, returning any errors as they occur.
+// If no patch policy is supplied, then destination field is replaced
 // with the given value.
-func patchFieldValueToObject(fieldPath string, value any, to runtime.Object, mo *xpv1.MergeOptions) error {
+func patchFieldValueToObject(fieldPath string, value any, to runtime.Object, pp *v1.PatchPolicy) error {
 	paved, err := fieldpath.PaveObject(to)
 	if err != nil {
 		return err
 	}
 
-	if err := paved.MergeValue(fieldPath, value, mo); err != nil {
+	if err := mergeValue(paved, fieldPath, value, pp); err != nil {
 		return err
 	}
 
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
 }
+
+// mergeValue merges the value into the supplied paved object at the given path
+// according to the ToFieldPath policy of the given patch policy. The merge
+// options of the patch policy are used if it has no ToFieldPath policy.
+func mergeValue(paved *fieldpath.Paved, fieldPath string, value any, pp *v1.PatchPolicy) error {
+	if pp == nil || pp.ToFieldPath == nil {
+		var mo *xpv1.MergeOptions
+		if pp != nil {
+			mo = pp.MergeOptions
+		}
+		return paved.MergeValue(fieldPath, value, mo)
+	}
+
+	switch *pp.ToFieldPath {
+	case v1.ToFieldPathPolicyReplace:
+		return paved.SetValue(fieldPath, value)
+	case v1.ToFieldPathPolicyMergeObjects:
+		// Non-nil merge options merge the value into any existing value,
+		// overwriting existing fields and replacing existing arrays.
+		return paved.MergeValue(fieldPath, value, &xpv1.MergeOptions{})
+	case v1.ToFieldPathPolicyAppendArray:
+		return appendValue(paved, fieldPath, value, false)
+	case v1.ToFieldPathPolicyAppendUniqueArray:
+		return appendValue(paved, fieldPath, value, true)
+	}
+	return errors.Errorf(errFmtUnknownToFieldPathPolicy, *pp.ToFieldPath)
+}
+
+// appendValue appends the value to the array at the given path of the supplied
+// paved object. The elements of the value are appended if it is an array. If
+// unique is true only values the array doesn't already contain are appended.
+func appendValue(paved *fieldpath.Paved, fieldPath string, value any, unique bool) error {
+	existing, err := paved.GetValue(fieldPath)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+	var arr []any
+	if existing != nil {
+		a, ok := existing.([]any)
+		if !ok {
+			return errors.Errorf(errFmtAppendNotArray, fieldPath)
+		}
+		arr = append(arr, a...)
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	for _, v := range values {
+		if unique && containsValue(arr, v) {
+			continue
+		}
+		arr = append(arr, v)
+	}
+
+	return paved.SetValue(fieldPath, arr)
+}
+
+// containsValue returns true if the supplied array contains the supplied
+// value.
+func containsValue(arr []any, v any) bool {
+	for _, e := range arr {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
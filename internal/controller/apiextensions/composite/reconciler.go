@@ -23,6 +23,9 @@ import (
 	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -51,6 +54,18 @@ const (
 	timeout             = 2 * time.Minute
 	defaultPollInterval = 1 * time.Minute
 	finalizer           = "composite.apiextensions.crossplane.io"
+
+	// tracerName is the instrumentation name of the OpenTelemetry tracer used
+	// to trace XR reconciles and Composition Function pipelines.
+	tracerName = "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+// OpenTelemetry span attribute keys.
+const (
+	AttributeKeyCompositeKind = "crossplane.composite.kind"
+	AttributeKeyCompositeName = "crossplane.composite.name"
+	AttributeKeyPipelineStep  = "crossplane.pipeline.step"
+	AttributeKeyFunctionName  = "crossplane.function.name"
 )
 
 // Error strings
@@ -186,6 +201,9 @@ type CompositionResult struct {
 	Composed          []ComposedResource
 	ConnectionDetails managed.ConnectionDetails
 	Events            []event.Event
+
+	// Conditions to set on the composite resource.
+	Conditions []xpv1.Condition
 }
 
 // A Composer composes (i.e. creates, updates, or deletes) resources given the
@@ -227,6 +245,14 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithTracerProvider specifies the OpenTelemetry tracer provider the
+// Reconciler should use to trace each reconcile.
+func WithTracerProvider(tp trace.TracerProvider) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.tracer = tp.Tracer(tracerName)
+	}
+}
+
 // WithPollInterval specifies how long the Reconciler should wait before queueing
 // a new reconciliation after a successful reconcile. The Reconciler requeues
 // after a specified duration when it is not actively waiting for an external
@@ -419,6 +445,7 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
+		tracer: noop.NewTracerProvider().Tracer(tracerName),
 
 		pollInterval: defaultPollInterval,
 	}
@@ -446,6 +473,7 @@ type Reconciler struct {
 
 	log    logging.Logger
 	record event.Recorder
+	tracer trace.Tracer
 
 	pollInterval time.Duration
 }
@@ -458,6 +486,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, span := r.tracer.Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String(AttributeKeyCompositeKind, r.gvk.Kind),
+		attribute.String(AttributeKeyCompositeName, req.Name),
+	))
+	defer span.End()
+
 	xr := composite.New(composite.WithGroupVersionKind(r.gvk))
 	if err := r.client.Get(ctx, req.NamespacedName, xr); err != nil {
 		log.Debug(errGet, "error", err)
@@ -624,6 +658,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(xr, event.Normal(reasonPublish, "Successfully published connection details"))
 	}
 
+	xr.SetConditions(res.Conditions...)
+
 	warnings := 0
 	for _, e := range res.Events {
 		if e.Type == event.TypeWarning {
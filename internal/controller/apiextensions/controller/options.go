@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/xfn"
@@ -40,5 +42,9 @@ type Options struct {
 	Registry string
 
 	// FunctionRunner used to run Composition Functions.
-	FunctionRunner *xfn.PackagedFunctionRunner
+	FunctionRunner xfn.FunctionRunner
+
+	// TracerProvider used to trace composite resource reconciles and
+	// Composition Function pipelines.
+	TracerProvider trace.TracerProvider
 }
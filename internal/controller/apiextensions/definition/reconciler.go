@@ -529,6 +529,10 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 		composite.WithPollInterval(co.PollInterval),
 	}
 
+	if co.TracerProvider != nil {
+		o = append(o, composite.WithTracerProvider(co.TracerProvider))
+	}
+
 	// We only want to enable Composition environment support if the relevant
 	// feature flag is enabled. Otherwise we will default to noop selector and
 	// fetcher that will always return nil. All environment features are
@@ -581,10 +585,15 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 	if co.Features.Enabled(features.EnableBetaCompositionFunctions) {
 		ptc := composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher))
 
-		fc := composite.NewFunctionComposer(c, co.FunctionRunner,
+		fo := []composite.FunctionComposerOption{
 			composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(c, fetcher)),
 			composite.WithCompositeConnectionDetailsFetcher(fetcher),
-		)
+			composite.WithFunctionComposerLogger(l),
+		}
+		if co.TracerProvider != nil {
+			fo = append(fo, composite.WithFunctionComposerTracerProvider(co.TracerProvider))
+		}
+		fc := composite.NewFunctionComposer(c, co.FunctionRunner, fo...)
 
 		// Note that if external secret stores are enabled this will supersede
 		// the WithComposer option specified in that block.
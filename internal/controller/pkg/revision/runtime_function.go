@@ -19,6 +19,7 @@ package revision
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -37,6 +38,10 @@ import (
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
+// defaultWebAssemblyModule is the default path of a WebAssembly Function's
+// module within its package image.
+const defaultWebAssemblyModule = "function.wasm"
+
 const (
 	errNotFunction                            = "not a function package"
 	errDeleteFunctionDeployment               = "cannot delete function package deployment"
@@ -65,13 +70,25 @@ func NewFunctionHooks(client client.Client) *FunctionHooks {
 }
 
 // Pre performs operations meant to happen before establishing objects.
-func (h *FunctionHooks) Pre(ctx context.Context, _ runtime.Object, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+func (h *FunctionHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
 	// TODO(ezgidemirel): update any status fields relevant to package revisions.
 
 	if pr.GetDesiredState() != v1.PackageRevisionActive {
 		return nil
 	}
 
+	// WebAssembly Functions run inside the Crossplane pod, so they don't need
+	// a Service or TLS certificates. Crossplane fetches the module from the
+	// package image when it first runs the Function.
+	if module, ok := webAssemblyModule(pkg); ok {
+		// N.B.: We expect the revision to be applied by the caller
+		fRev := pr.(*v1beta1.FunctionRevision)
+		fRev.Status.Runtime = v1beta1.FunctionRuntimeTypeWebAssembly
+		fRev.Status.Module = module
+		fRev.Status.Endpoint = ""
+		return nil
+	}
+
 	// Ensure Prerequisites
 	// Note(turkenh): We need certificates have generated when we get to the
 	// establish step, i.e. we want to inject the CA to CRDs (webhook caBundle).
@@ -97,6 +114,7 @@ func (h *FunctionHooks) Pre(ctx context.Context, _ runtime.Object, pr v1.Package
 
 	if err := initializer.NewTLSCertificateGenerator(secServer.Namespace, initializer.RootCACertSecretName,
 		initializer.TLSCertificateGeneratorWithServerSecretName(secServer.GetName(), initializer.DNSNamesForService(svc.Name, svc.Namespace)),
+		initializer.TLSCertificateGeneratorWithServerURIs([]*url.URL{initializer.FunctionIdentity(pr.GetLabels()[v1.LabelParentPackage])}),
 		initializer.TLSCertificateGeneratorWithOwner([]metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(pr, pr.GetObjectKind().GroupVersionKind()))})).Run(ctx, h.client); err != nil {
 		return errors.Wrapf(err, "cannot generate TLS certificates for %q", pr.GetLabels()[v1.LabelParentPackage])
 	}
@@ -115,6 +133,11 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		return nil
 	}
 
+	// WebAssembly Functions don't need a Deployment.
+	if _, ok := webAssemblyModule(functionMeta); ok {
+		return nil
+	}
+
 	sa := build.ServiceAccount()
 	d := build.Deployment(sa.Name, functionDeploymentOverrides(functionMeta, pr)...)
 	// Create/Apply the SA only if the deployment references it.
@@ -165,6 +188,21 @@ func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWith
 	return nil
 }
 
+// webAssemblyModule returns the path of the supplied Function package's
+// WebAssembly module within its image, and true if the package uses the
+// WebAssembly runtime.
+func webAssemblyModule(pkg runtime.Object) (string, bool) {
+	po, _ := xpkg.TryConvert(pkg, &pkgmetav1beta1.Function{})
+	functionMeta, ok := po.(*pkgmetav1beta1.Function)
+	if !ok || functionMeta.Spec.Runtime == nil || functionMeta.Spec.Runtime.Type != pkgmetav1beta1.FunctionRuntimeTypeWebAssembly {
+		return "", false
+	}
+	if functionMeta.Spec.Runtime.Module == nil || *functionMeta.Spec.Runtime.Module == "" {
+		return defaultWebAssemblyModule, true
+	}
+	return *functionMeta.Spec.Runtime.Module, true
+}
+
 func functionDeploymentOverrides(functionMeta *pkgmetav1beta1.Function, pr v1.PackageRevisionWithRuntime) []DeploymentOverride {
 	do := []DeploymentOverride{
 		DeploymentRuntimeWithAdditionalPorts([]corev1.ContainerPort{
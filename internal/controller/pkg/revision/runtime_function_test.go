@@ -56,6 +56,38 @@ func TestFunctionPreHook(t *testing.T) {
 		args   args
 		want   want
 	}{
+		"WebAssembly": {
+			reason: "We should record the module of a WebAssembly function, and not create a Service or TLS Secret.",
+			args: args{
+				pkg: &pkgmetav1beta1.Function{
+					Spec: pkgmetav1beta1.FunctionSpec{
+						Runtime: &pkgmetav1beta1.FunctionRuntime{
+							Type: pkgmetav1beta1.FunctionRuntimeTypeWebAssembly,
+						},
+					},
+				},
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+					Status: v1beta1.FunctionRevisionStatus{
+						Runtime: v1beta1.FunctionRuntimeTypeWebAssembly,
+						Module:  defaultWebAssemblyModule,
+					},
+				},
+			},
+		},
 		"Success": {
 			reason: "Successful run of pre hook.",
 			args: args{
@@ -175,6 +207,35 @@ func TestFunctionPostHook(t *testing.T) {
 				},
 			},
 		},
+		"WebAssembly": {
+			reason: "Should not create a Deployment for a WebAssembly function.",
+			args: args{
+				pkg: &pkgmetav1beta1.Function{
+					Spec: pkgmetav1beta1.FunctionSpec{
+						Runtime: &pkgmetav1beta1.FunctionRuntime{
+							Type:   pkgmetav1beta1.FunctionRuntimeTypeWebAssembly,
+							Module: ptr.To("cool.wasm"),
+						},
+					},
+				},
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				rev: &v1beta1.FunctionRevision{
+					Spec: v1beta1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+		},
 		"ErrApplySA": {
 			reason: "Should return error if we fail to apply service account for active function revision.",
 			args: args{
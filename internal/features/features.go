@@ -40,6 +40,16 @@ const (
 	// compositions, i.e. watching MRs and reconciling compositions immediately
 	// when any MR is updated.
 	EnableRealtimeCompositions feature.Flag = "EnableRealtimeCompositions"
+
+	// EnableAlphaWebAssemblyFunctions enables alpha support for Composition
+	// Functions that are packaged as WebAssembly modules, and run inside the
+	// Crossplane pod.
+	EnableAlphaWebAssemblyFunctions feature.Flag = "EnableAlphaWebAssemblyFunctions"
+
+	// EnableAlphaFunctionIdentityVerification enables alpha support for
+	// verifying that a Composition Function's server certificate has the
+	// Function's identity (i.e. its SPIFFE ID) before calling it.
+	EnableAlphaFunctionIdentityVerification feature.Flag = "EnableAlphaFunctionIdentityVerification"
 )
 
 // Beta Feature Flags
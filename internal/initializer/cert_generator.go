@@ -23,6 +23,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"net/url"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
@@ -31,6 +32,10 @@ const (
 	errGenerateCertificate = "cannot generate tls certificate"
 )
 
+// SPIFFETrustDomain is the SPIFFE trust domain of the identities Crossplane
+// issues to itself and to Functions.
+const SPIFFETrustDomain = "crossplane.io"
+
 // CertificateSigner is the parent's certificate and key that will be used to sign the certificate
 type CertificateSigner struct {
 	certificate    *x509.Certificate
@@ -53,6 +58,20 @@ var (
 	}
 )
 
+// CrossplaneIdentity returns the SPIFFE ID of Crossplane. It's included as a URI
+// SAN in Crossplane's client certificate, so that Functions can verify that
+// they're being called by Crossplane.
+func CrossplaneIdentity() *url.URL {
+	return &url.URL{Scheme: "spiffe", Host: SPIFFETrustDomain, Path: "/crossplane"}
+}
+
+// FunctionIdentity returns the SPIFFE ID of the named Function. It's included
+// as a URI SAN in the Function's certificate, so that Crossplane can verify it
+// is calling the Function it expects.
+func FunctionIdentity(name string) *url.URL {
+	return &url.URL{Scheme: "spiffe", Host: SPIFFETrustDomain, Path: "/function/" + name}
+}
+
 // NewCertGenerator returns a new CertGenerator.
 func NewCertGenerator() *CertGenerator {
 	return &CertGenerator{}
@@ -21,6 +21,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"math/big"
+	"net/url"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -65,8 +66,10 @@ type TLSCertificateGenerator struct {
 	caSecretName        string
 	tlsServerSecretName *string
 	tlsServerDNSNames   []string
+	tlsServerURIs       []*url.URL
 	tlsClientSecretName *string
 	tlsClientDNSNames   []string
+	tlsClientURIs       []*url.URL
 	owner               []metav1.OwnerReference
 	certificate         CertificateGenerator
 	log                 logging.Logger
@@ -105,6 +108,24 @@ func TLSCertificateGeneratorWithClientSecretName(s string, subjects []string) TL
 	}
 }
 
+// TLSCertificateGeneratorWithServerURIs returns an TLSCertificateGeneratorOption
+// that sets the URI SANs (e.g. SPIFFE IDs) of the server certificate. An
+// existing server certificate without these URIs is regenerated.
+func TLSCertificateGeneratorWithServerURIs(uris []*url.URL) TLSCertificateGeneratorOption {
+	return func(g *TLSCertificateGenerator) {
+		g.tlsServerURIs = uris
+	}
+}
+
+// TLSCertificateGeneratorWithClientURIs returns an TLSCertificateGeneratorOption
+// that sets the URI SANs (e.g. SPIFFE IDs) of the client certificate. An
+// existing client certificate without these URIs is regenerated.
+func TLSCertificateGeneratorWithClientURIs(uris []*url.URL) TLSCertificateGeneratorOption {
+	return func(g *TLSCertificateGenerator) {
+		g.tlsClientURIs = uris
+	}
+}
+
 // NewTLSCertificateGenerator returns a new TLSCertificateGenerator.
 func NewTLSCertificateGenerator(ns, caSecret string, opts ...TLSCertificateGeneratorOption) *TLSCertificateGenerator {
 	e := &TLSCertificateGenerator{
@@ -180,7 +201,7 @@ func (e *TLSCertificateGenerator) ensureClientCertificate(ctx context.Context, k
 	}
 
 	if err == nil {
-		if len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
+		if (len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0) && hasURIs(sec.Data[corev1.TLSCertKey], e.tlsClientURIs) {
 			e.log.Info("TLS secret contains client certificate.", "secret", nn.Name)
 			return nil
 		}
@@ -194,6 +215,7 @@ func (e *TLSCertificateGenerator) ensureClientCertificate(ctx context.Context, k
 		SerialNumber:          big.NewInt(2022),
 		Subject:               pkixName,
 		DNSNames:              dnsNames,
+		URIs:                  e.tlsClientURIs,
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().AddDate(10, 0, 0),
 		IsCA:                  false,
@@ -235,7 +257,7 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 	}
 
 	if err == nil {
-		if len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
+		if (len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0) && hasURIs(sec.Data[corev1.TLSCertKey], e.tlsServerURIs) {
 			e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
 			return nil
 		}
@@ -250,6 +272,7 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 		SerialNumber:          big.NewInt(2022),
 		Subject:               pkixName,
 		DNSNames:              dnsNames,
+		URIs:                  e.tlsServerURIs,
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().AddDate(10, 0, 0),
 		IsCA:                  false,
@@ -345,6 +368,34 @@ func parseCertificateSigner(key, cert []byte) (*CertificateSigner, error) {
 	}, nil
 }
 
+// hasURIs returns true if the supplied PEM encoded certificate has all of the
+// supplied URI SANs. Certificates that can't be parsed don't have any URIs.
+func hasURIs(cert []byte, uris []*url.URL) bool {
+	if len(uris) == 0 {
+		return true
+	}
+
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return false
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	has := make(map[string]bool, len(c.URIs))
+	for _, u := range c.URIs {
+		has[u.String()] = true
+	}
+	for _, u := range uris {
+		if !has[u.String()] {
+			return false
+		}
+	}
+	return true
+}
+
 // DNSNamesForService returns a list of DNS names for a given service name and namespace.
 func DNSNamesForService(service, namespace string) []string {
 	return []string{
@@ -19,6 +19,7 @@ package initializer
 import (
 	"context"
 	"crypto/x509"
+	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -562,6 +563,56 @@ func TestTLSCertificateGeneratorRun(t *testing.T) {
 			},
 			want: want{err: nil},
 		},
+		"OnlyServerCertificateRegenerateServerCertWithoutURIs": {
+			reason: "It should regenerate a server certificate that doesn't have the required URI SANs.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						if key.Name == caCertSecretName && key.Namespace == secretNS {
+							s := &corev1.Secret{
+								Data: map[string][]byte{
+									corev1.TLSCertKey:       []byte(caCert),
+									corev1.TLSPrivateKeyKey: []byte(caKey),
+								},
+							}
+							s.DeepCopyInto(obj.(*corev1.Secret))
+							return nil
+						}
+
+						if key.Name != tlsServerSecretName || key.Namespace != secretNS {
+							return errors.New("unexpected secret name or namespace")
+						}
+
+						// The CA certificate doesn't have any URI SANs.
+						s := &corev1.Secret{
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       []byte(caCert),
+								corev1.TLSPrivateKeyKey: []byte(caKey),
+							},
+						}
+						s.DeepCopyInto(obj.(*corev1.Secret))
+						return nil
+					},
+				},
+				certificate: &MockCertificateGenerator{
+					MockGenerate: func(cert *x509.Certificate, signer *CertificateSigner) ([]byte, []byte, error) {
+						// Return an error to prove we tried to generate a
+						// certificate with the required URI SANs.
+						if len(cert.URIs) == 1 && cert.URIs[0].String() == "spiffe://crossplane.io/function/cool-fn" {
+							return nil, nil, errBoom
+						}
+						return nil, nil, errors.New("unexpected URI SANs")
+					},
+				},
+				opts: []TLSCertificateGeneratorOption{
+					TLSCertificateGeneratorWithServerSecretName(tlsServerSecretName, []string{subject}),
+					TLSCertificateGeneratorWithServerURIs([]*url.URL{FunctionIdentity("cool-fn")}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errGenerateCertificate), errGenerateServerCert),
+			},
+		},
 		"OnlyServerCertificateSuccessfulGeneratedServerCert": {
 			reason: "It should be successful if the server certificate is generated and put into the Secret.",
 			args: args{
@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xcel compiles and evaluates Common Expression Language (CEL)
+// expressions used by Crossplane APIs.
+package xcel
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// CostLimit is the maximum runtime cost of evaluating an expression. It
+	// matches the per expression cost limit of Kubernetes CRD validation rules.
+	CostLimit = 1000000
+
+	// DefaultCacheSize is the default number of compiled programs a Cache
+	// holds.
+	DefaultCacheSize = 1000
+)
+
+const (
+	errCompile        = "cannot compile CEL expression"
+	errProgram        = "cannot create CEL program"
+	errEval           = "cannot evaluate CEL expression"
+	errFmtMapKey      = "CEL map keys must be strings, got %s"
+	errFmtUnsupported = "unsupported CEL result type %s"
+)
+
+// Variables declares the names and types of the variables an expression may
+// reference.
+type Variables map[string]*cel.Type
+
+// NewEnv returns a CEL environment with the supplied variables declared. The
+// environment includes the standard library and the strings, lists, math, sets
+// and encoders extensions.
+func NewEnv(vars Variables) (*cel.Env, error) {
+	opts := []cel.EnvOption{
+		cel.EagerlyValidateDeclarations(true),
+		cel.DefaultUTCTimeZone(true),
+		ext.Strings(ext.StringsVersion(2)),
+		ext.Lists(),
+		ext.Math(),
+		ext.Encoders(),
+		ext.Sets(),
+	}
+	// Sort for a deterministic environment.
+	names := make([]string, 0, len(vars))
+	for n := range vars {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		opts = append(opts, cel.Variable(n, vars[n]))
+	}
+	return cel.NewEnv(opts...)
+}
+
+// Compile parses and type-checks the supplied expression against the supplied
+// variables.
+func Compile(expr string, vars Variables) (*cel.Ast, error) {
+	env, err := NewEnv(vars)
+	if err != nil {
+		return nil, errors.Wrap(err, errCompile)
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, errors.Wrap(iss.Err(), errCompile)
+	}
+	return ast, nil
+}
+
+// A Cache of compiled CEL programs. Expressions are compiled with all their
+// variables declared as dynamically typed, so that a program can be reused
+// regardless of the types of the values it is evaluated against.
+type Cache struct {
+	mu       sync.RWMutex
+	size     int
+	programs map[string]cel.Program
+}
+
+// NewCache returns a Cache that holds at most the supplied number of compiled
+// programs.
+func NewCache(size int) *Cache {
+	return &Cache{size: size, programs: make(map[string]cel.Program)}
+}
+
+// Eval evaluates the supplied expression with the supplied variables bound,
+// compiling it if it is not already cached. The result is returned as a plain
+// Go value - i.e. one of string, bool, int64, float64, nil, []any, or
+// map[string]any.
+func (c *Cache) Eval(expr string, vars map[string]any) (any, error) {
+	prg, err := c.program(expr, vars)
+	if err != nil {
+		return nil, err
+	}
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, errors.Wrap(err, errEval)
+	}
+	return ToNative(out)
+}
+
+func (c *Cache) program(expr string, vars map[string]any) (cel.Program, error) {
+	names := make([]string, 0, len(vars))
+	for n := range vars {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	key := strings.Join(names, ",") + ":" + expr
+
+	c.mu.RLock()
+	prg, ok := c.programs[key]
+	c.mu.RUnlock()
+	if ok {
+		return prg, nil
+	}
+
+	decls := make(Variables, len(names))
+	for _, n := range names {
+		decls[n] = cel.DynType
+	}
+	env, err := NewEnv(decls)
+	if err != nil {
+		return nil, errors.Wrap(err, errCompile)
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, errors.Wrap(iss.Err(), errCompile)
+	}
+	prg, err = env.Program(ast, cel.CostLimit(CostLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, errors.Wrap(err, errProgram)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Evict an arbitrary program to keep the cache bounded. Compositions
+	// rarely contain anywhere near enough expressions for this to matter.
+	if len(c.programs) >= c.size {
+		for k := range c.programs {
+			delete(c.programs, k)
+			break
+		}
+	}
+	c.programs[key] = prg
+	return prg, nil
+}
+
+// ToNative converts the supplied CEL value to a plain Go value, i.e. one that
+// can be set in an unstructured Kubernetes object.
+func ToNative(v ref.Val) (any, error) { //nolint:gocyclo // Just a long switch.
+	switch v.Type() {
+	case types.NullType:
+		return nil, nil
+	case types.BoolType, types.StringType, types.IntType, types.DoubleType:
+		return v.Value(), nil
+	case types.UintType:
+		return int64(v.Value().(uint64)), nil //nolint:gosec // Unstructured objects don't support uint64.
+	case types.BytesType:
+		return string(v.Value().([]byte)), nil
+	case types.TimestampType:
+		return v.Value().(time.Time).Format(time.RFC3339Nano), nil
+	case types.DurationType:
+		return v.Value().(time.Duration).String(), nil
+	case types.ListType:
+		l := v.(traits.Lister)
+		out := make([]any, 0)
+		for it := l.Iterator(); it.HasNext() == types.True; {
+			e, err := ToNative(it.Next())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		return out, nil
+	case types.MapType:
+		m := v.(traits.Mapper)
+		out := make(map[string]any)
+		for it := m.Iterator(); it.HasNext() == types.True; {
+			k := it.Next()
+			ks, ok := k.Value().(string)
+			if !ok {
+				return nil, errors.Errorf(errFmtMapKey, k.Type().TypeName())
+			}
+			e, err := ToNative(m.Get(k))
+			if err != nil {
+				return nil, err
+			}
+			out[ks] = e
+		}
+		return out, nil
+	}
+	return nil, errors.Errorf(errFmtUnsupported, v.Type().TypeName())
+}
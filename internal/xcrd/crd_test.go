@@ -442,6 +442,22 @@ func TestForCompositeResource(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -748,6 +764,22 @@ func TestForCompositeResource(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -1012,6 +1044,22 @@ func TestForCompositeResource(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 										},
 									},
@@ -1288,6 +1336,22 @@ func TestForCompositeResource(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -1570,6 +1634,22 @@ func TestForCompositeResource(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -2057,6 +2137,22 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -2341,6 +2437,22 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
 													},
 												},
+												"functionConditionTypes": {
+													Description: "Types of the conditions that were set by Composition Functions.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+												"claimConditionTypes": {
+													Description: "Types of the conditions that were propagated to the claim.",
+													Type:        "array",
+													XListType:   ptr.To("set"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
 											},
 											XValidations: extv1.ValidationRules{
 												{
@@ -2639,6 +2751,22 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
 											},
 										},
+										"functionConditionTypes": {
+											Description: "Types of the conditions that were set by Composition Functions.",
+											Type:        "array",
+											XListType:   ptr.To("set"),
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{Type: "string"},
+											},
+										},
+										"claimConditionTypes": {
+											Description: "Types of the conditions that were propagated to the claim.",
+											Type:        "array",
+											XListType:   ptr.To("set"),
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{Type: "string"},
+											},
+										},
 									},
 								},
 							},
@@ -35,6 +35,19 @@ var CompositionRevisionRef = "compositionRevisionRef"
 // when translating an XRC into an XR and vice-versa.
 var PropagateSpecProps = []string{"compositionRef", "compositionSelector", "compositionUpdatePolicy", "compositionRevisionSelector"}
 
+// Composite resource and claim status fields that record the types of the
+// status conditions that were set by Composition Functions.
+const (
+	// StatusFunctionConditionTypes records the types of the conditions that
+	// Composition Functions set on a composite resource.
+	StatusFunctionConditionTypes = "functionConditionTypes"
+
+	// StatusClaimConditionTypes records the types of the conditions that
+	// should be (or, on a claim, were) propagated from a composite resource
+	// to its claim.
+	StatusClaimConditionTypes = "claimConditionTypes"
+)
+
 // TODO(negz): Add descriptions to schema fields.
 
 // BaseProps is a partial OpenAPIV3Schema for the spec fields that Crossplane
@@ -356,6 +369,22 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 				"lastPublishedTime": {Type: "string", Format: "date-time"},
 			},
 		},
+		StatusFunctionConditionTypes: {
+			Description: "Types of the conditions that were set by Composition Functions.",
+			Type:        "array",
+			XListType:   ptr.To("set"),
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{Type: "string"},
+			},
+		},
+		StatusClaimConditionTypes: {
+			Description: "Types of the conditions that were propagated to the claim.",
+			Type:        "array",
+			XListType:   ptr.To("set"),
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{Type: "string"},
+			},
+		},
 	}
 }
 
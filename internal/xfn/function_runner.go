@@ -24,15 +24,21 @@ import (
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/initializer"
 )
 
 // Error strings
@@ -41,10 +47,13 @@ const (
 	errNoActiveRevisions     = "cannot find an active FunctionRevision (a FunctionRevision with spec.desiredState: Active)"
 	errListFunctions         = "cannot List Functions to determine which gRPC client connections to garbage collect."
 
+	errFmtGetFunction = "cannot get Function %q to determine its run policy"
+
 	errFmtGetClientConn = "cannot get gRPC client connection for Function %q"
 	errFmtRunFunction   = "cannot run Function %q"
 	errFmtEmptyEndpoint = "cannot determine gRPC target: active FunctionRevision %q has an empty status.endpoint"
 	errFmtDialFunction  = "cannot gRPC dial target %q from status.endpoint of active FunctionRevision %q"
+	errFmtFunctionID    = "server certificate does not have the identity %q of Function %q"
 )
 
 // TODO(negz): Should any of these be configurable?
@@ -57,6 +66,14 @@ const (
 
 	dialFunctionTimeout = 10 * time.Second
 	runFunctionTimeout  = 10 * time.Second
+
+	// These are the defaults used when a Function doesn't specify a run
+	// policy. A Function that is briefly unavailable, for example because
+	// its pod is restarting during a rollout, is retried a few times before
+	// we give up and fail the reconcile.
+	runFunctionMaxRetries = 3
+	runFunctionBackoff    = 250 * time.Millisecond
+	runFunctionMaxBackoff = 10 * time.Second
 )
 
 // A PackagedFunctionRunner runs a Function by making a gRPC call to a Function
@@ -67,7 +84,10 @@ const (
 type PackagedFunctionRunner struct {
 	client       client.Reader
 	creds        credentials.TransportCredentials
+	tls          *tls.Config
+	verifyID     bool
 	interceptors []InterceptorCreator
+	maxMsgSize   int
 
 	connsMx sync.RWMutex
 	conns   map[string]*grpc.ClientConn
@@ -96,10 +116,21 @@ func WithLogger(l logging.Logger) PackagedFunctionRunnerOption {
 // WithTLSConfig configures the client TLS the PackagedFunctionRunner should use.
 func WithTLSConfig(cfg *tls.Config) PackagedFunctionRunnerOption {
 	return func(r *PackagedFunctionRunner) {
+		r.tls = cfg
 		r.creds = credentials.NewTLS(cfg)
 	}
 }
 
+// WithFunctionIdentityVerification configures the PackagedFunctionRunner to
+// verify that each Function's server certificate has the Function's identity
+// (i.e. its SPIFFE ID). This prevents a Function from impersonating another
+// Function. It has no effect unless a TLS config is also supplied.
+func WithFunctionIdentityVerification() PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.verifyID = true
+	}
+}
+
 // WithInterceptorCreators configures the interceptors the
 // PackagedFunctionRunner should create for each function.
 func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOption {
@@ -108,6 +139,16 @@ func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOp
 	}
 }
 
+// WithMaxMessageSize configures the maximum size, in bytes, of the gRPC
+// messages the PackagedFunctionRunner sends to and receives from functions.
+// Large composite resources that compose many resources may need a larger
+// size than gRPC's default of 4MiB.
+func WithMaxMessageSize(bytes int) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.maxMsgSize = bytes
+	}
+}
+
 // NewPackagedFunctionRunner returns a FunctionRunner that runs a Function by
 // making a gRPC call to a Function package's runtime.
 func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOption) *PackagedFunctionRunner {
@@ -133,12 +174,105 @@ func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, r
 		return nil, errors.Wrapf(err, errFmtGetClientConn, name)
 	}
 
+	p, err := r.getRunPolicy(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	log := r.log.WithValues("function", name)
+	backoff := p.backoff
+
+	for attempt := 0; ; attempt++ {
+		rsp, err := runFunctionOnce(withAttempt(ctx, attempt), conn, req, p.timeout)
+		if err == nil || attempt >= p.maxRetries || !retryable(ctx, err) {
+			return rsp, errors.Wrapf(err, errFmtRunFunction, name)
+		}
+
+		log.Debug("Retrying RunFunction after transient error", "attempt", attempt+1, "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrapf(err, errFmtRunFunction, name)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > runFunctionMaxBackoff {
+			backoff = runFunctionMaxBackoff
+		}
+	}
+}
+
+func runFunctionOnce(ctx context.Context, conn *grpc.ClientConn, req *v1beta1.RunFunctionRequest, timeout time.Duration) (*v1beta1.RunFunctionResponse, error) {
 	// This context is used for actually making the request.
-	ctx, cancel := context.WithTimeout(ctx, runFunctionTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	rsp, err := v1beta1.NewFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
-	return rsp, errors.Wrapf(err, errFmtRunFunction, name)
+	return v1beta1.NewFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
+}
+
+// retryable returns true if the supplied error returned by RunFunction is
+// transient, and the request should be retried. We don't retry once the
+// supplied (parent) context is done.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	// A DeadlineExceeded error while the parent context is still alive means
+	// this attempt's timeout expired.
+	switch status.Code(err) { //nolint:exhaustive // Only these codes are transient.
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+type runPolicy struct {
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+}
+
+// getRunPolicy returns the run policy of the named Function, using defaults
+// for anything the Function doesn't specify.
+func (r *PackagedFunctionRunner) getRunPolicy(ctx context.Context, name string) (runPolicy, error) {
+	p := runPolicy{timeout: runFunctionTimeout, maxRetries: runFunctionMaxRetries, backoff: runFunctionBackoff}
+
+	f := &pkgv1beta1.Function{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, f); err != nil {
+		return p, errors.Wrapf(resource.Ignore(kerrors.IsNotFound, err), errFmtGetFunction, name)
+	}
+
+	rp := f.Spec.RunPolicy
+	if rp == nil {
+		return p, nil
+	}
+	if rp.Timeout != nil && rp.Timeout.Duration > 0 {
+		p.timeout = rp.Timeout.Duration
+	}
+	if rp.MaxRetries != nil {
+		p.maxRetries = int(*rp.MaxRetries)
+	}
+	if rp.Backoff != nil && rp.Backoff.Duration > 0 {
+		p.backoff = rp.Backoff.Duration
+	}
+	return p, nil
+}
+
+type attemptKey struct{}
+
+// withAttempt returns a context that records which attempt (starting at zero)
+// to run a Function it's used for. Interceptors may use it to tell retries
+// apart from first attempts.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFrom returns the attempt recorded by withAttempt, or zero.
+func attemptFrom(ctx context.Context) int {
+	a, _ := ctx.Value(attemptKey{}).(int)
+	return a
 }
 
 // In most cases our gRPC target will be a Kubernetes Service. The package
@@ -167,20 +301,9 @@ func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, r
 func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string) (*grpc.ClientConn, error) {
 	log := r.log.WithValues("function", name)
 
-	l := &pkgv1beta1.FunctionRevisionList{}
-	if err := r.client.List(ctx, l, client.MatchingLabels{pkgv1.LabelParentPackage: name}); err != nil {
-		return nil, errors.Wrapf(err, errListFunctionRevisions)
-	}
-
-	var active *pkgv1beta1.FunctionRevision
-	for i := range l.Items {
-		if l.Items[i].GetDesiredState() == pkgv1.PackageRevisionActive {
-			active = &l.Items[i]
-			break
-		}
-	}
-	if active == nil {
-		return nil, errors.New(errNoActiveRevisions)
+	active, err := getActiveRevision(ctx, r.client, name)
+	if err != nil {
+		return nil, err
 	}
 
 	if active.Status.Endpoint == "" {
@@ -213,10 +336,23 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 		is[i] = r.interceptors[i].CreateInterceptor(name, active.Spec.Package)
 	}
 
-	conn, err := grpc.DialContext(ctx, active.Status.Endpoint,
-		grpc.WithTransportCredentials(r.creds),
+	creds := r.creds
+	if r.tls != nil && r.verifyID {
+		cfg := r.tls.Clone()
+		cfg.VerifyConnection = VerifyFunctionIdentity(name)
+		creds = credentials.NewTLS(cfg)
+	}
+
+	do := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
 		grpc.WithDefaultServiceConfig(lbRoundRobin),
-		grpc.WithChainUnaryInterceptor(is...))
+		grpc.WithChainUnaryInterceptor(is...),
+	}
+	if r.maxMsgSize > 0 {
+		do = append(do, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(r.maxMsgSize), grpc.MaxCallSendMsgSize(r.maxMsgSize)))
+	}
+
+	conn, err = grpc.DialContext(ctx, active.Status.Endpoint, do...)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtDialFunction, active.Status.Endpoint, active.GetName())
 	}
@@ -229,6 +365,39 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 	return conn, nil
 }
 
+// VerifyFunctionIdentity returns a function that verifies a TLS connection's
+// peer (i.e. server) certificate has the identity of the named Function. It's
+// intended to be used as the VerifyConnection function of a tls.Config, and
+// thus runs after the peer certificate chain has been verified.
+func VerifyFunctionIdentity(name string) func(tls.ConnectionState) error {
+	id := initializer.FunctionIdentity(name).String()
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) > 0 {
+			for _, u := range cs.PeerCertificates[0].URIs {
+				if u.String() == id {
+					return nil
+				}
+			}
+		}
+		return errors.Errorf(errFmtFunctionID, id, name)
+	}
+}
+
+// getActiveRevision returns the active FunctionRevision of the named Function.
+func getActiveRevision(ctx context.Context, c client.Reader, name string) (*pkgv1beta1.FunctionRevision, error) {
+	l := &pkgv1beta1.FunctionRevisionList{}
+	if err := c.List(ctx, l, client.MatchingLabels{pkgv1.LabelParentPackage: name}); err != nil {
+		return nil, errors.Wrapf(err, errListFunctionRevisions)
+	}
+
+	for i := range l.Items {
+		if l.Items[i].GetDesiredState() == pkgv1.PackageRevisionActive {
+			return &l.Items[i], nil
+		}
+	}
+	return nil, errors.New(errNoActiveRevisions)
+}
+
 // GarbageCollectConnections runs every interval until the supplied context is
 // cancelled. It garbage collects gRPC client connections to Functions that are
 // no longer installed.
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+// Error strings.
+const (
+	errFmtHashRequest = "cannot hash RunFunctionRequest for Function %q"
+)
+
+// DefaultMaxCachedResponses is the default maximum number of RunFunctionResponses
+// a CachingFunctionRunner caches.
+const DefaultMaxCachedResponses = 1000
+
+// A FunctionRunner runs a single Composition Function.
+type FunctionRunner interface {
+	// RunFunction runs the named Composition Function.
+	RunFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error)
+}
+
+// CacheMetrics records hits and misses of a CachingFunctionRunner's response
+// cache.
+type CacheMetrics interface {
+	// CacheHit records that a cached response of the named function was
+	// returned.
+	CacheHit(name string)
+
+	// CacheMiss records that the named function had to be run because no
+	// cached response was found.
+	CacheMiss(name string)
+}
+
+// NopCacheMetrics does nothing.
+type NopCacheMetrics struct{}
+
+// CacheHit does nothing.
+func (m NopCacheMetrics) CacheHit(_ string) {}
+
+// CacheMiss does nothing.
+func (m NopCacheMetrics) CacheMiss(_ string) {}
+
+type cachedResponse struct {
+	rsp     *v1beta1.RunFunctionResponse
+	expires time.Time
+}
+
+// A CachingFunctionRunner wraps a FunctionRunner. It caches the responses of
+// functions that return a response with a TTL (meta.ttl), and returns a cached
+// response instead of running a function again until its TTL expires.
+// Responses are cached by function name and a hash of the request, so a
+// function is run again whenever its request changes.
+type CachingFunctionRunner struct {
+	wrapped FunctionRunner
+	metrics CacheMetrics
+	log     logging.Logger
+
+	// max is the maximum number of cached responses.
+	max int
+
+	mx        sync.Mutex
+	responses map[string]cachedResponse
+
+	now func() time.Time
+}
+
+// A CachingFunctionRunnerOption configures a CachingFunctionRunner.
+type CachingFunctionRunnerOption func(r *CachingFunctionRunner)
+
+// WithCacheLogger configures the logger the CachingFunctionRunner should use.
+func WithCacheLogger(l logging.Logger) CachingFunctionRunnerOption {
+	return func(r *CachingFunctionRunner) {
+		r.log = l
+	}
+}
+
+// WithCacheMetrics configures the metrics the CachingFunctionRunner should
+// record cache hits and misses with.
+func WithCacheMetrics(m CacheMetrics) CachingFunctionRunnerOption {
+	return func(r *CachingFunctionRunner) {
+		r.metrics = m
+	}
+}
+
+// WithMaxCachedResponses configures the maximum number of responses the
+// CachingFunctionRunner should cache. When the cache is full the response that
+// expires soonest is evicted to make room for a new one.
+func WithMaxCachedResponses(n int) CachingFunctionRunnerOption {
+	return func(r *CachingFunctionRunner) {
+		r.max = n
+	}
+}
+
+// NewCachingFunctionRunner returns a FunctionRunner that caches the responses
+// of the supplied FunctionRunner until their TTL expires.
+func NewCachingFunctionRunner(wrapped FunctionRunner, o ...CachingFunctionRunnerOption) *CachingFunctionRunner {
+	r := &CachingFunctionRunner{
+		wrapped:   wrapped,
+		metrics:   NopCacheMetrics{},
+		log:       logging.NewNopLogger(),
+		max:       DefaultMaxCachedResponses,
+		responses: make(map[string]cachedResponse),
+		now:       time.Now,
+	}
+
+	for _, fn := range o {
+		fn(r)
+	}
+
+	return r
+}
+
+// RunFunction returns a cached response of the named Function to the supplied
+// request, if one exists and has not expired. Otherwise it runs the Function,
+// and caches its response if it has a TTL.
+func (r *CachingFunctionRunner) RunFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	key, err := cacheKey(name, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtHashRequest, name)
+	}
+
+	if rsp, ok := r.get(key); ok {
+		r.metrics.CacheHit(name)
+		r.log.Debug("Using cached RunFunctionResponse", "function", name)
+		return rsp, nil
+	}
+	r.metrics.CacheMiss(name)
+
+	rsp, err := r.wrapped.RunFunction(ctx, name, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl := rsp.GetMeta().GetTtl().AsDuration(); ttl > 0 {
+		r.put(key, rsp, ttl)
+	}
+
+	return rsp, nil
+}
+
+func (r *CachingFunctionRunner) get(key string) (*v1beta1.RunFunctionResponse, bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	c, ok := r.responses[key]
+	if !ok {
+		return nil, false
+	}
+	if !r.now().Before(c.expires) {
+		delete(r.responses, key)
+		return nil, false
+	}
+
+	// Callers may mutate the response, so we return a copy.
+	return proto.Clone(c.rsp).(*v1beta1.RunFunctionResponse), true //nolint:forcetypeassert // Cloning a message returns a message of the same type.
+}
+
+func (r *CachingFunctionRunner) put(key string, rsp *v1beta1.RunFunctionResponse, ttl time.Duration) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.max <= 0 {
+		return
+	}
+
+	now := r.now()
+	if _, ok := r.responses[key]; !ok && len(r.responses) >= r.max {
+		r.evict(now)
+	}
+
+	r.responses[key] = cachedResponse{
+		rsp:     proto.Clone(rsp).(*v1beta1.RunFunctionResponse), //nolint:forcetypeassert // Cloning a message returns a message of the same type.
+		expires: now.Add(ttl),
+	}
+}
+
+// evict deletes all expired responses. If none have expired it deletes the
+// response that expires soonest. It must be called with the lock held.
+func (r *CachingFunctionRunner) evict(now time.Time) {
+	soonest := ""
+	for k, c := range r.responses {
+		if !now.Before(c.expires) {
+			delete(r.responses, k)
+			continue
+		}
+		if soonest == "" || c.expires.Before(r.responses[soonest].expires) {
+			soonest = k
+		}
+	}
+	if len(r.responses) >= r.max {
+		delete(r.responses, soonest)
+	}
+}
+
+// cacheKey returns the cache key of the supplied function name and request.
+// Requests are marshalled deterministically, so identical requests (including
+// their map fields) produce identical keys.
+func cacheKey(name string, req *v1beta1.RunFunctionRequest) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return name + "/" + hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+type MockFunctionRunner struct {
+	calls int
+	rsp   *v1beta1.RunFunctionResponse
+	err   error
+}
+
+func (m *MockFunctionRunner) RunFunction(_ context.Context, _ string, _ *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	m.calls++
+	return m.rsp, m.err
+}
+
+type MockCacheMetrics struct {
+	hits   int
+	misses int
+}
+
+func (m *MockCacheMetrics) CacheHit(_ string)  { m.hits++ }
+func (m *MockCacheMetrics) CacheMiss(_ string) { m.misses++ }
+
+func TestCachingRunFunction(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	withTTL := func(ttl time.Duration) *v1beta1.RunFunctionResponse {
+		return &v1beta1.RunFunctionResponse{
+			Meta: &v1beta1.ResponseMeta{Tag: "hi", Ttl: durationpb.New(ttl)},
+		}
+	}
+
+	type call struct {
+		name  string
+		req   *v1beta1.RunFunctionRequest
+		after time.Duration
+	}
+	type params struct {
+		wrapped *MockFunctionRunner
+		o       []CachingFunctionRunnerOption
+	}
+	type want struct {
+		rsp    *v1beta1.RunFunctionResponse
+		err    error
+		calls  int
+		hits   int
+		misses int
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		calls  []call
+		want   want
+	}{
+		"RunFunctionError": {
+			reason: "We should return any error encountered while running the wrapped function, and not cache it.",
+			params: params{
+				wrapped: &MockFunctionRunner{err: errBoom},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				err:    errBoom,
+				calls:  2,
+				misses: 2,
+			},
+		},
+		"NoTTL": {
+			reason: "We should not cache responses without a TTL.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: &v1beta1.RunFunctionResponse{}},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				rsp:    &v1beta1.RunFunctionResponse{},
+				calls:  2,
+				misses: 2,
+			},
+		},
+		"CacheHit": {
+			reason: "We should return a cached response to an identical request until its TTL expires.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "hi"}}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "hi"}}, after: 30 * time.Second},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  1,
+				hits:   1,
+				misses: 1,
+			},
+		},
+		"Expired": {
+			reason: "We should run the function again once the cached response's TTL expires.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}, after: time.Minute},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  2,
+				misses: 2,
+			},
+		},
+		"DifferentRequest": {
+			reason: "We should run the function again if the request changes.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "hi"}}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{Meta: &v1beta1.RequestMeta{Tag: "there"}}},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  2,
+				misses: 2,
+			},
+		},
+		"DifferentFunction": {
+			reason: "We should run a function even if another function cached a response to the same request.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "uncool-function", req: &v1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  2,
+				misses: 2,
+			},
+		},
+		"Evicted": {
+			reason: "We should evict the response that expires soonest when the cache is full.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+				o:       []CachingFunctionRunnerOption{WithMaxCachedResponses(1)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "uncool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  3,
+				misses: 3,
+			},
+		},
+		"Disabled": {
+			reason: "We should not cache responses if the maximum number of cached responses is zero.",
+			params: params{
+				wrapped: &MockFunctionRunner{rsp: withTTL(time.Minute)},
+				o:       []CachingFunctionRunnerOption{WithMaxCachedResponses(0)},
+			},
+			calls: []call{
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+				{name: "cool-function", req: &v1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				rsp:    withTTL(time.Minute),
+				calls:  2,
+				misses: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &MockCacheMetrics{}
+			r := NewCachingFunctionRunner(tc.params.wrapped, append([]CachingFunctionRunnerOption{WithCacheMetrics(m)}, tc.params.o...)...)
+
+			clock := now
+			r.now = func() time.Time { return clock }
+
+			var rsp *v1beta1.RunFunctionResponse
+			var err error
+			for _, c := range tc.calls {
+				clock = clock.Add(c.after)
+				rsp, err = r.RunFunction(context.Background(), c.name, c.req)
+			}
+
+			if diff := cmp.Diff(tc.want.rsp, rsp, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want rsp, +got rsp:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, tc.params.wrapped.calls); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hits, m.hits); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want hits, +got hits:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.misses, m.misses); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want misses, +got misses:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -27,11 +27,16 @@ import (
 )
 
 // Metrics are requests, errors, and duration (RED) metrics for composition
-// function runs.
+// function runs. They also include the hits and misses of the response cache,
+// and the number of retried requests.
 type Metrics struct {
 	requests  *prometheus.CounterVec
 	responses *prometheus.CounterVec
 	duration  *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+
+	cacheHits   *prometheus.CounterVec
+	cacheMisses *prometheus.CounterVec
 }
 
 // NewMetrics creates metrics for composition function runs.
@@ -55,6 +60,24 @@ func NewMetrics() *Metrics {
 			Help:      "Histogram of RunFunctionResponse latency (seconds).",
 			Buckets:   prometheus.DefBuckets,
 		}, []string{"function_name", "function_package", "grpc_target", "grpc_code", "result_severity"}),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "run_function_retries_total",
+			Help:      "Total number of RunFunctionRequests retried after a transient error.",
+		}, []string{"function_name", "function_package", "grpc_target"}),
+
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "run_function_response_cache_hits_total",
+			Help:      "Total number of RunFunctionResponses served from the response cache.",
+		}, []string{"function_name"}),
+
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "run_function_response_cache_misses_total",
+			Help:      "Total number of RunFunctionRequests that could not be served from the response cache.",
+		}, []string{"function_name"}),
 	}
 }
 
@@ -65,6 +88,9 @@ func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
 	m.requests.Describe(ch)
 	m.responses.Describe(ch)
 	m.duration.Describe(ch)
+	m.retries.Describe(ch)
+	m.cacheHits.Describe(ch)
+	m.cacheMisses.Describe(ch)
 }
 
 // Collect is called by the Prometheus registry when collecting
@@ -74,6 +100,20 @@ func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
 	m.requests.Collect(ch)
 	m.responses.Collect(ch)
 	m.duration.Collect(ch)
+	m.retries.Collect(ch)
+	m.cacheHits.Collect(ch)
+	m.cacheMisses.Collect(ch)
+}
+
+// CacheHit records that a cached response of the named function was returned.
+func (m *Metrics) CacheHit(name string) {
+	m.cacheHits.With(prometheus.Labels{"function_name": name}).Inc()
+}
+
+// CacheMiss records that the named function had to be run because no cached
+// response was found.
+func (m *Metrics) CacheMiss(name string) {
+	m.cacheMisses.With(prometheus.Labels{"function_name": name}).Inc()
 }
 
 // CreateInterceptor returns a gRPC UnaryClientInterceptor for the named
@@ -83,6 +123,9 @@ func (m *Metrics) CreateInterceptor(name, pkg string) grpc.UnaryClientIntercepto
 		l := prometheus.Labels{"function_name": name, "function_package": pkg, "grpc_target": cc.Target()}
 
 		m.requests.With(l).Inc()
+		if attemptFrom(ctx) > 0 {
+			m.retries.With(l).Inc()
+		}
 
 		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, opts...)
@@ -17,14 +17,21 @@ package xfn
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +41,7 @@ import (
 	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/initializer"
 )
 
 func TestRunFunction(t *testing.T) {
@@ -42,6 +50,30 @@ func TestRunFunction(t *testing.T) {
 	// Make sure to add servers listeners here, for us to later close.
 	listeners := make([]net.Listener, 0)
 
+	errUnavailable := status.Error(codes.Unavailable, "boom")
+	errInvalid := status.Error(codes.InvalidArgument, "boom")
+
+	// Each of these servers fails a number of times before succeeding.
+	flaky := NewGRPCServer(t, &FlakyFunctionServer{failures: 2, err: errUnavailable, rsp: &v1beta1.RunFunctionResponse{
+		Meta: &v1beta1.ResponseMeta{Tag: "hi!"},
+	}})
+	exhausted := NewGRPCServer(t, &FlakyFunctionServer{failures: 2, err: errUnavailable})
+	invalid := NewGRPCServer(t, &FlakyFunctionServer{failures: 1, err: errInvalid, rsp: &v1beta1.RunFunctionResponse{
+		Meta: &v1beta1.ResponseMeta{Tag: "hi!"},
+	}})
+	listeners = append(listeners, flaky, exhausted, invalid)
+
+	// Retry quickly, to keep our tests fast.
+	withRunPolicy := func(retries int32) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*pkgv1beta1.Function).Spec.RunPolicy = &pkgv1beta1.FunctionRunPolicy{
+				MaxRetries: &retries,
+				Backoff:    &metav1.Duration{Duration: time.Millisecond},
+			}
+			return nil
+		})
+	}
+
 	type params struct {
 		c client.Client
 		o []PackagedFunctionRunnerOption
@@ -134,10 +166,81 @@ func TestRunFunction(t *testing.T) {
 				err: errors.Wrapf(errors.Errorf(errFmtEmptyEndpoint, "cool-fn-revision-a"), errFmtGetClientConn, "cool-fn"),
 			},
 		},
+		"GetFunctionError": {
+			reason: "We should return an error if we can't get the Function to determine its run policy",
+			params: params{
+				c: &test.MockClient{
+					MockGet:  test.NewMockGetFn(errBoom),
+					MockList: NewListFn(Target(flaky)),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &v1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetFunction, "cool-fn"),
+			},
+		},
+		"RetryTransientError": {
+			reason: "We should retry a request that fails with a transient error",
+			params: params{
+				c: &test.MockClient{
+					MockGet:  withRunPolicy(3),
+					MockList: NewListFn(Target(flaky)),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &v1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				rsp: &v1beta1.RunFunctionResponse{
+					Meta: &v1beta1.ResponseMeta{Tag: "hi!"},
+				},
+			},
+		},
+		"RetriesExhausted": {
+			reason: "We should return the last error if a request still fails after the maximum number of retries",
+			params: params{
+				c: &test.MockClient{
+					MockGet:  withRunPolicy(1),
+					MockList: NewListFn(Target(exhausted)),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &v1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(errUnavailable, errFmtRunFunction, "cool-fn"),
+			},
+		},
+		"DontRetryPermanentError": {
+			reason: "We should not retry a request that fails with an error that isn't transient",
+			params: params{
+				c: &test.MockClient{
+					MockGet:  withRunPolicy(3),
+					MockList: NewListFn(Target(invalid)),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &v1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(errInvalid, errFmtRunFunction, "cool-fn"),
+			},
+		},
 		"SuccessfulRequest": {
 			reason: "We should create a new client connection and successfully make a request if no client already exists",
 			params: params{
 				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
 					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
 						// Start a gRPC server.
 						lis := NewGRPCServer(t, &MockFunctionServer{rsp: &v1beta1.RunFunctionResponse{
@@ -371,6 +474,10 @@ func NewListFn(target string) test.MockListFn {
 	})
 }
 
+func Target(lis net.Listener) string {
+	return strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1)
+}
+
 func NewGRPCServer(t *testing.T, ss v1beta1.FunctionRunnerServiceServer) net.Listener {
 	// Listen on a random port.
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
@@ -400,3 +507,93 @@ type MockFunctionServer struct {
 func (s *MockFunctionServer) RunFunction(context.Context, *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
 	return s.rsp, s.err
 }
+
+// A FlakyFunctionServer returns an error the first few times it's called.
+type FlakyFunctionServer struct {
+	v1beta1.UnimplementedFunctionRunnerServiceServer
+
+	mx       sync.Mutex
+	failures int
+
+	rsp *v1beta1.RunFunctionResponse
+	err error
+}
+
+func (s *FlakyFunctionServer) RunFunction(context.Context, *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return nil, s.err
+	}
+	return s.rsp, nil
+}
+
+func TestVerifyFunctionIdentity(t *testing.T) {
+	type args struct {
+		name string
+		cs   tls.ConnectionState
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPeerCertificates": {
+			reason: "We should return an error if the server didn't present a certificate.",
+			args: args{
+				name: "cool-fn",
+			},
+			want: want{
+				err: errors.Errorf(errFmtFunctionID, "spiffe://crossplane.io/function/cool-fn", "cool-fn"),
+			},
+		},
+		"WrongIdentity": {
+			reason: "We should return an error if the server's certificate has the identity of another Function.",
+			args: args{
+				name: "cool-fn",
+				cs: tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+					{URIs: []*url.URL{initializer.FunctionIdentity("evil-fn")}},
+				}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFunctionID, "spiffe://crossplane.io/function/cool-fn", "cool-fn"),
+			},
+		},
+		"IntermediateHasIdentity": {
+			reason: "We should return an error if only a certificate other than the server's leaf certificate has the Function's identity.",
+			args: args{
+				name: "cool-fn",
+				cs: tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+					{},
+					{URIs: []*url.URL{initializer.FunctionIdentity("cool-fn")}},
+				}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtFunctionID, "spiffe://crossplane.io/function/cool-fn", "cool-fn"),
+			},
+		},
+		"CorrectIdentity": {
+			reason: "We should not return an error if the server's certificate has the Function's identity.",
+			args: args{
+				name: "cool-fn",
+				cs: tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+					{URIs: []*url.URL{initializer.CrossplaneIdentity(), initializer.FunctionIdentity("cool-fn")}},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyFunctionIdentity(tc.args.name)(tc.args.cs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerifyFunctionIdentity(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+func TestTracingCreateInterceptor(t *testing.T) {
+	// The connection is never used, so it doesn't matter that nothing is
+	// listening at this target.
+	cc, err := grpc.Dial("dns:///localhost:1234", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	type args struct {
+		ctx context.Context
+		err error
+	}
+	type want struct {
+		name   string
+		status otelcodes.Code
+		attrs  []attribute.KeyValue
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "We should record a client span for a successful request.",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: want{
+				name:   "/apiextensions.fn.proto.v1beta1.FunctionRunnerService/RunFunction",
+				status: otelcodes.Unset,
+				attrs: []attribute.KeyValue{
+					attribute.String("rpc.system", "grpc"),
+					attribute.String("crossplane.function.name", "cool-fn"),
+					attribute.String("crossplane.function.package", "xpkg.upbound.io/cool/fn:v1.0.0"),
+					attribute.String("crossplane.function.grpc_target", "dns:///localhost:1234"),
+					attribute.Int("crossplane.function.attempt", 0),
+					attribute.String("rpc.grpc.status_code", codes.OK.String()),
+				},
+			},
+		},
+		"RetriedError": {
+			reason: "We should record a client span with an error status for a failed, retried request.",
+			args: args{
+				ctx: withAttempt(context.Background(), 2),
+				err: status.Error(codes.Unavailable, "boom"),
+			},
+			want: want{
+				name:   "/apiextensions.fn.proto.v1beta1.FunctionRunnerService/RunFunction",
+				status: otelcodes.Error,
+				attrs: []attribute.KeyValue{
+					attribute.String("rpc.system", "grpc"),
+					attribute.String("crossplane.function.name", "cool-fn"),
+					attribute.String("crossplane.function.package", "xpkg.upbound.io/cool/fn:v1.0.0"),
+					attribute.String("crossplane.function.grpc_target", "dns:///localhost:1234"),
+					attribute.Int("crossplane.function.attempt", 2),
+					attribute.String("rpc.grpc.status_code", codes.Unavailable.String()),
+				},
+				err: status.Error(codes.Unavailable, "boom"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			exp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+			// We should propagate the span context to the Function using
+			// gRPC metadata.
+			var traceparent []string
+			invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				traceparent = md.Get("traceparent")
+				return tc.args.err
+			}
+
+			i := NewTracing(tp, propagation.TraceContext{}).CreateInterceptor("cool-fn", "xpkg.upbound.io/cool/fn:v1.0.0")
+			err := i(tc.args.ctx, "/apiextensions.fn.proto.v1beta1.FunctionRunnerService/RunFunction", &v1beta1.RunFunctionRequest{}, &v1beta1.RunFunctionResponse{}, cc, invoker)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			spans := exp.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("\n%s\nCreateInterceptor(...): want 1 span, got %d", tc.reason, len(spans))
+			}
+			s := spans[0]
+
+			if diff := cmp.Diff(tc.want.name, s.Name); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want span name, +got span name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(trace.SpanKindClient, s.SpanKind); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want span kind, +got span kind:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, s.Status.Code); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want span status, +got span status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.attrs, s.Attributes, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want span attributes, +got span attributes:\n%s", tc.reason, diff)
+			}
+
+			want := []string{"00-" + s.SpanContext.TraceID().String() + "-" + s.SpanContext.SpanID().String() + "-01"}
+			if diff := cmp.Diff(want, traceparent); diff != "" {
+				t.Errorf("\n%s\nCreateInterceptor(...): -want traceparent, +got traceparent:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
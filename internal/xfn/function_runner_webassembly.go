@@ -0,0 +1,432 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// Error strings.
+const (
+	errMarshalRequest    = "cannot marshal RunFunctionRequest"
+	errUnmarshalResponse = "cannot unmarshal RunFunctionResponse written to stdout"
+	errParsePackageRef   = "cannot parse package reference"
+	errFetchPackageImage = "cannot fetch package image"
+	errReadPackageImage  = "cannot read package image filesystem"
+
+	errFmtSelectRuntime      = "cannot determine runtime of Function %q"
+	errFmtNotWebAssembly     = "active FunctionRevision %q does not use the WebAssembly runtime"
+	errFmtGetModule          = "cannot get WebAssembly module for Function %q"
+	errFmtFetchModule        = "cannot fetch WebAssembly module %q of active FunctionRevision %q"
+	errFmtCompileModule      = "cannot compile WebAssembly module of active FunctionRevision %q"
+	errFmtRunModule          = "cannot run WebAssembly module of Function %q"
+	errFmtModuleNotFound     = "cannot find WebAssembly module %q in package image"
+	errFmtModuleTooLarge     = "WebAssembly module %q is larger than the maximum of %d bytes"
+	errFmtModuleOutputTooBig = "WebAssembly module wrote more than the maximum of %d bytes to stdout"
+)
+
+const (
+	// DefaultWASMMemoryLimit is the default maximum amount of memory, in
+	// bytes, a WebAssembly Function may use while running.
+	DefaultWASMMemoryLimit = 64 << 20
+
+	// MaxWASMMemoryLimit is the maximum amount of memory, in bytes, a
+	// WebAssembly Function may use while running. A 32-bit WebAssembly module
+	// can't address more than 4GiB of memory.
+	MaxWASMMemoryLimit = 4 << 30
+
+	// DefaultWASMTimeout is the default maximum amount of time a WebAssembly
+	// Function may run for.
+	DefaultWASMTimeout = 10 * time.Second
+
+	// WebAssembly memory is allocated in 64KiB pages.
+	wasmPageSize = 64 << 10
+
+	// wasmMaxPages is the number of pages in MaxWASMMemoryLimit.
+	wasmMaxPages = MaxWASMMemoryLimit / wasmPageSize
+
+	// maxModuleSize is the maximum size of a WebAssembly module.
+	maxModuleSize = 100 << 20
+
+	// maxResponseSize is the maximum size of a RunFunctionResponse a
+	// WebAssembly Function may write to stdout.
+	maxResponseSize = 32 << 20
+)
+
+// A ModuleFetcher fetches the WebAssembly module of a FunctionRevision.
+type ModuleFetcher interface {
+	// FetchModule fetches the WebAssembly module of the supplied
+	// FunctionRevision.
+	FetchModule(ctx context.Context, rev *pkgv1beta1.FunctionRevision) ([]byte, error)
+}
+
+// A ModuleFetcherFn fetches the WebAssembly module of a FunctionRevision.
+type ModuleFetcherFn func(ctx context.Context, rev *pkgv1beta1.FunctionRevision) ([]byte, error)
+
+// FetchModule fetches the WebAssembly module of the supplied FunctionRevision.
+func (fn ModuleFetcherFn) FetchModule(ctx context.Context, rev *pkgv1beta1.FunctionRevision) ([]byte, error) {
+	return fn(ctx, rev)
+}
+
+// An ImageModuleFetcher fetches a FunctionRevision's WebAssembly module from
+// its package image.
+type ImageModuleFetcher struct {
+	fetcher  xpkg.Fetcher
+	registry string
+}
+
+// NewImageModuleFetcher returns a ModuleFetcher that fetches a
+// FunctionRevision's WebAssembly module from its package image. Package
+// references that don't specify a registry use the supplied default registry.
+func NewImageModuleFetcher(f xpkg.Fetcher, registry string) *ImageModuleFetcher {
+	return &ImageModuleFetcher{fetcher: f, registry: registry}
+}
+
+// FetchModule fetches the supplied FunctionRevision's WebAssembly module, which
+// is read from the path specified by its status.module.
+func (f *ImageModuleFetcher) FetchModule(ctx context.Context, rev *pkgv1beta1.FunctionRevision) ([]byte, error) {
+	ref, err := name.ParseReference(rev.GetSource(), name.WithDefaultRegistry(f.registry))
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePackageRef)
+	}
+
+	img, err := f.fetcher.Fetch(ctx, ref, pkgv1.RefNames(rev.GetPackagePullSecrets())...)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchPackageImage)
+	}
+
+	// Extract returns the image's flattened filesystem as a tarball.
+	rc := mutate.Extract(img)
+	defer rc.Close() //nolint:errcheck // Only reading.
+
+	path := strings.TrimPrefix(rev.Status.Module, "/")
+	t := tar.NewReader(rc)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.Errorf(errFmtModuleNotFound, rev.Status.Module)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadPackageImage)
+		}
+		if strings.TrimPrefix(h.Name, "/") != path {
+			continue
+		}
+		b, err := io.ReadAll(io.LimitReader(t, maxModuleSize+1))
+		if err != nil {
+			return nil, errors.Wrap(err, errReadPackageImage)
+		}
+		if len(b) > maxModuleSize {
+			return nil, errors.Errorf(errFmtModuleTooLarge, rev.Status.Module, maxModuleSize)
+		}
+		return b, nil
+	}
+}
+
+type compiledModule struct {
+	// revision identifies the FunctionRevision the module was compiled from.
+	revision string
+	module   wazero.CompiledModule
+
+	// refs is the number of callers currently using the module. A stale
+	// module is one that has been replaced. It's closed once no callers are
+	// using it. Both are guarded by the WASMFunctionRunner's mutex.
+	refs  int
+	stale bool
+}
+
+// A WASMFunctionRunner runs Functions that are packaged as WebAssembly
+// modules inside the Crossplane process, using a pure Go WebAssembly runtime.
+//
+// A module must be a WASI command. Crossplane writes a protobuf encoded
+// RunFunctionRequest to its stdin, and expects it to write a protobuf encoded
+// RunFunctionResponse to its stdout before exiting. Anything the module writes
+// to stderr is logged. Each call runs in a new module instance, and is limited
+// in the amount of memory it may use and the amount of time it may run for.
+type WASMFunctionRunner struct {
+	client  client.Reader
+	modules ModuleFetcher
+	log     logging.Logger
+
+	memory  uint64
+	timeout time.Duration
+
+	runtime wazero.Runtime
+
+	mx       sync.Mutex
+	compiled map[string]*compiledModule
+}
+
+// A WASMFunctionRunnerOption configures a WASMFunctionRunner.
+type WASMFunctionRunnerOption func(r *WASMFunctionRunner)
+
+// WithWASMLogger configures the logger the WASMFunctionRunner should use.
+func WithWASMLogger(l logging.Logger) WASMFunctionRunnerOption {
+	return func(r *WASMFunctionRunner) {
+		r.log = l
+	}
+}
+
+// WithWASMMemoryLimit configures the maximum amount of memory, in bytes, a
+// WebAssembly Function may use while running. The limit is rounded down to a
+// whole number of 64KiB WebAssembly memory pages, and capped at
+// MaxWASMMemoryLimit.
+func WithWASMMemoryLimit(bytes uint64) WASMFunctionRunnerOption {
+	return func(r *WASMFunctionRunner) {
+		r.memory = bytes
+	}
+}
+
+// WithWASMTimeout configures the maximum amount of time a WebAssembly Function
+// may run for.
+func WithWASMTimeout(t time.Duration) WASMFunctionRunnerOption {
+	return func(r *WASMFunctionRunner) {
+		r.timeout = t
+	}
+}
+
+// NewWASMFunctionRunner returns a FunctionRunner that runs Functions that are
+// packaged as WebAssembly modules. You must call Close to release the
+// resources used by the underlying WebAssembly runtime.
+func NewWASMFunctionRunner(c client.Reader, f ModuleFetcher, o ...WASMFunctionRunnerOption) *WASMFunctionRunner {
+	r := &WASMFunctionRunner{
+		client:   c,
+		modules:  f,
+		log:      logging.NewNopLogger(),
+		memory:   DefaultWASMMemoryLimit,
+		timeout:  DefaultWASMTimeout,
+		compiled: make(map[string]*compiledModule),
+	}
+
+	for _, fn := range o {
+		fn(r)
+	}
+
+	pages := r.memory / wasmPageSize
+	if pages < 1 {
+		pages = 1
+	}
+	if pages > wasmMaxPages {
+		pages = wasmMaxPages
+	}
+
+	// Closing modules when their context is done lets us enforce a timeout,
+	// even on a module that never yields (e.g. an infinite loop).
+	cfg := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(pages)).
+		WithCloseOnContextDone(true)
+
+	ctx := context.Background()
+	r.runtime = wazero.NewRuntimeWithConfig(ctx, cfg)
+	wasi_snapshot_preview1.MustInstantiate(ctx, r.runtime)
+
+	return r
+}
+
+// RunFunction runs the named Function's WebAssembly module with the supplied
+// RunFunctionRequest. The Function is expected to be an installed
+// Function.pkg.crossplane.io package that uses the WebAssembly runtime.
+func (r *WASMFunctionRunner) RunFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	log := r.log.WithValues("function", name)
+
+	c, err := r.getModule(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetModule, name)
+	}
+	defer r.release(ctx, c)
+
+	in, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalRequest)
+	}
+
+	// This context is used for actually running the module.
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{max: maxResponseSize}
+	stderr := &limitedBuffer{max: maxResponseSize}
+
+	// An empty name lets us run many instances of the same module at once.
+	cfg := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(name).
+		WithStdin(bytes.NewReader(in)).
+		WithStdout(stdout).
+		WithStderr(stderr)
+
+	m, err := r.runtime.InstantiateModule(ctx, c.module, cfg)
+	if m != nil {
+		// Close only returns an error if the module is already closed.
+		_ = m.Close(ctx)
+	}
+	if stderr.Len() > 0 {
+		log.Debug("WebAssembly Function wrote to stderr", "stderr", stderr.String())
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRunModule, name)
+	}
+	if stdout.overflow {
+		return nil, errors.Errorf(errFmtModuleOutputTooBig, maxResponseSize)
+	}
+
+	rsp := &v1beta1.RunFunctionResponse{}
+	if err := proto.Unmarshal(stdout.Bytes(), rsp); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalResponse)
+	}
+	return rsp, nil
+}
+
+// getModule returns the compiled WebAssembly module of the named Function's
+// active FunctionRevision. Modules are fetched and compiled the first time the
+// Function is run, and again whenever its active FunctionRevision changes. The
+// caller must release the returned module once it's done using it.
+func (r *WASMFunctionRunner) getModule(ctx context.Context, name string) (*compiledModule, error) {
+	active, err := getActiveRevision(ctx, r.client, name)
+	if err != nil {
+		return nil, err
+	}
+	if active.Status.Runtime != pkgv1beta1.FunctionRuntimeTypeWebAssembly {
+		return nil, errors.Errorf(errFmtNotWebAssembly, active.GetName())
+	}
+
+	revision := active.GetName() + "/" + active.Status.Module
+
+	r.mx.Lock()
+	if c, ok := r.compiled[name]; ok && c.revision == revision {
+		c.refs++
+		r.mx.Unlock()
+		return c, nil
+	}
+	r.mx.Unlock()
+
+	// We don't hold the lock while we fetch and compile the module, to avoid
+	// blocking other Functions. This means two calls may compile the same
+	// module; the last one wins.
+	b, err := r.modules.FetchModule(ctx, active)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchModule, active.Status.Module, active.GetName())
+	}
+
+	mod, err := r.runtime.CompileModule(ctx, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtCompileModule, active.GetName())
+	}
+
+	c := &compiledModule{revision: revision, module: mod, refs: 1}
+
+	r.mx.Lock()
+	if old, ok := r.compiled[name]; ok {
+		// Callers may still be running the old module, so we only close it
+		// once they've released it.
+		r.log.Debug("Replacing WebAssembly module", "function", name, "old-revision", old.revision, "new-revision", revision)
+		old.stale = true
+		if old.refs == 0 {
+			// Close only returns an error if the module is already closed.
+			_ = old.module.Close(ctx)
+		}
+	}
+	r.compiled[name] = c
+	r.mx.Unlock()
+
+	r.log.Debug("Compiled WebAssembly module", "function", name, "revision", revision)
+	return c, nil
+}
+
+// release the supplied module, closing it if it's stale and no other callers
+// are using it.
+func (r *WASMFunctionRunner) release(ctx context.Context, c *compiledModule) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	c.refs--
+	if c.stale && c.refs == 0 {
+		// Close only returns an error if the module is already closed.
+		_ = c.module.Close(ctx)
+	}
+}
+
+// Close releases all resources used by the WASMFunctionRunner, including any
+// compiled modules.
+func (r *WASMFunctionRunner) Close(ctx context.Context) error {
+	return r.runtime.Close(ctx)
+}
+
+// A limitedBuffer is a bytes.Buffer that silently discards writes once it
+// contains max bytes, and records that it did so.
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		b.overflow = true
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// A RuntimeSelectingFunctionRunner runs each Function using the runtime
+// specified by its package metadata, as recorded in the status of its active
+// FunctionRevision.
+type RuntimeSelectingFunctionRunner struct {
+	client    client.Reader
+	container FunctionRunner
+	wasm      FunctionRunner
+}
+
+// NewRuntimeSelectingFunctionRunner returns a FunctionRunner that runs
+// Functions that use the WebAssembly runtime using the supplied wasm runner,
+// and all other Functions using the supplied container runner.
+func NewRuntimeSelectingFunctionRunner(c client.Reader, container, wasm FunctionRunner) *RuntimeSelectingFunctionRunner {
+	return &RuntimeSelectingFunctionRunner{client: c, container: container, wasm: wasm}
+}
+
+// RunFunction runs the named Function using the runtime specified by its
+// active FunctionRevision.
+func (r *RuntimeSelectingFunctionRunner) RunFunction(ctx context.Context, name string, req *v1beta1.RunFunctionRequest) (*v1beta1.RunFunctionResponse, error) {
+	active, err := getActiveRevision(ctx, r.client, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtSelectRuntime, name)
+	}
+
+	if active.Status.Runtime == pkgv1beta1.FunctionRuntimeTypeWebAssembly {
+		return r.wasm.RunFunction(ctx, name, req)
+	}
+	return r.container.RunFunction(ctx, name, req)
+}
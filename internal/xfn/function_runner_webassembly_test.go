@@ -0,0 +1,433 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+// WASMModule returns a minimal WASI command module. When its _start function
+// is called it writes the supplied stdout to stdout, or loops forever if loop
+// is true. The module requires the supplied number of 64KiB memory pages.
+func WASMModule(stdout []byte, loop bool, pages uint32) []byte {
+	uleb := func(v uint32) []byte {
+		return binary.AppendUvarint(nil, uint64(v))
+	}
+	str := func(s string) []byte {
+		return append(uleb(uint32(len(s))), s...)
+	}
+	vec := func(n int, items ...byte) []byte {
+		return append(uleb(uint32(n)), items...)
+	}
+	section := func(id byte, content []byte) []byte {
+		return append(append([]byte{id}, uleb(uint32(len(content)))...), content...)
+	}
+	cat := func(bs ...[]byte) []byte {
+		out := []byte{}
+		for _, b := range bs {
+			out = append(out, b...)
+		}
+		return out
+	}
+
+	// (i32, i32, i32, i32) -> i32 for fd_write, and () -> () for _start.
+	types := vec(2, cat(
+		[]byte{0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7f},
+		[]byte{0x60, 0x00, 0x00},
+	)...)
+	imports := vec(1, cat(str("wasi_snapshot_preview1"), str("fd_write"), []byte{0x00, 0x00})...)
+	funcs := vec(1, 0x01)
+	memory := vec(1, cat([]byte{0x00}, uleb(pages))...)
+	exports := vec(2, cat(
+		str("_start"), []byte{0x00, 0x01},
+		str("memory"), []byte{0x02, 0x00},
+	)...)
+
+	// fd_write(stdout, iovs=0, iovs_len=1, nwritten=8), or loop { br 0 }.
+	body := []byte{0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, 0x1a, 0x0b}
+	if loop {
+		body = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b}
+	}
+	code := vec(1, cat(uleb(uint32(len(body)+1)), []byte{0x00}, body)...)
+
+	// A single iovec at offset 0 pointing at stdout, which starts at offset 16.
+	mem := make([]byte, 16)
+	binary.LittleEndian.PutUint32(mem[0:4], 16)
+	binary.LittleEndian.PutUint32(mem[4:8], uint32(len(stdout)))
+	mem = append(mem, stdout...)
+	data := vec(1, cat([]byte{0x00, 0x41, 0x00, 0x0b}, uleb(uint32(len(mem))), mem)...)
+
+	return cat(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		section(1, types),
+		section(2, imports),
+		section(3, funcs),
+		section(5, memory),
+		section(7, exports),
+		section(10, code),
+		section(11, data),
+	)
+}
+
+func NewWASMListFn(revision string, rt pkgv1beta1.FunctionRuntimeType) test.MockListFn {
+	return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		l := obj.(*pkgv1beta1.FunctionRevisionList)
+		l.Items = []pkgv1beta1.FunctionRevision{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: revision},
+				Spec: pkgv1beta1.FunctionRevisionSpec{
+					PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+						DesiredState: pkgv1.PackageRevisionActive,
+					},
+				},
+				Status: pkgv1beta1.FunctionRevisionStatus{
+					Runtime: rt,
+					Module:  "function.wasm",
+				},
+			},
+		}
+		return nil
+	})
+}
+
+func TestWASMRunFunction(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rsp := &v1beta1.RunFunctionResponse{Meta: &v1beta1.ResponseMeta{Tag: "hi!"}}
+	out, _ := proto.Marshal(rsp)
+
+	type params struct {
+		c client.Reader
+		f ModuleFetcher
+		o []WASMFunctionRunnerOption
+	}
+	type want struct {
+		rsp *v1beta1.RunFunctionResponse
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return an error if we can't list FunctionRevisions.",
+			params: params{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"NotWebAssembly": {
+			reason: "We should return an error if the active FunctionRevision doesn't use the WebAssembly runtime.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", "")},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"FetchModuleError": {
+			reason: "We should return an error if we can't fetch the module.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"CompileModuleError": {
+			reason: "We should return an error if the module isn't valid WebAssembly.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return []byte("not-wasm"), nil
+				}),
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"MemoryLimitExceeded": {
+			reason: "We should return an error if the module requires more memory than allowed.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return WASMModule(out, false, 2), nil
+				}),
+				o: []WASMFunctionRunnerOption{WithWASMMemoryLimit(wasmPageSize)},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"TimeoutExceeded": {
+			reason: "We should return an error if the module runs for longer than allowed.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return WASMModule(nil, true, 1), nil
+				}),
+				o: []WASMFunctionRunnerOption{WithWASMTimeout(100 * time.Millisecond)},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"InvalidResponse": {
+			reason: "We should return an error if the module doesn't write a valid RunFunctionResponse to stdout.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return WASMModule([]byte{0xff}, false, 1), nil
+				}),
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"Success": {
+			reason: "We should return the RunFunctionResponse the module writes to stdout.",
+			params: params{
+				c: &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+				f: ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+					return WASMModule(out, false, 1), nil
+				}),
+			},
+			want: want{
+				rsp: rsp,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewWASMFunctionRunner(tc.params.c, tc.params.f, tc.params.o...)
+			defer r.Close(context.Background())
+
+			got, err := r.RunFunction(context.Background(), "cool-fn", &v1beta1.RunFunctionRequest{})
+
+			if diff := cmp.Diff(tc.want.rsp, got, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWASMGetModule(t *testing.T) {
+	fetches := 0
+	f := ModuleFetcherFn(func(_ context.Context, _ *pkgv1beta1.FunctionRevision) ([]byte, error) {
+		fetches++
+		return WASMModule(nil, false, 1), nil
+	})
+
+	c := &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)}
+	r := NewWASMFunctionRunner(c, f)
+	defer r.Close(context.Background())
+
+	// We should fetch and compile the module the first time we're called, and
+	// reuse it while the active FunctionRevision doesn't change.
+	t.Run("ReuseCompiledModule", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			m, err := r.getModule(context.Background(), "cool-fn")
+			if err != nil {
+				t.Fatalf("\nr.getModule(...): %s", err)
+			}
+			r.release(context.Background(), m)
+		}
+		if diff := cmp.Diff(1, fetches); diff != "" {
+			t.Errorf("\nr.getModule(...): -want fetches, +got fetches:\n%s", diff)
+		}
+	})
+
+	// Hold a reference to the module of the first FunctionRevision, as if it
+	// were still running.
+	held, err := r.getModule(context.Background(), "cool-fn")
+	if err != nil {
+		t.Fatalf("\nr.getModule(...): %s", err)
+	}
+
+	// We should fetch and compile the module again if the active
+	// FunctionRevision changes.
+	c.MockList = NewWASMListFn("cool-fn-revision-b", pkgv1beta1.FunctionRuntimeTypeWebAssembly)
+	t.Run("ReplaceCompiledModule", func(t *testing.T) {
+		m, err := r.getModule(context.Background(), "cool-fn")
+		if err != nil {
+			t.Fatalf("\nr.getModule(...): %s", err)
+		}
+		r.release(context.Background(), m)
+		if diff := cmp.Diff(2, fetches); diff != "" {
+			t.Errorf("\nr.getModule(...): -want fetches, +got fetches:\n%s", diff)
+		}
+	})
+
+	// We shouldn't close a replaced module until its callers release it.
+	t.Run("KeepReplacedModuleUntilReleased", func(t *testing.T) {
+		cfg := wazero.NewModuleConfig().WithName("")
+		m, err := r.runtime.InstantiateModule(context.Background(), held.module, cfg)
+		if err != nil {
+			t.Errorf("\nr.runtime.InstantiateModule(...): replaced module that is still in use: %s", err)
+		}
+		if m != nil {
+			_ = m.Close(context.Background())
+		}
+
+		r.release(context.Background(), held)
+		if _, err := r.runtime.InstantiateModule(context.Background(), held.module, cfg); err == nil {
+			t.Errorf("\nr.runtime.InstantiateModule(...): want error instantiating replaced module after it was released")
+		}
+	})
+}
+
+func TestImageFetchModule(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	module := WASMModule(nil, false, 1)
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	_ = tw.WriteHeader(&tar.Header{Name: "function.wasm", Mode: 0o644, Size: int64(len(module))})
+	_, _ = tw.Write(module)
+	_ = tw.Close()
+	layer, _ := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	img, _ := mutate.AppendLayers(empty.Image, layer)
+
+	rev := func(module string) *pkgv1beta1.FunctionRevision {
+		return &pkgv1beta1.FunctionRevision{
+			Spec: pkgv1beta1.FunctionRevisionSpec{
+				PackageRevisionSpec: pkgv1.PackageRevisionSpec{Package: "xpkg.upbound.io/cool/fn:v1"},
+			},
+			Status: pkgv1beta1.FunctionRevisionStatus{Module: module},
+		}
+	}
+
+	type want struct {
+		module []byte
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		f      *fake.MockFetcher
+		rev    *pkgv1beta1.FunctionRevision
+		want   want
+	}{
+		"FetchError": {
+			reason: "We should return an error if we can't fetch the package image.",
+			f:      &fake.MockFetcher{MockFetch: fake.NewMockFetchFn(nil, errBoom)},
+			rev:    rev("function.wasm"),
+			want: want{
+				err: errors.Wrap(errBoom, errFetchPackageImage),
+			},
+		},
+		"ModuleNotFound": {
+			reason: "We should return an error if the module isn't in the package image.",
+			f:      &fake.MockFetcher{MockFetch: fake.NewMockFetchFn(img, nil)},
+			rev:    rev("other.wasm"),
+			want: want{
+				err: errors.Errorf(errFmtModuleNotFound, "other.wasm"),
+			},
+		},
+		"Success": {
+			reason: "We should return the module read from the package image.",
+			f:      &fake.MockFetcher{MockFetch: fake.NewMockFetchFn(img, nil)},
+			rev:    rev("/function.wasm"),
+			want: want{
+				module: module,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewImageModuleFetcher(tc.f, "").FetchModule(context.Background(), tc.rev)
+
+			if diff := cmp.Diff(tc.want.module, got); diff != "" {
+				t.Errorf("\n%s\nFetchModule(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetchModule(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRuntimeSelectingRunFunction(t *testing.T) {
+	container := &MockFunctionRunner{rsp: &v1beta1.RunFunctionResponse{Meta: &v1beta1.ResponseMeta{Tag: "container"}}}
+	wasm := &MockFunctionRunner{rsp: &v1beta1.RunFunctionResponse{Meta: &v1beta1.ResponseMeta{Tag: "wasm"}}}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   *v1beta1.RunFunctionResponse
+	}{
+		"Container": {
+			reason: "We should run Functions that don't specify a runtime in a container.",
+			c:      &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", "")},
+			want:   container.rsp,
+		},
+		"WebAssembly": {
+			reason: "We should run Functions that specify the WebAssembly runtime using the WebAssembly runner.",
+			c:      &test.MockClient{MockList: NewWASMListFn("cool-fn-revision-a", pkgv1beta1.FunctionRuntimeTypeWebAssembly)},
+			want:   wasm.rsp,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRuntimeSelectingFunctionRunner(tc.c, container, wasm)
+			got, err := r.RunFunction(context.Background(), "cool-fn", &v1beta1.RunFunctionRequest{})
+			if err != nil {
+				t.Errorf("\n%s\nr.RunFunction(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
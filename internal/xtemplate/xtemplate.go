@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xtemplate parses and executes the Go templates used by Crossplane
+// APIs.
+package xtemplate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// MaxOutputSize is the maximum size in bytes of the output of a template.
+	MaxOutputSize = 1 << 20
+
+	// DefaultCacheSize is the default number of parsed templates a Cache
+	// holds.
+	DefaultCacheSize = 1000
+)
+
+const (
+	errParse          = "cannot parse template"
+	errExecute        = "cannot execute template"
+	errFmtOutputSize  = "template output exceeds %d bytes"
+	errFmtRequired    = "required value is missing: %s"
+	errFmtJoinElement = "cannot join element at index %d of type %T"
+)
+
+// Funcs returns the helper functions available to templates. Unlike those of
+// sprig they're deterministic - there are no helpers that return the current
+// time, random values, or read the environment. Helpers that operate on a
+// value take it as their last argument, so they may be used in pipelines,
+// e.g. {{ .input | trimPrefix "arn:" | upper }}.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"quote":      func(v any) string { return fmt.Sprintf("%q", toString(v)) },
+		"split":      split,
+		"join":       join,
+		"hasKey":     func(m map[string]any, k string) bool { _, ok := m[k]; return ok },
+		"default":    defaultValue,
+		"empty":      empty,
+		"coalesce":   coalesce,
+		"required":   required,
+		"toJson":     toJSON,
+		"b64enc":     func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":     b64dec,
+		"sha256sum":  func(s string) string { h := sha256.Sum256([]byte(s)); return hex.EncodeToString(h[:]) },
+	}
+}
+
+// Parse the supplied template text. Executing the template returns an error
+// if it references a missing map key.
+func Parse(text string) (*template.Template, error) {
+	t, err := template.New("template").Option("missingkey=error").Funcs(Funcs()).Parse(text)
+	return t, errors.Wrap(err, errParse)
+}
+
+// Execute the supplied template with the supplied data, returning its output.
+func Execute(t *template.Template, data any) (string, error) {
+	w := &limitedBuffer{limit: MaxOutputSize}
+	if err := t.Execute(w, data); err != nil {
+		return "", errors.Wrap(err, errExecute)
+	}
+	return w.String(), nil
+}
+
+// A Cache of parsed templates.
+type Cache struct {
+	mu        sync.RWMutex
+	size      int
+	templates map[string]*template.Template
+}
+
+// NewCache returns a Cache that holds at most the supplied number of parsed
+// templates.
+func NewCache(size int) *Cache {
+	return &Cache{size: size, templates: make(map[string]*template.Template)}
+}
+
+// Execute the supplied template text with the supplied data, parsing it if it
+// is not already cached.
+func (c *Cache) Execute(text string, data any) (string, error) {
+	c.mu.RLock()
+	t, ok := c.templates[text]
+	c.mu.RUnlock()
+
+	if !ok {
+		var err error
+		if t, err = Parse(text); err != nil {
+			return "", err
+		}
+		c.mu.Lock()
+		// Evict an arbitrary template to keep the cache bounded.
+		if len(c.templates) >= c.size {
+			for k := range c.templates {
+				delete(c.templates, k)
+				break
+			}
+		}
+		c.templates[text] = t
+		c.mu.Unlock()
+	}
+
+	// A parsed template may be executed in parallel.
+	return Execute(t, data)
+}
+
+// limitedBuffer is a bytes.Buffer that returns an error when more than limit
+// bytes are written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errors.Errorf(errFmtOutputSize, b.limit)
+	}
+	return b.Buffer.Write(p)
+}
+
+func toString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func split(sep, s string) []any {
+	if s == "" {
+		return []any{}
+	}
+	parts := strings.Split(s, sep)
+	out := make([]any, len(parts))
+	for i := range parts {
+		out[i] = parts[i]
+	}
+	return out
+}
+
+func join(sep string, v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return toString(v), nil
+	}
+	elems := make([]string, rv.Len())
+	for i := range elems {
+		e := rv.Index(i).Interface()
+		switch e.(type) {
+		case string, bool, int, int64, float64:
+			elems[i] = toString(e)
+		default:
+			return "", errors.Errorf(errFmtJoinElement, i, e)
+		}
+	}
+	return strings.Join(elems, sep), nil
+}
+
+// empty returns true if the supplied value is nil or its type's zero value,
+// or an empty string, array, or object.
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive // Other kinds are never empty.
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.Pointer, reflect.Interface:
+		return rv.IsZero()
+	}
+	return false
+}
+
+// defaultValue returns v, or d if v is empty.
+func defaultValue(d, v any) any {
+	if empty(v) {
+		return d
+	}
+	return v
+}
+
+func coalesce(v ...any) any {
+	for _, e := range v {
+		if !empty(e) {
+			return e
+		}
+	}
+	return nil
+}
+
+func required(msg string, v any) (any, error) {
+	if empty(v) {
+		return nil, errors.Errorf(errFmtRequired, msg)
+	}
+	return v, nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func b64dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	return string(b), err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xtemplate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCacheExecute(t *testing.T) {
+	data := map[string]any{
+		"name":  "cool",
+		"zones": []any{"a", "b"},
+		"empty": "",
+		"obj":   map[string]any{"a": "b"},
+	}
+
+	type args struct {
+		text string
+		data any
+	}
+	type want struct {
+		out string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"StringHelpers": {
+			reason: "String helpers should take the value they operate on as their last argument.",
+			args: args{
+				text: `{{ .name | upper | replace "COOL" "warm" | trimSuffix "m" | quote }}`,
+				data: data,
+			},
+			want: want{out: `"war"`},
+		},
+		"SplitAndJoin": {
+			reason: "Arrays should be joined and strings split.",
+			args: args{
+				text: `{{ join "," .zones }}/{{ "x;y" | split ";" | join "-" }}`,
+				data: data,
+			},
+			want: want{out: "a,b/x-y"},
+		},
+		"Default": {
+			reason: "Empty values should be defaulted.",
+			args: args{
+				text: `{{ .empty | default "fallback" }} {{ coalesce .empty .name }} {{ hasKey .obj "a" }}`,
+				data: data,
+			},
+			want: want{out: "fallback cool true"},
+		},
+		"Encoding": {
+			reason: "Values should be encoded.",
+			args: args{
+				text: `{{ toJson .obj }} {{ b64enc .name }} {{ b64enc .name | b64dec }}`,
+				data: data,
+			},
+			want: want{out: `{"a":"b"} Y29vbA== cool`},
+		},
+		"Required": {
+			reason: "An error should be returned if a required value is empty.",
+			args: args{
+				text: `{{ required "empty is required" .empty }}`,
+				data: data,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+		"MissingKey": {
+			reason: "An error should be returned if a template references a missing key.",
+			args: args{
+				text: `{{ .nope }}`,
+				data: data,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+		"ParseError": {
+			reason: "An error should be returned if a template can't be parsed.",
+			args: args{
+				text: `{{ .name `,
+				data: data,
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+		"OutputTooLarge": {
+			reason: "An error should be returned if a template's output is too large.",
+			args: args{
+				text: `{{ .big }}{{ .big }}`,
+				data: map[string]any{"big": strings.Repeat("a", MaxOutputSize/2+1)},
+			},
+			want: want{err: cmpopts.AnyError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := NewCache(DefaultCacheSize).Execute(tc.args.text, tc.args.data)
+			if diff := cmp.Diff(tc.want.out, out); diff != "" {
+				t.Errorf("\n%s\nExecute(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExecute(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xtime parses the time layouts and durations used by Crossplane APIs.
+package xtime
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtInvalidDuration = "invalid duration %q"
+	errFmtUnknownUnit     = "unknown duration unit %q"
+)
+
+// Day and Week are the durations of the d and w units. They don't account for
+// daylight saving time or leap seconds.
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// layouts are the named layouts that may be used in place of a Go time layout.
+var layouts = map[string]string{ //nolint:gochecknoglobals // We treat this as a constant.
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// Layout returns the Go time layout for the supplied layout, which may either
+// be the name of one of the layouts of the time package (e.g. RFC3339) or a
+// Go time layout (e.g. 2006-01-02).
+func Layout(l string) string {
+	if layout, ok := layouts[l]; ok {
+		return layout
+	}
+	return l
+}
+
+// durationRE matches a duration string, and componentRE each of its
+// components, e.g. "1w" and "2d" in "1w2d".
+var ( //nolint:gochecknoglobals // We treat these as constants.
+	durationRE  = regexp.MustCompile(`^[+-]?((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h|d|w))+$`)
+	componentRE = regexp.MustCompile(`(\d+(?:\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h|d|w)`)
+)
+
+// ParseDuration parses a duration string, like time.ParseDuration. In addition
+// to the units supported by time.ParseDuration it supports d (days) and w
+// (weeks), e.g. "30d" or "1w2d12h".
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	if !durationRE.MatchString(s) {
+		return 0, errors.Errorf(errFmtInvalidDuration, s)
+	}
+
+	// Rewrite days and weeks as hours, which time.ParseDuration supports.
+	var b strings.Builder
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		b.WriteByte(s[0])
+	}
+	for _, c := range componentRE.FindAllStringSubmatch(s, -1) {
+		switch c[2] {
+		case "d", "w":
+			v, err := strconv.ParseFloat(c[1], 64)
+			if err != nil {
+				return 0, errors.Wrapf(err, errFmtInvalidDuration, s)
+			}
+			hours := v * 24
+			if c[2] == "w" {
+				hours *= 7
+			}
+			b.WriteString(strconv.FormatFloat(hours, 'f', -1, 64) + "h")
+		default:
+			b.WriteString(c[0])
+		}
+	}
+
+	d, err := time.ParseDuration(b.String())
+	if err != nil {
+		return 0, errors.Wrapf(err, errFmtInvalidDuration, s)
+	}
+	return d, nil
+}
+
+// Unit returns the duration of the supplied unit, which must be one of ns, us,
+// ms, s, m, h, d, or w.
+func Unit(u string) (time.Duration, error) {
+	switch u {
+	case "ns":
+		return time.Nanosecond, nil
+	case "us", "µs", "μs":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	case "d":
+		return Day, nil
+	case "w":
+		return Week, nil
+	}
+	return 0, errors.Errorf(errFmtUnknownUnit, u)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseDuration(t *testing.T) {
+	type want struct {
+		d   time.Duration
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      string
+		want   want
+	}{
+		"Zero": {
+			reason: "A zero duration without a unit should be valid.",
+			s:      "0",
+			want:   want{d: 0},
+		},
+		"GoDuration": {
+			reason: "Durations supported by time.ParseDuration should be parsed.",
+			s:      "1h30m15.5s",
+			want:   want{d: time.Hour + 30*time.Minute + 15500*time.Millisecond},
+		},
+		"Days": {
+			reason: "Durations in days should be parsed.",
+			s:      "30d",
+			want:   want{d: 30 * Day},
+		},
+		"FractionalDays": {
+			reason: "Durations in fractional days should be parsed.",
+			s:      "1.5d",
+			want:   want{d: 36 * time.Hour},
+		},
+		"Mixed": {
+			reason: "Durations mixing weeks, days and Go units should be parsed.",
+			s:      "-1w2d12h30m",
+			want:   want{d: -(Week + 2*Day + 12*time.Hour + 30*time.Minute)},
+		},
+		"Invalid": {
+			reason: "Durations with unknown units should be invalid.",
+			s:      "1mo",
+			want:   want{err: cmpopts.AnyError},
+		},
+		"Empty": {
+			reason: "Empty durations should be invalid.",
+			s:      "",
+			want:   want{err: cmpopts.AnyError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := ParseDuration(tc.s)
+			if diff := cmp.Diff(tc.want.d, d); diff != "" {
+				t.Errorf("\n%s\nParseDuration(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseDuration(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
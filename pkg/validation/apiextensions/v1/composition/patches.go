@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -42,6 +43,8 @@ const (
 	errFmtFieldInvalid         = "field '%s' is not valid according to the schema"
 	errFmtIndexAccessWrongType = "trying to access a '%s' by index"
 	errFmtFieldAccessWrongType = "trying to access a field '%s' of object, but schema says parent is of type: '%v'"
+	errFmtWildcardWrongType    = "trying to expand a wildcard, but schema says parent is of type: '%v'"
+	errFmtNotArray             = "field path %q must be an array to be patched with multiple values, but schema says it is of type: '%v'"
 	errUnableToParse           = "cannot parse base"
 )
 
@@ -150,6 +153,10 @@ func (v *Validator) validatePatchWithSchemas(ctx context.Context, comp *v1.Compo
 		return nil
 	}
 
+	if resource.ForEach != nil {
+		compositeCRD = withForEachSchema(compositeCRD)
+	}
+
 	return verrors.WrapFieldError(v.validatePatchWithSchemaInternal(patchValidationCtx{
 		comp:            comp,
 		patch:           patch,
@@ -160,6 +167,35 @@ func (v *Validator) validatePatchWithSchemas(ctx context.Context, comp *v1.Compo
 	}), field.NewPath("spec").Child("resources").Index(resourceNumber).Child("patches").Index(patchNumber))
 }
 
+// withForEachSchema returns a copy of the supplied composite resource CRD
+// whose schemas allow patches of a forEach composed resource template to read
+// from the forEach field path. The type of the element isn't known, so any
+// field under the forEach field path is accepted.
+func withForEachSchema(crd *apiextensions.CustomResourceDefinition) *apiextensions.CustomResourceDefinition {
+	out := crd.DeepCopy()
+	add := func(s *apiextensions.JSONSchemaProps) {
+		if s == nil {
+			return
+		}
+		if s.Properties == nil {
+			s.Properties = map[string]apiextensions.JSONSchemaProps{}
+		}
+		s.Properties[v1.ForEachFieldPath] = apiextensions.JSONSchemaProps{
+			Type:                   string(xpschema.KnownJSONTypeObject),
+			XPreserveUnknownFields: ptr.To(true),
+		}
+	}
+	if out.Spec.Validation != nil {
+		add(out.Spec.Validation.OpenAPIV3Schema)
+	}
+	for i := range out.Spec.Versions {
+		if out.Spec.Versions[i].Schema != nil {
+			add(out.Spec.Versions[i].Schema.OpenAPIV3Schema)
+		}
+	}
+	return out
+}
+
 type patchValidationCtx struct {
 	comp            *v1.Composition
 	patch           v1.Patch
@@ -293,14 +329,40 @@ func validateFromCompositeFieldPathPatch(patch v1.Patch, from, to *apiextensions
 		return "", "", field.Invalid(field.NewPath("fromFieldPath"), fromFieldPath, err.Error())
 	}
 
-	toType, err = validateFieldPath(to, toFieldPath)
+	// The values of a fromFieldPath with wildcards are patched to a
+	// toFieldPath without wildcards as an array, so each value must match the
+	// type of the items of the array.
+	if strings.Contains(fromFieldPath, "[*]") && toFieldPath != "" && !strings.Contains(toFieldPath, "[*]") {
+		toType, err = validateFieldPathItems(to, toFieldPath)
+	} else {
+		toType, err = validateFieldPath(to, toFieldPath)
+	}
 	if err != nil {
 		return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, err.Error())
 	}
 
+	// A value appended to an array must match the type of the items of the
+	// array, unless it's an array whose elements are appended.
+	if isAppendPolicy(patch.Policy) && toType == xpschema.KnownJSONTypeArray && !(fromType == xpschema.KnownJSONTypeArray && len(patch.Transforms) == 0) {
+		if toType, err = validateFieldPathItems(to, toFieldPath); err != nil {
+			return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, err.Error())
+		}
+	}
+
 	return fromType, toType, nil
 }
 
+// isAppendPolicy returns true if the supplied patch policy appends values to
+// the toFieldPath.
+func isAppendPolicy(pp *v1.PatchPolicy) bool {
+	switch pp.GetToFieldPathPolicy() {
+	case v1.ToFieldPathPolicyAppendArray, v1.ToFieldPathPolicyAppendUniqueArray:
+		return true
+	case v1.ToFieldPathPolicyReplace, v1.ToFieldPathPolicyMergeObjects:
+	}
+	return false
+}
+
 func validateIOTypesWithTransforms(transforms []v1.Transform, fromType, toType xpschema.KnownJSONType) *field.Error {
 	// if there are no transforms and the types are either the same or unknown, we don't need to validate transforms
 	if len(transforms) == 0 && (fromType == "" || toType == "" || fromType == toType) {
@@ -337,6 +399,11 @@ func validateTransformsChainIOTypes(transforms []v1.Transform, fromType xpschema
 		if err != nil {
 			return "", field.InternalError(field.NewPath("transforms").Index(i), err)
 		}
+		if transform.Type == v1.TransformTypeMath && transform.Math != nil && inputType != "" {
+			// The output type of some math transforms depends on their input type.
+			o := transform.Math.GetOutputType(inputType)
+			out = &o
+		}
 		if out == nil {
 			// no need to validate the rest of the transforms as a nil output without error means we don't
 			// have a way to know the output type for some transforms
@@ -368,23 +435,61 @@ func validateFieldPath(schema *apiextensions.JSONSchemaProps, fieldPath string)
 	return validateFieldPathSegments(segments, schema, fieldPath)
 }
 
+// validateFieldPathItems validates the given fieldPath is a valid array for
+// the given schema. It returns the type of the items of the array and any
+// error. If the returned type is "", but without error, it means the fieldPath
+// is accepted by the schema, but its items are not defined in it.
+func validateFieldPathItems(schema *apiextensions.JSONSchemaProps, fieldPath string) (xpschema.KnownJSONType, error) {
+	segments, err := fieldpath.Parse(fieldPath)
+	if err != nil {
+		return "", err
+	}
+	if len(segments) > 0 && segments[0].Type == fieldpath.SegmentField && segments[0].Field == "metadata" {
+		schema = defaultMetadataSchema(schema)
+	}
+	current, err := fieldPathSchema(segments, schema)
+	if err != nil || current == nil {
+		return "", err
+	}
+	if current.Type != string(xpschema.KnownJSONTypeArray) {
+		return "", errors.Errorf(errFmtNotArray, fieldPath, current.Type)
+	}
+	if current.Items == nil || current.Items.Schema == nil || current.Items.Schema.Type == "" {
+		return "", nil
+	}
+	if !xpschema.IsValid(current.Items.Schema.Type) {
+		return "", fmt.Errorf("items of field path %q have an unsupported type %q", fieldPath, current.Items.Schema.Type)
+	}
+	return xpschema.KnownJSONType(current.Items.Schema.Type), nil
+}
+
 func validateFieldPathSegments(segments fieldpath.Segments, schema *apiextensions.JSONSchemaProps, fieldPath string) (xpschema.KnownJSONType, error) {
+	current, err := fieldPathSchema(segments, schema)
+	if err != nil || current == nil {
+		return "", err
+	}
+
+	if !xpschema.IsValid(current.Type) {
+		return "", fmt.Errorf("field path %q has an unsupported type %q", fieldPath, current.Type)
+	}
+	return xpschema.KnownJSONType(current.Type), nil
+}
+
+// fieldPathSchema returns the schema of the field the given segments point
+// to, or nil if the schema accepts the field but does not define it.
+func fieldPathSchema(segments fieldpath.Segments, schema *apiextensions.JSONSchemaProps) (*apiextensions.JSONSchemaProps, error) {
 	current := schema
 	for _, segment := range segments {
 		currentSegment, err := validateFieldPathSegment(current, segment)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		if currentSegment == nil {
-			return "", nil
+			return nil, nil
 		}
 		current = currentSegment
 	}
-
-	if !xpschema.IsValid(current.Type) {
-		return "", fmt.Errorf("field path %q has an unsupported type %q", fieldPath, current.Type)
-	}
-	return xpschema.KnownJSONType(current.Type), nil
+	return current, nil
 }
 
 // validateFieldPathSegment validates that the given field path segment is valid for the given schema.
@@ -392,6 +497,9 @@ func validateFieldPathSegments(segments fieldpath.Segments, schema *apiextension
 func validateFieldPathSegment(parent *apiextensions.JSONSchemaProps, segment fieldpath.Segment) (current *apiextensions.JSONSchemaProps, err error) {
 	switch segment.Type {
 	case fieldpath.SegmentField:
+		if segment.Field == "*" {
+			return validateFieldPathSegmentWildcard(parent)
+		}
 		return validateFieldPathSegmentField(parent, segment)
 	case fieldpath.SegmentIndex:
 		return validateFieldPathSegmentIndex(parent, segment)
@@ -432,6 +540,28 @@ func validateFieldPathSegmentField(parent *apiextensions.JSONSchemaProps, segmen
 	return &prop, nil
 }
 
+// validateFieldPathSegmentWildcard validates that a wildcard may be expanded
+// for the given schema. It returns the schema of the array items or object
+// fields the wildcard expands to.
+func validateFieldPathSegmentWildcard(parent *apiextensions.JSONSchemaProps) (*apiextensions.JSONSchemaProps, error) {
+	if parent == nil {
+		return nil, nil
+	}
+	switch parent.Type {
+	case string(xpschema.KnownJSONTypeArray):
+		if parent.Items == nil {
+			return nil, errors.New("no items found in array")
+		}
+		return parent.Items.Schema, nil
+	case string(xpschema.KnownJSONTypeObject), "":
+		if parent.AdditionalProperties != nil {
+			return parent.AdditionalProperties.Schema, nil
+		}
+		return nil, nil
+	}
+	return nil, errors.Errorf(errFmtWildcardWrongType, parent.Type)
+}
+
 func validateFieldPathSegmentIndex(parent *apiextensions.JSONSchemaProps, segment fieldpath.Segment) (*apiextensions.JSONSchemaProps, error) {
 	if parent == nil {
 		return nil, nil
@@ -484,7 +614,7 @@ func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) erro
 			if fromType != v1.TransformIOTypeString {
 				return errors.Errorf("string transform can only be used with string input types, got %s", fromType)
 			}
-		case v1.StringTransformTypeFormat:
+		case v1.StringTransformTypeFormat, v1.StringTransformTypeTemplate:
 			// any input type is valid
 		case v1.StringTransformTypeConvert:
 			if t.String.Convert == nil {
@@ -507,6 +637,24 @@ func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) erro
 		if _, err := composite.GetConversionFunc(t.Convert, fromType); err != nil {
 			return err
 		}
+	case v1.TransformTypeCEL:
+		if t.CEL == nil {
+			return errors.Errorf("cel transform requires configuration")
+		}
+		if _, err := t.CEL.TypeCheck(fromType); err != nil {
+			return err
+		}
+	case v1.TransformTypeTime:
+		if fromType != v1.TransformIOTypeString {
+			return errors.Errorf("time transform can only be used with string input types, got %s", fromType)
+		}
+	case v1.TransformTypeCollection:
+		if t.Collection == nil {
+			return errors.Errorf("collection transform requires configuration")
+		}
+		if in := t.Collection.GetInputType(); fromType != in {
+			return errors.Errorf("collection transform of type %s can only be used with %s input types, got %s", t.Collection.Type, in, fromType)
+		}
 	default:
 		return errors.Errorf("unknown transform type %s", t.Type)
 	}
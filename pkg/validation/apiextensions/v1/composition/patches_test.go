@@ -408,6 +408,34 @@ func TestValidateFieldPath(t *testing.T) {
 									XPreserveUnknownFields: &[]bool{true}[0],
 								}}}}}},
 		},
+		"AcceptWildcardArray": {
+			reason: "Should validate wildcards in arrays properly",
+			want:   want{err: nil, fieldType: "string"},
+			args: args{
+				fieldPath: "spec.subnets[*].id",
+				schema: &apiextensions.JSONSchemaProps{
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"spec": {
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"subnets": {
+									Type: "array",
+									Items: &apiextensions.JSONSchemaPropsOrArray{
+										Schema: &apiextensions.JSONSchemaProps{
+											Type: "object",
+											Properties: map[string]apiextensions.JSONSchemaProps{
+												"id": {Type: "string"}}}}}}}}}},
+		},
+		"RejectWildcardString": {
+			reason: "Should return an error when expanding a wildcard on a string",
+			want:   want{err: xperrors.Errorf(errFmtWildcardWrongType, "string")},
+			args: args{
+				fieldPath: "spec.subnet[*]",
+				schema: &apiextensions.JSONSchemaProps{
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"spec": {
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"subnet": {Type: "string"}}}}}},
+		},
 		"AcceptValidArray": {
 			reason: "Should validate arrays properly",
 			want:   want{err: nil, fieldType: "string"},
@@ -499,6 +527,62 @@ func TestValidateFieldPath(t *testing.T) {
 	}
 }
 
+func TestValidateFieldPathItems(t *testing.T) {
+	subnetIDs := func(t string) *apiextensions.JSONSchemaProps {
+		return &apiextensions.JSONSchemaProps{
+			Properties: map[string]apiextensions.JSONSchemaProps{
+				"spec": {
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"subnetIds": {
+							Type: t,
+							Items: &apiextensions.JSONSchemaPropsOrArray{
+								Schema: &apiextensions.JSONSchemaProps{Type: "string"},
+							}}}}}}
+	}
+	type args struct {
+		schema    *apiextensions.JSONSchemaProps
+		fieldPath string
+	}
+	type want struct {
+		err       error
+		fieldType schema.KnownJSONType
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AcceptArray": {
+			reason: "Should return the type of the items of an array",
+			want:   want{fieldType: "string"},
+			args: args{
+				fieldPath: "spec.subnetIds",
+				schema:    subnetIDs("array"),
+			},
+		},
+		"RejectNotArray": {
+			reason: "Should return an error if the field path is not an array",
+			want:   want{err: xperrors.Errorf(errFmtNotArray, "spec.subnetIds", "string")},
+			args: args{
+				fieldPath: "spec.subnetIds",
+				schema:    subnetIDs("string"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotFieldType, err := validateFieldPathItems(tc.args.schema, tc.args.fieldPath)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateFieldPathItems(...): -want error, +got error: %s\n", tc.reason, diff)
+				return
+			}
+			if diff := cmp.Diff(tc.want.fieldType, gotFieldType); diff != "" {
+				t.Errorf("\n%s\nvalidateFieldPathItems(...): -want, +got: %s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateFieldPathSegmentIndex(t *testing.T) {
 	type args struct {
 		parent  *apiextensions.JSONSchemaProps
@@ -1016,6 +1100,52 @@ func TestIsValidInputForTransform(t *testing.T) {
 				err: true,
 			},
 		},
+		"ValidCELTransformInputArray": {
+			reason: "CEL transform should not return an error if its expression type checks against the input type",
+			args: args{
+				fromType: v1.TransformIOTypeArray,
+				t: &v1.Transform{
+					Type: v1.TransformTypeCEL,
+					CEL:  &v1.CELTransform{Expression: `input.size() > 0`},
+				},
+			},
+		},
+		"InvalidCELTransformInputString": {
+			reason: "CEL transform should return an error if its expression does not type check against the input type",
+			args: args{
+				fromType: v1.TransformIOTypeString,
+				t: &v1.Transform{
+					Type: v1.TransformTypeCEL,
+					CEL:  &v1.CELTransform{Expression: `input * 2`},
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"ValidTimeTransformInputString": {
+			reason: "Time transform should accept string input",
+			args: args{
+				fromType: v1.TransformIOTypeString,
+				t: &v1.Transform{
+					Type: v1.TransformTypeTime,
+					Time: &v1.TimeTransform{Type: v1.TimeTransformTypeConvertDuration, Unit: ptr.To("s")},
+				},
+			},
+		},
+		"InvalidTimeTransformInputInt": {
+			reason: "Time transform should not accept int input",
+			args: args{
+				fromType: v1.TransformIOTypeInt,
+				t: &v1.Transform{
+					Type: v1.TransformTypeTime,
+					Time: &v1.TimeTransform{Type: v1.TimeTransformTypeFormat},
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -109,6 +109,17 @@ func TestValidatorValidate(t *testing.T) {
 				})),
 			},
 		},
+		"AcceptStrictForEachFromFieldPath": {
+			reason: "Should accept a Composition with a forEach resource patching from the forEach field path, which isn't part of the Composite resource's schema",
+			args: args{
+				gkToCRDs: defaultGKToCRDs(),
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeStrict, map[string]any{"someOtherField": "test"}, withForEach(0, &v1.ForEach{FromFieldPath: "spec.someField"}), withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("forEach.element.name"),
+					ToFieldPath:   ptr.To("spec.someOtherField"),
+				})),
+			},
+		},
 		"RejectStrictInvalidToFieldPath": {
 			reason: "Should reject a Composition with a patch using a field not allowed by the schema of the Managed resource, if all CRDs are found",
 			want: want{
@@ -154,6 +165,26 @@ func TestValidatorValidate(t *testing.T) {
 				})),
 			},
 		},
+		"AcceptStrictPatchAppendToArray": {
+			reason: "Should accept a Composition with a patch appending a value to an array of the same type, if all CRDs are found",
+			args: args{
+				gkToCRDs: buildGkToCRDs(
+					defaultCompositeCrdBuilder().build(),
+					defaultManagedCrdBuilder().withOption(func(crd *extv1.CustomResourceDefinition) {
+						crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["someNonRequiredField"] = extv1.JSONSchemaProps{
+							Type:  "array",
+							Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}},
+						}
+					}).build(),
+				),
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeStrict, map[string]any{"someOtherField": "test"}, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someField"),
+					ToFieldPath:   ptr.To("spec.someNonRequiredField"),
+					Policy:        &v1.PatchPolicy{ToFieldPath: ptr.To(v1.ToFieldPathPolicyAppendUniqueArray)},
+				})),
+			},
+		},
 		"RejectStrictPatchMismatchTypeWithMathTransform": {
 			reason: "Should reject a Composition with a math transformation resulting in the wrong final type, if validation mode is strict and all CRDs are found",
 			want: want{
@@ -179,6 +210,81 @@ func TestValidatorValidate(t *testing.T) {
 				})),
 			},
 		},
+		"AcceptStrictPatchIntegerWithMathTransform": {
+			reason: "Should accept a Composition with a math transformation that preserves an integer type, if all CRDs are found",
+			args: args{
+				gkToCRDs: buildGkToCRDs(
+					defaultCompositeCrdBuilder().withOption(func(crd *extv1.CustomResourceDefinition) {
+						crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["someField"] = extv1.JSONSchemaProps{
+							Type: "integer",
+						}
+					}).build(),
+					defaultManagedCrdBuilder().withOption(func(crd *extv1.CustomResourceDefinition) {
+						crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["someNonRequiredField"] = extv1.JSONSchemaProps{
+							Type: "integer",
+						}
+					}).build(),
+				),
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeStrict, map[string]any{"someOtherField": "test"}, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someField"),
+					ToFieldPath:   ptr.To("spec.someNonRequiredField"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeMath,
+						Math: &v1.MathTransform{
+							Type: v1.MathTransformTypeAdd,
+							Add:  ptr.To[int64](1),
+						},
+					}},
+				})),
+			},
+		},
+		"AcceptStrictPatchArrayWithCollectionTransform": {
+			reason: "Should accept a Composition with a collection transformation joining an array into a string, if all CRDs are found",
+			args: args{
+				gkToCRDs: buildGkToCRDs(
+					defaultCompositeCrdBuilder().withOption(func(crd *extv1.CustomResourceDefinition) {
+						crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["someField"] = extv1.JSONSchemaProps{
+							Type:  "array",
+							Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}},
+						}
+					}).build(),
+					defaultManagedCrdBuilder().build(),
+				),
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeStrict, map[string]any{"someOtherField": "test"}, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someField"),
+					ToFieldPath:   ptr.To("spec.someOtherField"),
+					Transforms: []v1.Transform{{
+						Type:       v1.TransformTypeCollection,
+						Collection: &v1.CollectionTransform{Type: v1.CollectionTransformTypeJoin},
+					}},
+				})),
+			},
+		},
+		"RejectStrictPatchObjectWithCollectionTransform": {
+			reason: "Should reject a Composition with a collection transformation joining a string, if all CRDs are found",
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].transforms[0]",
+					},
+				},
+			},
+			args: args{
+				gkToCRDs: defaultGKToCRDs(),
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeStrict, nil, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someField"),
+					ToFieldPath:   ptr.To("spec.someOtherField"),
+					Transforms: []v1.Transform{{
+						Type:       v1.TransformTypeCollection,
+						Collection: &v1.CollectionTransform{Type: v1.CollectionTransformTypeJoin},
+					}},
+				})),
+			},
+		},
 		"RejectStrictPatchMismatchTypeWithConvertTransform": {
 			reason: "Should reject a Composition with a convert transformation resulting in the wrong final type, if all CRDs are found",
 			want: want{
@@ -813,6 +919,12 @@ func withPatches(index int, patches ...v1.Patch) compositionBuilderOption {
 	}
 }
 
+func withForEach(index int, f *v1.ForEach) compositionBuilderOption {
+	return func(c *v1.Composition) {
+		c.Spec.Resources[index].ForEach = f
+	}
+}
+
 func withEnvironmentPatches(patches ...v1.EnvironmentPatch) compositionBuilderOption {
 	return func(c *v1.Composition) {
 		if c.Spec.Environment == nil {
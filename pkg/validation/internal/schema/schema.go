@@ -89,7 +89,7 @@ func FromKnownJSONType(t KnownJSONType) (v1.TransformIOType, error) {
 	case KnownJSONTypeObject:
 		return v1.TransformIOTypeObject, nil
 	case KnownJSONTypeArray:
-		return v1.TransformIOTypeObject, nil
+		return v1.TransformIOTypeArray, nil
 	case KnownJSONTypeNull:
 		return "", errors.Errorf(errFmtUnsupportedJSONType, t)
 	default:
@@ -223,18 +223,22 @@ func TestFromKnownJSONType(t *testing.T) {
 				out: v1.TransformIOTypeBool,
 			},
 		},
-		"InvalidArray": {
-			reason: "Array should not be valid",
-			args:   args{t: KnownJSONTypeArray},
+		"ValidArray": {
+			reason: "Array should be valid and convert properly",
+			args: args{
+				t: KnownJSONTypeArray,
+			},
 			want: want{
-				err: xperrors.Errorf(errFmtUnsupportedJSONType, KnownJSONTypeArray),
+				out: v1.TransformIOTypeArray,
 			},
 		},
-		"InvalidObject": {
-			reason: "Object should not be valid",
-			args:   args{t: KnownJSONTypeObject},
+		"ValidObject": {
+			reason: "Object should be valid and convert properly",
+			args: args{
+				t: KnownJSONTypeObject,
+			},
 			want: want{
-				err: xperrors.Errorf(errFmtUnsupportedJSONType, KnownJSONTypeObject),
+				out: v1.TransformIOTypeObject,
 			},
 		},
 	}
@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/objectmeta"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	xperrors "github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtNoCRD           = "no CustomResourceDefinition found for %s - skipping validation"
+	errFmtNoSchema        = "CustomResourceDefinition for %s has no schema - skipping validation"
+	errFmtStructural      = "cannot build structural schema for %s"
+	errFmtSchemaValidator = "cannot build schema validator for %s"
+	errUnknownField       = "field not declared in schema"
+	errFmtCELRules        = "CustomResourceDefinition for %s has x-kubernetes-validations rules - skipping CEL validation"
+)
+
+// Validator validates arbitrary resources against the schemas of their
+// CustomResourceDefinitions.
+type Validator struct {
+	crdGetter CRDGetter
+}
+
+// CRDGetter is used to get the CRD of a resource.
+type CRDGetter interface {
+	Get(ctx context.Context, gk schema.GroupKind) (*apiextensions.CustomResourceDefinition, error)
+}
+
+// ValidatorOption is used to configure the Validator.
+type ValidatorOption func(*Validator)
+
+// NewValidator returns a new Validator configured with the given options.
+func NewValidator(opts ...ValidatorOption) (*Validator, error) {
+	v := &Validator{}
+	for _, f := range opts {
+		f(v)
+	}
+	return v, v.isValid()
+}
+
+func (v *Validator) isValid() error {
+	if v.crdGetter == nil {
+		return xperrors.New("CRDGetter is required")
+	}
+	return nil
+}
+
+// WithCRDGetter returns a ValidatorOption that configures the Validator to use
+// the given CRDGetter to retrieve the CRDs it needs.
+func WithCRDGetter(c CRDGetter) ValidatorOption {
+	return func(v *Validator) {
+		v.crdGetter = c
+	}
+}
+
+// WithCRDGetterFromMap returns a ValidatorOption that configures the Validator
+// to use the given map as a CRDGetter.
+func WithCRDGetterFromMap(m map[schema.GroupKind]apiextensions.CustomResourceDefinition) ValidatorOption {
+	return WithCRDGetter(crdGetterMap(m))
+}
+
+type crdGetterMap map[schema.GroupKind]apiextensions.CustomResourceDefinition
+
+func (c crdGetterMap) Get(_ context.Context, gk schema.GroupKind) (*apiextensions.CustomResourceDefinition, error) {
+	if crd, ok := c[gk]; ok {
+		return &crd, nil
+	}
+	return nil, kerrors.NewNotFound(schema.GroupResource{Group: gk.Group, Resource: "CustomResourceDefinition"}, gk.String())
+}
+
+// Validate the supplied resource against the schema of its CRD. It returns
+// errors for fields that aren't declared in the schema, invalid object
+// metadata, and values that violate the schema. CEL validation rules aren't
+// evaluated; it returns a warning if the schema has any. It returns a warning, and skips validation, if the
+// resource's CRD or its schema is not found.
+func (v *Validator) Validate(ctx context.Context, obj runtime.Object) (warns []string, errs field.ErrorList) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, append(errs, field.InternalError(nil, err))
+	}
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	crd, err := v.crdGetter.Get(ctx, gvk.GroupKind())
+	if kerrors.IsNotFound(err) {
+		return append(warns, fmt.Sprintf(errFmtNoCRD, gvk.GroupKind())), nil
+	}
+	if err != nil {
+		return nil, append(errs, field.InternalError(nil, err))
+	}
+
+	if !hasVersion(crd, gvk.Version) {
+		return nil, append(errs, field.NotSupported(field.NewPath("apiVersion"), obj.GetObjectKind().GroupVersionKind().GroupVersion().String(), versions(crd)))
+	}
+
+	props := getSchemaForVersion(crd, gvk.Version)
+	if props == nil {
+		return append(warns, fmt.Sprintf(errFmtNoSchema, gvk)), nil
+	}
+
+	s, err := structuralschema.NewStructural(props)
+	if err != nil {
+		return nil, append(errs, field.InternalError(nil, xperrors.Wrapf(err, errFmtStructural, gvk)))
+	}
+
+	// Pruning removes unknown fields, so we prune a copy.
+	unknown := pruning.PruneWithOptions(runtime.DeepCopyJSON(u), s, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, p := range unknown {
+		errs = append(errs, field.Forbidden(field.NewPath(p), errUnknownField))
+	}
+
+	sv, _, err := validation.NewSchemaValidator(props)
+	if err != nil {
+		return nil, append(errs, field.InternalError(nil, xperrors.Wrapf(err, errFmtSchemaValidator, gvk)))
+	}
+
+	errs = append(errs, objectmeta.Validate(nil, u, s, true)...)
+	errs = append(errs, validateName(u)...)
+	errs = append(errs, validation.ValidateCustomResource(nil, u, sv)...)
+
+	if hasValidationRules(s) {
+		warns = append(warns, fmt.Sprintf(errFmtCELRules, gvk))
+	}
+	return warns, errs
+}
+
+// validateName validates the supplied resource's name and generateName the way
+// the API server validates the names of custom resources.
+func validateName(u map[string]any) field.ErrorList {
+	errs := field.ErrorList{}
+	pth := field.NewPath("metadata")
+	if name, ok := getString(u, "metadata", "name"); ok && name != "" {
+		for _, msg := range apivalidation.NameIsDNSSubdomain(name, false) {
+			errs = append(errs, field.Invalid(pth.Child("name"), name, msg))
+		}
+	}
+	if prefix, ok := getString(u, "metadata", "generateName"); ok && prefix != "" {
+		for _, msg := range apivalidation.NameIsDNSSubdomain(prefix, true) {
+			errs = append(errs, field.Invalid(pth.Child("generateName"), prefix, msg))
+		}
+	}
+	return errs
+}
+
+// hasValidationRules returns true if the supplied schema or any of its
+// children has x-kubernetes-validations rules.
+func hasValidationRules(s *structuralschema.Structural) bool {
+	if s == nil {
+		return false
+	}
+	if len(s.XValidations) > 0 {
+		return true
+	}
+	for k := range s.Properties {
+		p := s.Properties[k]
+		if hasValidationRules(&p) {
+			return true
+		}
+	}
+	if s.AdditionalProperties != nil && hasValidationRules(s.AdditionalProperties.Structural) {
+		return true
+	}
+	return hasValidationRules(s.Items)
+}
+
+func hasVersion(crd *apiextensions.CustomResourceDefinition, version string) bool {
+	if crd.Spec.Version == version {
+		return true
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return true
+		}
+	}
+	return false
+}
+
+func versions(crd *apiextensions.CustomResourceDefinition) []string {
+	vs := make([]string, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		vs = append(vs, schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}.String())
+	}
+	return vs
+}
+
+func getSchemaForVersion(crd *apiextensions.CustomResourceDefinition, version string) *apiextensions.JSONSchemaProps {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			return v.Schema.OpenAPIV3Schema
+		}
+	}
+	if crd.Spec.Validation != nil {
+		return crd.Spec.Validation.OpenAPIV3Schema
+	}
+	return nil
+}
+
+func getString(m map[string]any, fields ...string) (string, bool) {
+	var v any = m
+	for _, f := range fields {
+		o, ok := v.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		v = o[f]
+	}
+	s, ok := v.(string)
+	return s, ok
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidatorValidate(t *testing.T) {
+	crd := apiextensions.CustomResourceDefinition{
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: apiextensions.CustomResourceDefinitionNames{Kind: "Bucket"},
+			Versions: []apiextensions.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &apiextensions.CustomResourceValidation{OpenAPIV3Schema: &apiextensions.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"apiVersion": {Type: "string"},
+						"kind":       {Type: "string"},
+						"metadata":   {Type: "object"},
+						"spec": {
+							Type:     "object",
+							Required: []string{"region"},
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"region": {Type: "string", Pattern: "^[a-z]+-[a-z]+-[0-9]$"},
+								"size":   {Type: "integer", Minimum: ptr.To[float64](1), Maximum: ptr.To[float64](10)},
+								"ratio":  {Type: "number"},
+								"port":   {XIntOrString: true},
+								"acl": {
+									Type: "string",
+									Enum: []apiextensions.JSON{"private", "public"},
+								},
+								"note": {Type: "string", Nullable: true},
+								"tags": {
+									Type: "array",
+									Items: &apiextensions.JSONSchemaPropsOrArray{Schema: &apiextensions.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]apiextensions.JSONSchemaProps{
+											"key": {Type: "string"},
+										},
+									}},
+								},
+								"labels": {
+									Type:                 "object",
+									AdditionalProperties: &apiextensions.JSONSchemaPropsOrBool{Allows: true, Schema: &apiextensions.JSONSchemaProps{Type: "string"}},
+								},
+								"config": {
+									Type:                   "object",
+									XPreserveUnknownFields: ptr.To(true),
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+	ruled := apiextensions.CustomResourceDefinition{
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: apiextensions.CustomResourceDefinitionNames{Kind: "Queue"},
+			Versions: []apiextensions.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &apiextensions.CustomResourceValidation{OpenAPIV3Schema: &apiextensions.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"apiVersion": {Type: "string"},
+						"kind":       {Type: "string"},
+						"metadata":   {Type: "object"},
+						"spec": {
+							Type: "object",
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"depth": {
+									Type:         "integer",
+									XValidations: apiextensions.ValidationRules{{Rule: "self > 0"}},
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+	crds := map[schema.GroupKind]apiextensions.CustomResourceDefinition{
+		{Group: "example.org", Kind: "Bucket"}: crd,
+		{Group: "example.org", Kind: "Queue"}:  ruled,
+	}
+
+	type want struct {
+		warns []string
+		errs  field.ErrorList
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    string
+		want   want
+	}{
+		"Valid": {
+			reason: "A resource that matches its schema should be valid.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket", "labels": {"cool": "true"}},
+				"spec": {
+					"region": "us-east-2",
+					"size": 5,
+					"ratio": 1,
+					"port": "http",
+					"acl": "private",
+					"note": null,
+					"tags": [{"key": "cool"}],
+					"labels": {"cool": "true"},
+					"config": {"anything": {"goes": true}}
+				}
+			}`,
+			want: want{errs: field.ErrorList{}},
+		},
+		"UnknownFields": {
+			reason: "We should return an error for each field that isn't declared in the schema.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket"},
+				"spec": {
+					"region": "us-east-2",
+					"regoin": "us-east-2",
+					"tags": [{"kye": "cool"}]
+				}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.Forbidden(field.NewPath("spec.regoin"), errUnknownField),
+				field.Forbidden(field.NewPath("spec.tags[0].kye"), errUnknownField),
+			}},
+		},
+		"WrongTypes": {
+			reason: "We should return an error for each value of the wrong type.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket"},
+				"spec": {
+					"region": 42,
+					"size": 4.2,
+					"port": true,
+					"tags": "cool",
+					"labels": {"cool": true}
+				}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.TypeInvalid(field.NewPath("spec", "labels", "cool"), "boolean", ""),
+				field.TypeInvalid(field.NewPath("spec", "port"), "boolean", ""),
+				field.TypeInvalid(field.NewPath("spec", "region"), "integer", ""),
+				field.TypeInvalid(field.NewPath("spec", "size"), "number", ""),
+				field.TypeInvalid(field.NewPath("spec", "tags"), "string", ""),
+			}},
+		},
+		"MissingRequiredFieldsAndUnsupportedValues": {
+			reason: "We should return an error for missing required fields and values not in an enum.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket"},
+				"spec": {
+					"acl": "secret"
+				}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "acl"), "secret", []string{`"private"`, `"public"`}),
+				field.Required(field.NewPath("spec", "region"), ""),
+			}},
+		},
+		"ValueValidations": {
+			reason: "We should return an error for each value that doesn't match a pattern or is out of range.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket"},
+				"spec": {
+					"region": "mars",
+					"size": 42
+				}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "region"), "mars", ""),
+				field.Invalid(field.NewPath("spec", "size"), 42, ""),
+			}},
+		},
+		"InvalidMetadata": {
+			reason: "We should return an error for invalid object metadata.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Bucket",
+				"metadata": {"name": "Not_A_Valid_Name"},
+				"spec": {
+					"region": "us-east-2"
+				}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "name"), "Not_A_Valid_Name", ""),
+			}},
+		},
+		"UnsupportedVersion": {
+			reason: "We should return an error if the CRD doesn't have the resource's version.",
+			obj: `{
+				"apiVersion": "example.org/v2",
+				"kind": "Bucket",
+				"metadata": {"name": "cool-bucket"}
+			}`,
+			want: want{errs: field.ErrorList{
+				field.NotSupported(field.NewPath("apiVersion"), "example.org/v2", []string{"example.org/v1"}),
+			}},
+		},
+		"ValidationRules": {
+			reason: "We should return a warning, not an error, if the resource's schema has CEL validation rules.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Queue",
+				"metadata": {"name": "cool-queue"},
+				"spec": {
+					"depth": 0
+				}
+			}`,
+			want: want{warns: []string{"CustomResourceDefinition for example.org/v1, Kind=Queue has x-kubernetes-validations rules - skipping CEL validation"}},
+		},
+		"NoCRD": {
+			reason: "We should return a warning, not an error, if we don't have the resource's CRD.",
+			obj: `{
+				"apiVersion": "example.org/v1",
+				"kind": "Topic",
+				"metadata": {"name": "cool-topic"}
+			}`,
+			want: want{warns: []string{"no CustomResourceDefinition found for Topic.example.org - skipping validation"}},
+		},
+	}
+
+	v, err := NewValidator(WithCRDGetterFromMap(crds))
+	if err != nil {
+		t.Fatalf("NewValidator(...): %s", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			if err := json.Unmarshal([]byte(tc.obj), &u.Object); err != nil {
+				t.Fatalf("json.Unmarshal(...): %s", err)
+			}
+
+			warns, errs := v.Validate(context.Background(), u)
+
+			if diff := cmp.Diff(tc.want.warns, warns); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+			// We only care about the type and path of errors, not their detail
+			// or order.
+			if diff := cmp.Diff(tc.want.errs, errs, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(a, b *field.Error) bool { return a.Field < b.Field }), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want errors, +got errors:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}